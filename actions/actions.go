@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/SUSE/saptune/app"
 	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"io"
 	"os"
 	"strings"
@@ -33,6 +34,14 @@ var StagingArea = "/var/lib/saptune/staging/"
 // StagingSheets is the staging directory of the latest notes
 var StagingSheets = "/var/lib/saptune/staging/latest/"
 
+// StagingBackup is the directory for the versioned backups of the working
+// area files replaced or removed by 'staging release'
+var StagingBackup = "/var/lib/saptune/staging/backup/"
+
+// StagingHistory is the file recording the staging operations (enable,
+// disable, release and rollback), one json object per line
+var StagingHistory = "/var/lib/saptune/staging/history"
+
 // NoteTuningSheets is the working directory of available sap notes
 var NoteTuningSheets = "/var/lib/saptune/working/notes/"
 
@@ -82,6 +91,9 @@ func SelectAction(writer io.Writer, stApp *app.App, saptuneVers string) {
 	// switch off color and highlighting, if Stdout is not a terminal
 	switchOffColor()
 	system.JnotSupportedYet()
+	if system.GetFlagVal("format") == "prometheus" {
+		system.Jcollect(metricsInfo(saptuneVers))
+	}
 
 	// check for test packages
 	if RPMDate != "undef" {
@@ -102,6 +114,8 @@ func SelectAction(writer io.Writer, stApp *app.App, saptuneVers string) {
 		SolutionAction(writer, system.CliArg(2), system.CliArg(3), system.CliArg(4), stApp)
 	case "configure":
 		ConfigureAction(writer, system.CliArg(2), system.CliArgs(3), stApp)
+	case "config":
+		ConfigAction(writer, system.CliArg(2), system.CliArg(3), saptuneVers, stApp)
 	case "refresh":
 		RefreshAction(os.Stdin, writer, system.CliArg(2), stApp)
 	case "revert":
@@ -115,6 +129,10 @@ func SelectAction(writer io.Writer, stApp *app.App, saptuneVers string) {
 		ServiceAction(writer, "status", saptuneVers, stApp)
 	case "verify":
 		VerifyAction(writer, system.CliArg(2), stApp)
+	case "report":
+		ReportAction(writer, system.CliArg(2), saptuneVers, stApp)
+	case "converge":
+		ConvergeAction(writer, system.CliArg(2), stApp)
 	default:
 		PrintHelpAndExit(writer, 1)
 	}
@@ -206,6 +224,95 @@ func VerifyAllParameters(writer io.Writer, tuneApp *app.App, chkApplied bool) {
 	system.Jcollect(result)
 }
 
+// metricsInfo collects the service, staging and version information needed
+// additional to the verify result for the prometheus output
+func metricsInfo(saptuneVers string) system.JMetricsInfo {
+	info := system.JMetricsInfo{
+		Services:       []system.JMetricsServ{},
+		SaptuneVersion: saptuneVers,
+		RPMVersion:     RPMVersion,
+	}
+	for _, serv := range []string{SaptuneService, SapconfService, TunedService} {
+		if !system.IsServiceAvailable(serv) {
+			continue
+		}
+		enabled, _ := system.SystemctlIsEnabled(serv)
+		active, _ := system.SystemctlIsRunning(serv)
+		info.Services = append(info.Services, system.JMetricsServ{Name: serv, Enabled: enabled, Active: active})
+	}
+	info.StagingEnabled = getStagingFromConf()
+	info.StagedNotes, info.StagedSols = listStageNotesAndSols()
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
+	if err == nil {
+		info.TextfileDir = sconf.GetString("PROMETHEUS_TEXTFILE_DIR", "")
+	}
+	return info
+}
+
+// printLintProblems prints the problems found in a Note or Solution
+// definition file and returns the number of problems, which are not only
+// warnings
+func printLintProblems(writer io.Writer, fileName string, problems []txtparser.LintProblem) int {
+	for _, prob := range problems {
+		fmt.Fprintf(writer, "%s\n", prob.String())
+	}
+	errs, warnings := txtparser.SplitLintProblems(problems)
+	switch {
+	case len(problems) == 0:
+		fmt.Fprintf(writer, "%s: no problems found\n", fileName)
+	case len(warnings) == 0:
+		fmt.Fprintf(writer, "%s: %d problem(s) found\n", fileName, len(errs))
+	default:
+		fmt.Fprintf(writer, "%s: %d problem(s) and %d warning(s) found\n", fileName, len(errs), len(warnings))
+	}
+	return len(errs)
+}
+
+// lintValidator returns a validator for system.EditValidateAndCheckFile,
+// which checks the edited temporary file with the given lint function.
+// If problems are found, they are printed and the user is asked, if the
+// file should be edited again. Otherwise the editing is aborted and the
+// changes are discarded. Warnings are printed, but the file is saved
+func lintValidator(reader io.Reader, writer io.Writer, fileName string, lint func(string) ([]txtparser.LintProblem, error)) system.EditValidator {
+	// share the buffered reader between the questions of all editor
+	// sessions
+	bufReader := bufio.NewReader(reader)
+	return func(tmpFile string) (bool, bool) {
+		problems, err := lint(tmpFile)
+		if err != nil {
+			system.ErrorLog("Problems while checking the edited file '%s' - %v", fileName, err)
+			return false, false
+		}
+		errs, warnings := txtparser.SplitLintProblems(problems)
+		for _, warning := range warnings {
+			warning.File = fileName
+			system.WarningLog("%s", warning.String())
+		}
+		if len(errs) == 0 {
+			return true, false
+		}
+		fmt.Fprintf(writer, "\nThe edited file contains %d problem(s) and can not be saved as '%s':\n", len(errs), fileName)
+		for _, prob := range errs {
+			prob.File = fileName
+			fmt.Fprintf(writer, "  %s\n", prob.String())
+		}
+		return false, readYesNo("Do you want to edit the file again? Otherwise your changes will be discarded.", bufReader, writer)
+	}
+}
+
+// chkOverrideFile checks an existing override file after its base
+// definition file was edited and reports the problems found as warnings
+func chkOverrideFile(ovFileName string, lint func() ([]txtparser.LintProblem, error)) {
+	problems, err := lint()
+	if err != nil {
+		system.ErrorLog("Problems while checking the override file '%s' - %v", ovFileName, err)
+		return
+	}
+	for _, prob := range problems {
+		system.WarningLog("%s", prob.String())
+	}
+}
+
 // chkFileName returns the corresponding filename of a given definition file
 // (note or solution)
 // additional it returns a boolean value which is pointing out that
@@ -268,6 +375,26 @@ func getovFile(defName, OverrideTuningSheets string) (string, bool) {
 	return ovFileName, overrideNote
 }
 
+// getovDropInDir returns the name of the override drop-in directory
+// (NOTEID.d) of a Note and if it exists
+func getovDropInDir(noteID string) (string, bool) {
+	dropInDir := fmt.Sprintf("%s%s.d", OverrideTuningSheets, noteID)
+	if fi, err := os.Stat(dropInDir); err != nil || !fi.IsDir() {
+		return dropInDir, false
+	}
+	return dropInDir, true
+}
+
+// getOverrideFiles returns the override file and the drop-in override files
+// of a Note, which exist
+func getOverrideFiles(noteID string) []string {
+	files := []string{}
+	if ovFileName, override := getovFile(noteID, OverrideTuningSheets); override {
+		files = append(files, ovFileName)
+	}
+	return append(files, txtparser.GetOverrideDropIns(OverrideTuningSheets, noteID)...)
+}
+
 // readYesNo asks the user for yes/no answer.
 // "y", "Y", "yes", "YES", and "Yes" following by "enter" count as confirmation
 // "n", "N", "no", "NO", and "No" following by "enter" count as non-confirmation
@@ -278,6 +405,7 @@ func readYesNo(s string, in io.Reader, out io.Writer) bool {
 		response, err := reader.ReadString('\n')
 		if err != nil {
 			system.ErrorExit("Failed to read input: %v", err)
+			return false
 		}
 		response = strings.ToLower(strings.TrimSpace(response))
 		if response == "y" || response == "yes" {
@@ -297,6 +425,16 @@ func renameDefFile(fileName, newFileName string) {
 	}
 }
 
+// deleteDropInDir will delete an override drop-in directory including all
+// drop-in files
+func deleteDropInDir(dirName string) {
+	if err := os.RemoveAll(dirName); err != nil {
+		system.ErrorExit("Failed to remove directory '%s' - %v", dirName, err)
+	} else {
+		system.NoticeLog("Directory '%s' removed successfully", dirName)
+	}
+}
+
 // deleteDefFile will delete a definition file (Note or Solution)
 func deleteDefFile(fileName string) {
 	if err := os.Remove(fileName); err != nil {
@@ -324,7 +462,7 @@ func switchOffColor() {
 
 // PrintHelpAndExit prints the usage and exit
 func PrintHelpAndExit(writer io.Writer, exitStatus int) {
-	if system.GetFlagVal("format") == "json" {
+	if system.IsStructuredOut() {
 		system.JInvalid(exitStatus)
 	}
 	if system.IfdefVers() > 15 {
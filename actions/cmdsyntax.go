@@ -19,21 +19,35 @@ Tune system according to SAP and SUSE notes:
   saptune [--format FORMAT] [--force-color] [--fun] note refresh [NOTEID|applied] ATTENTION: experimental
   saptune [--format FORMAT] [--force-color] [--fun] note verify [--colorscheme SCHEME] [--show-non-compliant] [NOTEID|applied]
   saptune [--format FORMAT] [--force-color] [--fun] note rename NOTEID NEWNOTEID
+  saptune [--format FORMAT] [--force-color] [--fun] note lint NOTEID|FILE
+  saptune [--format FORMAT] [--force-color] [--fun] note override ( set NOTEID [SECTION] KEY VALUE | unset NOTEID [SECTION] KEY | show NOTEID | clear NOTEID )
 Tune system for all notes applicable to your SAP solution:
   saptune [--format FORMAT] [--force-color] [--fun] solution ( list | verify | enabled | applied )
   saptune [--format FORMAT] [--force-color] [--fun] solution ( apply | simulate | customise | create | edit | revert | show | delete ) SOLUTIONNAME
   saptune [--format FORMAT] [--force-color] [--fun] solution ( apply | change ) [--force] SOLUTIONNAME
   saptune [--format FORMAT] [--force-color] [--fun] solution verify [--colorscheme SCHEME] [--show-non-compliant] [SOLUTIONNAME]
   saptune [--format FORMAT] [--force-color] [--fun] solution rename SOLUTIONNAME NEWSOLUTIONNAME
+  saptune [--format FORMAT] [--force-color] [--fun] solution lint [SOLUTIONNAME|FILE]
+  saptune [--format FORMAT] [--force-color] [--fun] solution recommend [--apply]
+  saptune [--format FORMAT] [--force-color] [--fun] solution upgrade-check [--apply]
 Staging control:
    saptune [--format FORMAT] [--force-color] [--fun] staging ( status | enable | disable | is-enabled | list )
    saptune [--format FORMAT] [--force-color] [--fun] staging ( analysis | diff ) [ ( NOTEID | SOLUTIONNAME.sol )... | all ]
    saptune [--format FORMAT] [--force-color] [--fun] staging release [--force|--dry-run] [ ( NOTEID | SOLUTIONNAME.sol )... | all ]
+   saptune [--format FORMAT] [--force-color] [--fun] staging rollback [--force|--dry-run] [ ( NOTEID | SOLUTIONNAME.sol )... | all ]
+   saptune [--format FORMAT] [--force-color] [--fun] staging history [ ( NOTEID | SOLUTIONNAME.sol )... ]
 Config (re-)settings:
   saptune [--format FORMAT] [--force-color] [--fun] configure ( COLOR_SCHEME | SKIP_SYSCTL_FILES | IGNORE_RELOAD | DEBUG | TrentoASDP ) Value
   saptune [--format FORMAT] [--force-color] [--fun] configure ( reset | show )
+  saptune [--format FORMAT] [--force-color] [--fun] configure describe [ VARIABLE... ]
+  saptune [--format FORMAT] [--force-color] [--fun] config export FILE
+  saptune [--format FORMAT] [--force-color] [--fun] config import [--dry-run|--apply] FILE
+  saptune [--format FORMAT] [--force-color] [--fun] config diff FILE
+  saptune [--format FORMAT] [--force-color] [--fun] converge [--dry-run] FILE
 Verify all applied Notes:
   saptune [--format FORMAT] [--force-color] [--fun] verify applied
+Create a compliance report of all enabled Notes:
+  saptune [--format FORMAT] [--force-color] [--fun] report html [--output FILE]
 Refresh all applied Notes:
   saptune [--format FORMAT] [--force-color] [--fun] refresh applied ATTENTION: experimental
 Revert all parameters tuned by the SAP notes or solutions:
@@ -69,21 +83,35 @@ Tune system according to SAP and SUSE notes:
   saptune [--format FORMAT] [--force-color] [--fun] note refresh [NOTEID|applied] ATTENTION: experimental
   saptune [--format FORMAT] [--force-color] [--fun] note verify [--colorscheme SCHEME] [--show-non-compliant] [NOTEID|applied]
   saptune [--format FORMAT] [--force-color] [--fun] note rename NOTEID NEWNOTEID
+  saptune [--format FORMAT] [--force-color] [--fun] note lint NOTEID|FILE
+  saptune [--format FORMAT] [--force-color] [--fun] note override ( set NOTEID [SECTION] KEY VALUE | unset NOTEID [SECTION] KEY | show NOTEID | clear NOTEID )
 Tune system for all notes applicable to your SAP solution:
   saptune [--format FORMAT] [--force-color] [--fun] solution ( list | verify | enabled | applied )
   saptune [--format FORMAT] [--force-color] [--fun] solution ( apply | customise | create | edit | revert | show | delete ) SOLUTIONNAME
   saptune [--format FORMAT] [--force-color] [--fun] solution ( apply | change ) [--force] SOLUTIONNAME
   saptune [--format FORMAT] [--force-color] [--fun] solution verify [--colorscheme SCHEME] [--show-non-compliant] [SOLUTIONNAME]
   saptune [--format FORMAT] [--force-color] [--fun] solution rename SOLUTIONNAME NEWSOLUTIONNAME
+  saptune [--format FORMAT] [--force-color] [--fun] solution lint [SOLUTIONNAME|FILE]
+  saptune [--format FORMAT] [--force-color] [--fun] solution recommend [--apply]
+  saptune [--format FORMAT] [--force-color] [--fun] solution upgrade-check [--apply]
 Staging control:
    saptune [--format FORMAT] [--force-color] [--fun] staging ( status | enable | disable | is-enabled | list )
    saptune [--format FORMAT] [--force-color] [--fun] staging ( analysis | diff ) [ ( NOTEID | SOLUTIONNAME.sol )... | all ]
    saptune [--format FORMAT] [--force-color] [--fun] staging release [--force|--dry-run] [ ( NOTEID | SOLUTIONNAME.sol )... | all ]
+   saptune [--format FORMAT] [--force-color] [--fun] staging rollback [--force|--dry-run] [ ( NOTEID | SOLUTIONNAME.sol )... | all ]
+   saptune [--format FORMAT] [--force-color] [--fun] staging history [ ( NOTEID | SOLUTIONNAME.sol )... ]
 Config (re-)settings:
   saptune [--format FORMAT] [--force-color] [--fun] configure ( COLOR_SCHEME | SKIP_SYSCTL_FILES | IGNORE_RELOAD | DEBUG | TrentoASDP ) Value
   saptune [--format FORMAT] [--force-color] [--fun] configure ( reset | show )
+  saptune [--format FORMAT] [--force-color] [--fun] configure describe [ VARIABLE... ]
+  saptune [--format FORMAT] [--force-color] [--fun] config export FILE
+  saptune [--format FORMAT] [--force-color] [--fun] config import [--dry-run|--apply] FILE
+  saptune [--format FORMAT] [--force-color] [--fun] config diff FILE
+  saptune [--format FORMAT] [--force-color] [--fun] converge [--dry-run] FILE
 Verify all applied Notes:
   saptune [--format FORMAT] [--force-color] [--fun] verify applied
+Create a compliance report of all enabled Notes:
+  saptune [--format FORMAT] [--force-color] [--fun] report html [--output FILE]
 Refresh all applied Notes:
   saptune [--format FORMAT] [--force-color] [--fun] refresh applied ATTENTION: experimental
 Revert all parameters tuned by the SAP notes or solutions:
@@ -0,0 +1,608 @@
+package actions
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/sap/solution"
+	"github.com/SUSE/saptune/system"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// configArchiveVersion is the version of the archive format written by
+// 'saptune config export'
+const configArchiveVersion = 1
+
+// configManifestName is the name of the manifest inside the archive
+const configManifestName = "manifest.json"
+
+// configValuesName is the name of the file inside the archive, which
+// contains the effective parameter values of the enabled Notes in the
+// format of 'saptune --format json verify applied'
+const configValuesName = "values.json"
+
+// ConfigBackupDir is the directory for the backups of the saptune
+// configuration created by 'saptune config import'
+var ConfigBackupDir = "/var/lib/saptune/config/"
+
+// configArchiveDirs are the directories saved in the archive together with
+// their directory names inside the archive
+var configArchiveDirs = func() map[string]string {
+	return map[string]string{"override": OverrideTuningSheets, "extra": ExtraTuningSheets}
+}
+
+// configManifest describes the content of an archive created by
+// 'saptune config export'
+type configManifest struct {
+	Version         int               `json:"archive version"`
+	Created         string            `json:"created"`
+	Hostname        string            `json:"hostname"`
+	Arch            string            `json:"architecture"`
+	OSName          string            `json:"os name"`
+	OSRelease       string            `json:"os release"`
+	SaptuneVersion  string            `json:"saptune version"`
+	RPMVersion      string            `json:"package version"`
+	Solutions       []string          `json:"enabled Solutions"`
+	Notes           []string          `json:"enabled Notes"`
+	NoteApplyOrder  []string          `json:"Note apply order"`
+	ConfigValues    map[string]string `json:"configure values"`
+	Overrides       []string          `json:"override files"`
+	ExtraNotes      []string          `json:"extra Notes"`
+	CustomSolutions []string          `json:"custom Solutions"`
+}
+
+// configArchive is the content of an archive created by
+// 'saptune config export'
+type configArchive struct {
+	manifest configManifest
+	files    map[string][]byte
+	values   *system.JPNotes
+}
+
+// ConfigAction exports, imports or compares the complete saptune
+// configuration
+func ConfigAction(writer io.Writer, actionName, fileName, saptuneVers string, tuneApp *app.App) {
+	if fileName == "" {
+		PrintHelpAndExit(writer, 1)
+	}
+	switch actionName {
+	case "export":
+		ConfigActionExport(writer, fileName, saptuneVers, tuneApp)
+	case "import":
+		ConfigActionImport(writer, fileName, saptuneVers, tuneApp)
+	case "diff":
+		ConfigActionDiff(writer, fileName, tuneApp)
+	default:
+		PrintHelpAndExit(writer, 1)
+	}
+}
+
+// ConfigActionExport writes the saptune configuration (enabled solution and
+// notes, note apply order, configure values, override files, extra notes and
+// custom solutions) together with a manifest and the effective parameter
+// values to a compressed tar archive
+func ConfigActionExport(writer io.Writer, fileName, saptuneVers string, tuneApp *app.App) {
+	manifest, err := exportConfig(fileName, saptuneVers, tuneApp)
+	if err != nil {
+		system.ErrorExit("Failed to export the saptune configuration to '%s': %v", fileName, err)
+	}
+	fmt.Fprintf(writer, "saptune configuration exported to '%s'.\n\n", fileName)
+	printConfigManifest(writer, manifest)
+}
+
+// ConfigActionImport installs the saptune configuration from an archive
+// created by 'saptune config export' after checking the compatibility with
+// this host. The current configuration is saved to ConfigBackupDir before
+// and restored, if the import fails.
+// Without '--apply' the import is refused, if it changes the override or
+// extra files of enabled or applied Notes or Solutions.
+// With '--dry-run' only the changes are shown, with '--apply' the current
+// tuning is reverted and the enabled solution and notes of the archive are
+// applied afterwards
+func ConfigActionImport(writer io.Writer, fileName, saptuneVers string, tuneApp *app.App) {
+	archive, err := readConfigArchive(fileName)
+	if err != nil {
+		system.ErrorExit("Failed to read the saptune configuration archive '%s': %v", fileName, err)
+	}
+	printConfigManifest(writer, archive.manifest)
+	if errs := checkConfigCompat(archive, saptuneVers, tuneApp); len(errs) != 0 {
+		for _, cerr := range errs {
+			system.ErrorLog("%v", cerr)
+		}
+		system.ErrorExit("The saptune configuration archive '%s' is not compatible with this host, nothing imported.", fileName, 1)
+	}
+	if system.IsFlagSet("dryrun") {
+		printConfigImportChanges(writer, archive)
+		return
+	}
+	if !system.IsFlagSet("apply") {
+		inUse, err := importAffectsTuning(archive, tuneApp)
+		if err != nil {
+			system.ErrorExit("Failed to read the current saptune configuration: %v", err)
+		}
+		if len(inUse) != 0 {
+			system.ErrorExit("The import would change the override or extra files of the enabled or applied %s, nothing imported.\nPlease revert them first or use 'saptune config import --apply %s' to revert the current tuning and apply the imported configuration.", strings.Join(inUse, ", "), fileName)
+		}
+	}
+
+	backup := path.Join(ConfigBackupDir, fmt.Sprintf("saptune_config_%s.tar.gz", time.Now().Format("20060102150405")))
+	if _, err := exportConfig(backup, saptuneVers, tuneApp); err != nil {
+		system.ErrorExit("Failed to save the current saptune configuration to '%s', nothing imported: %v", backup, err)
+	}
+	system.NoticeLog("Current saptune configuration saved to '%s'", backup)
+	if system.IsFlagSet("apply") {
+		if err := tuneApp.RevertAll(true); err != nil {
+			system.ErrorExit("Failed to revert the current tuning: %v", err)
+		}
+	}
+	if err := installConfig(archive); err != nil {
+		if rerr := restoreConfig(backup, tuneApp, system.IsFlagSet("apply")); rerr != nil {
+			system.ErrorExit("Failed to import the saptune configuration: %v\nFailed to restore the previous configuration from '%s': %v", err, backup, rerr)
+		}
+		system.ErrorExit("Failed to import the saptune configuration: %v\nThe previous configuration has been restored from '%s'.", err, backup)
+	}
+	fmt.Fprintf(writer, "\nsaptune configuration imported from '%s'.\nThe previous configuration is saved in '%s'.\n", fileName, backup)
+	if !system.IsFlagSet("apply") {
+		if len(archive.manifest.Solutions) != 0 || len(archive.manifest.Notes) != 0 {
+			fmt.Fprintf(writer, "The enabled Solutions and Notes of the archive are not applied. Run 'saptune config import --apply %s' to apply them.\n", fileName)
+		}
+		return
+	}
+	refreshTuningObjects(tuneApp)
+	if err := tuneApp.TuneConfiguration(archive.manifest.Solutions, archive.manifest.Notes, archive.manifest.NoteApplyOrder); err != nil {
+		system.ErrorExit("Failed to apply the imported configuration: %v", err)
+	}
+	fmt.Fprintf(writer, "The enabled Solutions and Notes of the archive have been applied successfully.\n")
+	rememberMessage(writer)
+}
+
+// exportConfig writes the archive with the current saptune configuration
+func exportConfig(fileName, saptuneVers string, tuneApp *app.App) (configManifest, error) {
+	manifest := configManifest{
+		Version:        configArchiveVersion,
+		Created:        time.Now().Format("2006-01-02 15:04:05"),
+		Arch:           runtime.GOARCH,
+		OSName:         system.GetOsName(),
+		OSRelease:      system.GetOsVers(),
+		SaptuneVersion: saptuneVers,
+		RPMVersion:     RPMVersion,
+		Solutions:      append([]string{}, tuneApp.TuneForSolutions...),
+		Notes:          append([]string{}, tuneApp.TuneForNotes...),
+		NoteApplyOrder: append([]string{}, tuneApp.NoteApplyOrder...),
+		ConfigValues:   make(map[string]string),
+	}
+	manifest.Hostname, _ = os.Hostname()
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
+	if err != nil {
+		return manifest, err
+	}
+	for _, key := range configImportKeys() {
+		manifest.ConfigValues[key] = sconf.GetString(key, "")
+	}
+	files, err := collectConfigFiles()
+	if err != nil {
+		return manifest, err
+	}
+	for _, name := range sortedFileNames(files) {
+		switch {
+		case strings.HasPrefix(name, "override/"):
+			manifest.Overrides = append(manifest.Overrides, strings.TrimPrefix(name, "override/"))
+		case strings.HasPrefix(name, "extra/") && strings.HasSuffix(name, ".sol"):
+			manifest.CustomSolutions = append(manifest.CustomSolutions, strings.TrimSuffix(path.Base(name), ".sol"))
+		case strings.HasPrefix(name, "extra/") && strings.HasSuffix(name, ".conf"):
+			manifest.ExtraNotes = append(manifest.ExtraNotes, strings.TrimSuffix(path.Base(name), ".conf"))
+		}
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, err
+	}
+	files[configManifestName] = content
+	if values := localParameterValues(tuneApp, nil); values != nil {
+		if content, err = json.MarshalIndent(values, "", "  "); err != nil {
+			return manifest, err
+		}
+		files[configValuesName] = content
+	}
+	return manifest, writeConfigArchive(fileName, files)
+}
+
+// configImportKeys returns the keys of the saptune configuration file,
+// which are part of the archive
+func configImportKeys() []string {
+	return append([]string{"STAGING"}, ChangeKeyList()...)
+}
+
+// collectConfigFiles reads all files of the override and the extra
+// directory. The map key is the file name inside the archive
+func collectConfigFiles() (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for arDir, dir := range configArchiveDirs() {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		err := filepath.Walk(dir, func(fileName string, info os.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(dir, fileName)
+			if err != nil {
+				return err
+			}
+			content, err := os.ReadFile(fileName)
+			if err != nil {
+				return err
+			}
+			files[path.Join(arDir, filepath.ToSlash(rel))] = content
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// writeConfigArchive writes the files to a compressed tar archive
+func writeConfigArchive(fileName string, files map[string][]byte) error {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	now := time.Now()
+	for _, name := range sortedFileNames(files) {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name])), ModTime: now, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gzw.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(fileName), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, buf.Bytes(), 0600)
+}
+
+// readConfigArchive reads the manifest and the files of an archive created
+// by 'saptune config export'
+func readConfigArchive(fileName string) (configArchive, error) {
+	archive := configArchive{files: make(map[string][]byte)}
+	fh, err := os.Open(fileName)
+	if err != nil {
+		return archive, err
+	}
+	defer fh.Close()
+	gzr, err := gzip.NewReader(fh)
+	if err != nil {
+		return archive, err
+	}
+	tr := tar.NewReader(gzr)
+	manifestFound := false
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return archive, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if name != configManifestName && name != configValuesName && !validConfigFileName(name) {
+			return archive, fmt.Errorf("unexpected file '%s' in archive", hdr.Name)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return archive, err
+		}
+		if name == configManifestName {
+			if err := json.Unmarshal(content, &archive.manifest); err != nil {
+				return archive, fmt.Errorf("invalid manifest - %v", err)
+			}
+			manifestFound = true
+			continue
+		}
+		if name == configValuesName {
+			archive.values = &system.JPNotes{}
+			if err := json.Unmarshal(content, archive.values); err != nil {
+				return archive, fmt.Errorf("invalid parameter values - %v", err)
+			}
+			continue
+		}
+		archive.files[name] = content
+	}
+	if !manifestFound {
+		return archive, fmt.Errorf("missing manifest '%s'", configManifestName)
+	}
+	return archive, nil
+}
+
+// validConfigFileName checks, if the file name inside the archive belongs
+// to one of the configuration directories and does not leave it
+func validConfigFileName(name string) bool {
+	if path.IsAbs(name) || strings.HasPrefix(name, "../") {
+		return false
+	}
+	for arDir := range configArchiveDirs() {
+		if strings.HasPrefix(name, arDir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConfigCompat checks, if the configuration of the archive is
+// compatible with this host regarding architecture, OS release, saptune
+// version and the availability of the enabled Solutions and Notes
+func checkConfigCompat(archive configArchive, saptuneVers string, tuneApp *app.App) []error {
+	errs := []error{}
+	mf := archive.manifest
+	if mf.Version < 1 || mf.Version > configArchiveVersion {
+		errs = append(errs, fmt.Errorf("unsupported archive version '%d', supported is up to '%d'", mf.Version, configArchiveVersion))
+	}
+	if mf.Arch != runtime.GOARCH {
+		errs = append(errs, fmt.Errorf("archive was created on architecture '%s', but this host is '%s'", mf.Arch, runtime.GOARCH))
+	}
+	osRel := system.GetOsVers()
+	if osMajorRelease(mf.OSRelease) != osMajorRelease(osRel) {
+		errs = append(errs, fmt.Errorf("archive was created on OS release '%s', but this host is running '%s'", mf.OSRelease, osRel))
+	} else if mf.OSRelease != osRel {
+		system.WarningLog("archive was created on OS release '%s', this host is running '%s'", mf.OSRelease, osRel)
+	}
+	if mf.SaptuneVersion != saptuneVers {
+		errs = append(errs, fmt.Errorf("archive was created for saptune version '%s', but this host is using saptune version '%s'", mf.SaptuneVersion, saptuneVers))
+	} else if mf.RPMVersion != RPMVersion {
+		system.WarningLog("archive was created by saptune package version '%s', this host has installed '%s'", mf.RPMVersion, RPMVersion)
+	}
+	for _, solName := range mf.Solutions {
+		if _, ok := archive.files[path.Join("extra", solName+".sol")]; ok {
+			continue
+		}
+		if _, ok := tuneApp.AllSolutions[solName]; !ok {
+			errs = append(errs, fmt.Errorf("enabled Solution '%s' is not available on this host", solName))
+		}
+	}
+	checked := make(map[string]bool)
+	for _, noteID := range append(append([]string{}, mf.Notes...), mf.NoteApplyOrder...) {
+		if checked[noteID] {
+			continue
+		}
+		checked[noteID] = true
+		if _, ok := archive.files[path.Join("extra", noteID+".conf")]; ok {
+			continue
+		}
+		if _, ok := tuneApp.AllNotes[noteID]; !ok {
+			errs = append(errs, fmt.Errorf("enabled Note '%s' is not available on this host", noteID))
+		}
+	}
+	return errs
+}
+
+// osMajorRelease returns the major part of an OS release (15 for 15-SP5,
+// 16 for 16.0)
+func osMajorRelease(osRel string) string {
+	if fields := strings.FieldsFunc(osRel, func(r rune) bool { return r == '-' || r == '.' }); len(fields) != 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// installConfig replaces the content of the override and the extra
+// directory by the files of the archive and sets the configure values of
+// the archive in the saptune configuration file
+func installConfig(archive configArchive) error {
+	for _, dir := range configArchiveDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for _, entry := range entries {
+			if err := os.RemoveAll(path.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	dirs := configArchiveDirs()
+	for _, name := range sortedFileNames(archive.files) {
+		parts := strings.SplitN(name, "/", 2)
+		dest := path.Join(dirs[parts[0]], parts[1])
+		if err := os.MkdirAll(path.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dest, archive.files[name], 0644); err != nil {
+			return err
+		}
+	}
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, true)
+	if err != nil {
+		return err
+	}
+	for _, key := range configImportKeys() {
+		if val, ok := archive.manifest.ConfigValues[key]; ok {
+			sconf.Set(key, val)
+			if dropIn := sconf.DropInFile(key); dropIn != "" && sconf.GetString(key, "") != val {
+				system.WarningLog("Variable '%s' is set in the drop-in file '%s', which overrides the imported value '%s'", key, dropIn, val)
+			}
+		}
+	}
+	return os.WriteFile(saptuneSysconfig, []byte(sconf.ToText()), 0644)
+}
+
+// restoreConfig installs the configuration saved in the backup archive
+// again after a failed import. If the tuning was reverted for the import,
+// the enabled Solutions and Notes of the backup are applied again
+func restoreConfig(backup string, tuneApp *app.App, reapply bool) error {
+	archive, err := readConfigArchive(backup)
+	if err != nil {
+		return err
+	}
+	if err := installConfig(archive); err != nil {
+		return err
+	}
+	if !reapply {
+		return nil
+	}
+	refreshTuningObjects(tuneApp)
+	return tuneApp.TuneConfiguration(archive.manifest.Solutions, archive.manifest.Notes, archive.manifest.NoteApplyOrder)
+}
+
+// refreshTuningObjects reads the Notes and Solutions again to include
+// changed extra Notes and custom Solutions
+func refreshTuningObjects(tuneApp *app.App) {
+	solution.Refresh()
+	tuneApp.AllNotes = note.GetTuningOptions(NoteTuningSheets, ExtraTuningSheets)
+	tuneApp.AllSolutions = solution.AllSolutions[solutionSelector]
+}
+
+// importAffectsTuning returns the enabled or applied Notes and Solutions,
+// whose override or extra files would be added, changed or removed by the
+// import of the archive
+func importAffectsTuning(archive configArchive, tuneApp *app.App) ([]string, error) {
+	current, err := collectConfigFiles()
+	if err != nil {
+		return nil, err
+	}
+	changed := []string{}
+	for name, cont := range archive.files {
+		if cur, ok := current[name]; !ok || !bytes.Equal(cur, cont) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range current {
+		if _, ok := archive.files[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	enabled := make(map[string]bool)
+	for _, noteID := range append(append(tuneApp.GetSortedSolutionEnabledNotes(), tuneApp.TuneForNotes...), tuneApp.NoteApplyOrder...) {
+		enabled[noteID] = true
+	}
+	inUse := []string{}
+	found := make(map[string]bool)
+	for _, name := range changed {
+		// override/ID, override/ID.d/FILE, extra/ID.conf or extra/SOL.sol
+		parts := strings.SplitN(name, "/", 3)
+		obj := strings.TrimSuffix(parts[1], ".d")
+		used := ""
+		if strings.HasSuffix(obj, ".sol") {
+			solName := strings.TrimSuffix(obj, ".sol")
+			if tuneApp.IsSolutionEnabled(solName) {
+				used = "Solution '" + solName + "'"
+			}
+		} else {
+			noteID := strings.TrimSuffix(obj, ".conf")
+			_, applied := tuneApp.IsNoteApplied(noteID)
+			if applied || enabled[noteID] {
+				used = "Note '" + noteID + "'"
+			}
+		}
+		if used != "" && !found[used] {
+			found[used] = true
+			inUse = append(inUse, used)
+		}
+	}
+	sort.Strings(inUse)
+	return inUse, nil
+}
+
+// printConfigManifest prints the content of the manifest
+func printConfigManifest(writer io.Writer, mf configManifest) {
+	format := "    %-19s %s\n"
+	fmt.Fprintf(writer, "saptune configuration archive (version %d):\n", mf.Version)
+	fmt.Fprintf(writer, format, "created:", fmt.Sprintf("%s on host '%s'", mf.Created, mf.Hostname))
+	fmt.Fprintf(writer, format, "architecture:", mf.Arch)
+	fmt.Fprintf(writer, format, "OS release:", strings.TrimSpace(mf.OSName+" "+mf.OSRelease))
+	fmt.Fprintf(writer, format, "saptune version:", fmt.Sprintf("%s (package %s)", mf.SaptuneVersion, mf.RPMVersion))
+	fmt.Fprintf(writer, format, "enabled Solutions:", strings.Join(mf.Solutions, " "))
+	fmt.Fprintf(writer, format, "enabled Notes:", strings.Join(mf.Notes, " "))
+	fmt.Fprintf(writer, format, "Note apply order:", strings.Join(mf.NoteApplyOrder, " "))
+	fmt.Fprintf(writer, format, "override files:", strings.Join(mf.Overrides, " "))
+	fmt.Fprintf(writer, format, "extra Notes:", strings.Join(mf.ExtraNotes, " "))
+	fmt.Fprintf(writer, format, "custom Solutions:", strings.Join(mf.CustomSolutions, " "))
+	keys := make([]string, 0, len(mf.ConfigValues))
+	for key := range mf.ConfigValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(writer, "    configure values:\n")
+	for _, key := range keys {
+		fmt.Fprintf(writer, "        %s=\"%s\"\n", key, mf.ConfigValues[key])
+	}
+}
+
+// printConfigImportChanges prints the changes an import of the archive
+// would do on this host
+func printConfigImportChanges(writer io.Writer, archive configArchive) {
+	current, err := collectConfigFiles()
+	if err != nil {
+		system.ErrorExit("Failed to read the current saptune configuration: %v", err)
+	}
+	dirs := configArchiveDirs()
+	fileName := func(name string) string {
+		parts := strings.SplitN(name, "/", 2)
+		return path.Join(dirs[parts[0]], parts[1])
+	}
+	fmt.Fprintf(writer, "\nDry run - the import would do the following changes:\n")
+	changes := 0
+	for _, name := range sortedFileNames(archive.files) {
+		cont, ok := current[name]
+		switch {
+		case !ok:
+			fmt.Fprintf(writer, "    add file     %s\n", fileName(name))
+		case !bytes.Equal(cont, archive.files[name]):
+			fmt.Fprintf(writer, "    replace file %s\n", fileName(name))
+		default:
+			continue
+		}
+		changes++
+	}
+	for _, name := range sortedFileNames(current) {
+		if _, ok := archive.files[name]; !ok {
+			fmt.Fprintf(writer, "    remove file  %s\n", fileName(name))
+			changes++
+		}
+	}
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
+	if err != nil {
+		system.ErrorExit("Unable to read file '%s': '%v'\n", saptuneSysconfig, err, 128)
+	}
+	for _, key := range configImportKeys() {
+		if val, ok := archive.manifest.ConfigValues[key]; ok && val != sconf.GetString(key, "") {
+			fmt.Fprintf(writer, "    set          %s=\"%s\" (currently \"%s\")\n", key, val, sconf.GetString(key, ""))
+			changes++
+		}
+	}
+	if changes == 0 {
+		fmt.Fprintf(writer, "    none, the configuration files and values are identical\n")
+	}
+}
+
+// sortedFileNames returns the sorted file names of a file map
+func sortedFileNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
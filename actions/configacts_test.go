@@ -0,0 +1,180 @@
+package actions
+
+import (
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/system"
+	"os"
+	"path"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestConfigExportImport(t *testing.T) {
+	tstDir := "/tmp/saptune_config_test"
+	os.RemoveAll(tstDir)
+	defer os.RemoveAll(tstDir)
+	oldExtraTuningSheets := ExtraTuningSheets
+	defer func() { ExtraTuningSheets = oldExtraTuningSheets }()
+	oldOverrideTuningSheets := OverrideTuningSheets
+	defer func() { OverrideTuningSheets = oldOverrideTuningSheets }()
+	oldSaptuneSysconfig := saptuneSysconfig
+	defer func() { saptuneSysconfig = oldSaptuneSysconfig }()
+
+	// reference host
+	OverrideTuningSheets = path.Join(tstDir, "ref/override") + "/"
+	ExtraTuningSheets = path.Join(tstDir, "ref/extra") + "/"
+	saptuneSysconfig = path.Join(tstDir, "ref/saptune")
+	refFiles := map[string]string{
+		"override/simpleNote":            "[sysctl]\nvm.swappiness = 20\n",
+		"override/simpleNote.d/tst.conf": "[sysctl]\nvm.dirty_ratio = 10\n",
+		"extra/myNote.conf":              "# myNote\n[sysctl]\nkernel.shmmni = 32768\n",
+		"extra/MYSOL.sol":                "[version]\n[ArchX86]\nmyNote\n",
+	}
+	for name, content := range refFiles {
+		fileName := path.Join(tstDir, "ref", name)
+		if err := os.MkdirAll(path.Dir(fileName), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := system.CopyFile(path.Join(TstFilesInGOPATH, "etc/sysconfig/saptune"), saptuneSysconfig); err != nil {
+		t.Fatal(err)
+	}
+	writeConfigEntry("COLOR_SCHEME", "full-blue-zebra")
+
+	archiveFile := path.Join(tstDir, "config.tar.gz")
+	manifest, err := exportConfig(archiveFile, "3", tApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := readConfigArchive(archiveFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(archive.manifest, manifest) {
+		t.Errorf("manifest differs, got '%+v', expected '%+v'", archive.manifest, manifest)
+	}
+	if len(archive.files) != len(refFiles) {
+		t.Errorf("expected '%d' files, got '%v'", len(refFiles), sortedFileNames(archive.files))
+	}
+	for name, content := range refFiles {
+		if string(archive.files[name]) != content {
+			t.Errorf("file '%s': got '%s', expected '%s'", name, string(archive.files[name]), content)
+		}
+	}
+	if !reflect.DeepEqual(manifest.Overrides, []string{"simpleNote", "simpleNote.d/tst.conf"}) || !reflect.DeepEqual(manifest.ExtraNotes, []string{"myNote"}) || !reflect.DeepEqual(manifest.CustomSolutions, []string{"MYSOL"}) {
+		t.Errorf("wrong file lists in manifest '%+v'", manifest)
+	}
+	if manifest.ConfigValues["COLOR_SCHEME"] != "full-blue-zebra" || manifest.Arch != runtime.GOARCH {
+		t.Errorf("wrong manifest '%+v'", manifest)
+	}
+
+	// compatibility
+	archive.manifest.Solutions = []string{"sol1", "MYSOL"}
+	archive.manifest.Notes = []string{"myNote"}
+	archive.manifest.NoteApplyOrder = []string{"simpleNote", "myNote"}
+	if errs := checkConfigCompat(archive, "3", tApp); len(errs) != 0 {
+		t.Errorf("expected compatible archive, got '%v'", errs)
+	}
+	incompat := configArchive{manifest: archive.manifest, files: archive.files}
+	incompat.manifest.Arch = "s390x"
+	incompat.manifest.Notes = []string{"myNote", "unknownNote"}
+	incompat.manifest.Solutions = []string{"MYSOL", "unknownSol"}
+	if errs := checkConfigCompat(incompat, "2", tApp); len(errs) != 4 {
+		t.Errorf("expected 4 problems, got '%v'", errs)
+	}
+
+	// target host
+	OverrideTuningSheets = path.Join(tstDir, "new/override") + "/"
+	ExtraTuningSheets = path.Join(tstDir, "new/extra") + "/"
+	saptuneSysconfig = path.Join(tstDir, "new/saptune")
+	if err := os.MkdirAll(OverrideTuningSheets, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(OverrideTuningSheets, "oldNote"), []byte("[sysctl]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := system.CopyFile(path.Join(TstFilesInGOPATH, "etc/sysconfig/saptune"), saptuneSysconfig); err != nil {
+		t.Fatal(err)
+	}
+
+	// the import is refused for enabled or applied Notes and Solutions
+	iApp := app.InitialiseApp(path.Join(tstDir, "new"), path.Join(tstDir, "new"), tuningOpts, AllTestSolutions)
+	iApp.TuneForNotes = []string{"oldNote", "900929"}
+	iApp.TuneForSolutions = []string{"sol1"}
+	inUse, err := importAffectsTuning(archive, iApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"Note 'oldNote'", "Note 'simpleNote'"}; !reflect.DeepEqual(inUse, exp) {
+		t.Errorf("got '%v', expected '%v'", inUse, exp)
+	}
+	iApp.TuneForNotes = []string{"900929"}
+	iApp.TuneForSolutions = []string{}
+	if inUse, _ := importAffectsTuning(archive, iApp); len(inUse) != 0 {
+		t.Errorf("expected no enabled Notes or Solutions affected, got '%v'", inUse)
+	}
+
+	if err := installConfig(archive); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path.Join(OverrideTuningSheets, "oldNote")); !os.IsNotExist(err) {
+		t.Error("file 'oldNote' should be removed")
+	}
+	installed, err := collectConfigFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(installed, archive.files) {
+		t.Errorf("installed files '%v' differ from archive '%v'", sortedFileNames(installed), sortedFileNames(archive.files))
+	}
+	if !system.CheckForPattern(saptuneSysconfig, `COLOR_SCHEME="full-blue-zebra"`) {
+		t.Error("COLOR_SCHEME not imported")
+	}
+
+	// after the import the files and configure values are identical
+	for _, diff := range diffConfigArchive(archive, tApp) {
+		if diff.Category != "enabled Solution" && diff.Category != "enabled Note" && diff.Category != "Note apply order" {
+			t.Errorf("unexpected difference '%+v'", diff)
+		}
+	}
+	if err := os.WriteFile(path.Join(ExtraTuningSheets, "myNote.conf"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, diff := range diffConfigArchive(archive, tApp) {
+		if diff.Category == "extra file" && diff.Item == path.Join(ExtraTuningSheets, "myNote.conf") && diff.Local != diff.Reference {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("changed extra file not detected")
+	}
+
+	// restore the previous configuration from the backup
+	if err := restoreConfig(archiveFile, iApp, false); err != nil {
+		t.Fatal(err)
+	}
+	if cont, _ := os.ReadFile(path.Join(ExtraTuningSheets, "myNote.conf")); string(cont) != refFiles["extra/myNote.conf"] {
+		t.Errorf("extra file not restored, got '%s'", string(cont))
+	}
+	if err := restoreConfig(path.Join(tstDir, "missing.tar.gz"), iApp, false); err == nil {
+		t.Error("expected an error for a missing backup")
+	}
+}
+
+func TestValidConfigFileName(t *testing.T) {
+	for name, valid := range map[string]bool{"override/1410736": true, "extra/MYSOL.sol": true, "override/1410736.d/a.conf": true, "../etc/passwd": false, "/etc/passwd": false, "notes/1410736": false} {
+		if validConfigFileName(name) != valid {
+			t.Errorf("'%s': expected '%v'", name, valid)
+		}
+	}
+	for rel, major := range map[string]string{"15-SP5": "15", "16.0": "16", "12": "12", "": ""} {
+		if osMajorRelease(rel) != major {
+			t.Errorf("'%s': got '%s', expected '%s'", rel, osMajorRelease(rel), major)
+		}
+	}
+}
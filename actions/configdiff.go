@@ -0,0 +1,330 @@
+package actions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// exitConfigDiffers is the exit code of 'saptune config diff', if the local
+// configuration differs from the reference
+const exitConfigDiffers = 2
+
+// verifyCommands are the saptune commands, whose json output can be used as
+// reference for 'saptune config diff'
+var verifyCommands = map[string]bool{"verify applied": true, "note verify": true, "solution verify": true}
+
+// ConfigActionDiff compares the local configuration and the effective
+// parameter values with a reference. The reference is either an archive
+// created by 'saptune config export' or the json output of
+// 'saptune --format json verify applied' (or 'note verify' or 'solution
+// verify') of another host. The output of 'note verify' and 'solution
+// verify' only covers some Notes, so only the parameter values of these
+// Notes are compared and the Note apply order is not checked.
+// If differences are found, the command exits with exitConfigDiffers
+func ConfigActionDiff(writer io.Writer, fileName string, tuneApp *app.App) {
+	result := system.JConfigDiff{Reference: fileName, Differences: []system.JConfigDiffEntry{}}
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		system.ErrorExit("Failed to read the reference '%s': %v", fileName, err)
+	}
+	var refValues *system.JPNotes
+	var refNotes []string
+	if bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		// gzip compressed - archive of 'saptune config export'
+		archive, err := readConfigArchive(fileName)
+		if err != nil {
+			system.ErrorExit("Failed to read the saptune configuration archive '%s': %v", fileName, err)
+		}
+		result.RefType = "archive"
+		fmt.Fprintf(writer, "Comparing the local saptune configuration with the configuration archive '%s' (created %s on host '%s').\n", fileName, archive.manifest.Created, archive.manifest.Hostname)
+		result.Differences = diffConfigArchive(archive, tuneApp)
+		refValues = archive.values
+	} else {
+		cmd, verify, err := readVerifyJSON(content)
+		if err != nil {
+			system.ErrorExit("The reference '%s' is neither a saptune configuration archive nor the json output of a saptune verify command: %v", fileName, err)
+		}
+		result.RefType = "verify"
+		fmt.Fprintf(writer, "Comparing the local saptune configuration with the verify result '%s'.\n", fileName)
+		if cmd == "verify applied" {
+			result.Differences = diffStringList("Note apply order", tuneApp.NoteApplyOrder, verify.NotesOrder)
+		} else {
+			// the reference only covers the verified Notes
+			refNotes = verifiedNotes(&verify)
+		}
+		refValues = &verify
+	}
+	if refValues != nil {
+		result.Differences = append(result.Differences, diffParameterValues(localParameterValues(tuneApp, refNotes), refValues)...)
+	} else {
+		system.WarningLog("The reference '%s' contains no parameter values, so only the configuration is compared.", fileName)
+	}
+	result.Identical = len(result.Differences) == 0
+	system.Jcollect(result)
+	if result.Identical {
+		fmt.Fprintf(writer, "\nThe local saptune configuration is identical to the reference.\n")
+		return
+	}
+	printConfigDiff(writer, result.Differences)
+	system.ErrorExit("", exitConfigDiffers)
+}
+
+// readVerifyJSON reads the command and the result of the json output of a
+// saptune verify command
+func readVerifyJSON(content []byte) (string, system.JPNotes, error) {
+	verify := struct {
+		Command string          `json:"command"`
+		Result  *system.JPNotes `json:"result"`
+	}{}
+	if err := json.Unmarshal(content, &verify); err != nil {
+		return "", system.JPNotes{}, err
+	}
+	if !verifyCommands[verify.Command] || verify.Result == nil {
+		return "", system.JPNotes{}, fmt.Errorf("unsupported command '%s', supported are 'verify applied', 'note verify' and 'solution verify'", verify.Command)
+	}
+	return verify.Command, *verify.Result, nil
+}
+
+// verifiedNotes returns the Notes contained in a verify result in the order
+// of their first appearance
+func verifiedNotes(verify *system.JPNotes) []string {
+	notes := []string{}
+	found := make(map[string]bool)
+	for _, line := range verify.Verifications {
+		if !found[line.NoteID] {
+			found[line.NoteID] = true
+			notes = append(notes, line.NoteID)
+		}
+	}
+	return notes
+}
+
+// localParameterValues returns the verify result, which contains the
+// effective parameter values, of the given Notes or of all enabled Notes,
+// if noteIDs is nil
+func localParameterValues(tuneApp *app.App, noteIDs []string) *system.JPNotes {
+	result := system.JPNotes{
+		Verifications: []system.JPNotesLine{},
+		Attentions:    []system.JPNotesRemind{},
+		NotesOrder:    append([]string{}, tuneApp.NoteApplyOrder...),
+	}
+	var unsatisfiedNotes []string
+	var comparisons map[string]map[string]note.FieldComparison
+	var err error
+	if noteIDs == nil {
+		if len(tuneApp.NoteApplyOrder) == 0 {
+			return &result
+		}
+		unsatisfiedNotes, comparisons, err = tuneApp.VerifyAll(false)
+	} else {
+		unsatisfiedNotes, comparisons, err = verifyNotes(tuneApp, noteIDs)
+	}
+	if err != nil {
+		system.WarningLog("Failed to inspect the current parameter values: %v", err)
+		return nil
+	}
+	PrintNoteFields(io.Discard, "NONE", comparisons, true, &result)
+	sysComp := len(unsatisfiedNotes) == 0
+	result.SysCompliance = &sysComp
+	return &result
+}
+
+// verifyNotes verifies the given Notes. Notes not available on the host
+// are skipped, so their parameters are reported as difference
+func verifyNotes(tuneApp *app.App, noteIDs []string) ([]string, map[string]map[string]note.FieldComparison, error) {
+	unsatisfiedNotes := []string{}
+	comparisons := make(map[string]map[string]note.FieldComparison)
+	for _, noteID := range noteIDs {
+		if _, ok := tuneApp.AllNotes[noteID]; !ok {
+			system.WarningLog("Note '%s' of the reference is not available on this host", noteID)
+			continue
+		}
+		conforming, noteComparisons, _, err := tuneApp.VerifyNote(noteID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !conforming {
+			unsatisfiedNotes = append(unsatisfiedNotes, noteID)
+		}
+		comparisons[noteID] = noteComparisons
+	}
+	return unsatisfiedNotes, comparisons, nil
+}
+
+// diffConfigArchive compares the local configuration with the configuration
+// of an archive created by 'saptune config export'
+func diffConfigArchive(archive configArchive, tuneApp *app.App) []system.JConfigDiffEntry {
+	mf := archive.manifest
+	diffs := diffStringSet("enabled Solution", tuneApp.TuneForSolutions, mf.Solutions)
+	diffs = append(diffs, diffStringSet("enabled Note", tuneApp.TuneForNotes, mf.Notes)...)
+	diffs = append(diffs, diffStringList("Note apply order", tuneApp.NoteApplyOrder, mf.NoteApplyOrder)...)
+
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
+	if err != nil {
+		system.ErrorExit("Unable to read file '%s': '%v'\n", saptuneSysconfig, err, 128)
+		return diffs
+	}
+	for _, key := range configImportKeys() {
+		refVal, ok := mf.ConfigValues[key]
+		if locVal := sconf.GetString(key, ""); ok && locVal != refVal {
+			diffs = append(diffs, system.JConfigDiffEntry{Category: "configure value", Item: key, Local: locVal, Reference: refVal})
+		}
+	}
+
+	current, err := collectConfigFiles()
+	if err != nil {
+		system.ErrorExit("Failed to read the current saptune configuration: %v", err)
+		return diffs
+	}
+	dirs := configArchiveDirs()
+	names := sortedFileNames(archive.files)
+	for _, name := range sortedFileNames(current) {
+		if _, ok := archive.files[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		locSum := fileChecksum(current, name)
+		refSum := fileChecksum(archive.files, name)
+		if locSum != refSum {
+			parts := strings.SplitN(name, "/", 2)
+			diffs = append(diffs, system.JConfigDiffEntry{Category: parts[0] + " file", Item: dirs[parts[0]] + parts[1], Local: locSum, Reference: refSum})
+		}
+	}
+	return diffs
+}
+
+// fileChecksum returns the shortened sha256 checksum of a file of a file
+// map or an empty string, if the file does not exist
+func fileChecksum(files map[string][]byte, name string) string {
+	content, ok := files[name]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))[:19]
+}
+
+// diffStringSet reports the entries, which are only available in one of the
+// lists
+func diffStringSet(category string, local, ref []string) []system.JConfigDiffEntry {
+	diffs := []system.JConfigDiffEntry{}
+	inLocal := make(map[string]bool)
+	for _, entry := range local {
+		inLocal[entry] = true
+	}
+	inRef := make(map[string]bool)
+	for _, entry := range ref {
+		inRef[entry] = true
+	}
+	all := append(append([]string{}, local...), ref...)
+	sort.Strings(all)
+	for i, entry := range all {
+		if i > 0 && all[i-1] == entry {
+			continue
+		}
+		if inLocal[entry] != inRef[entry] {
+			diffs = append(diffs, system.JConfigDiffEntry{Category: category, Item: entry, Local: enabledText(inLocal[entry]), Reference: enabledText(inRef[entry])})
+		}
+	}
+	return diffs
+}
+
+// enabledText returns the text used in the diff for an enabled entry
+func enabledText(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return ""
+}
+
+// diffStringList reports a difference of two ordered lists
+func diffStringList(category string, local, ref []string) []system.JConfigDiffEntry {
+	if strings.Join(local, " ") == strings.Join(ref, " ") {
+		return []system.JConfigDiffEntry{}
+	}
+	return []system.JConfigDiffEntry{{Category: category, Item: "order", Local: strings.Join(local, " "), Reference: strings.Join(ref, " ")}}
+}
+
+// diffParameterValues compares the effective parameter values and the
+// override values of the local verify result with the reference
+func diffParameterValues(local, ref *system.JPNotes) []system.JConfigDiffEntry {
+	diffs := []system.JConfigDiffEntry{}
+	if local == nil || ref == nil {
+		return diffs
+	}
+	locLines := make(map[string]system.JPNotesLine)
+	for _, line := range local.Verifications {
+		locLines[line.NoteID+" "+line.Parameter] = line
+	}
+	refLines := make(map[string]system.JPNotesLine)
+	for _, line := range ref.Verifications {
+		refLines[line.NoteID+" "+line.Parameter] = line
+	}
+	keys := make([]string, 0, len(locLines)+len(refLines))
+	for key := range locLines {
+		keys = append(keys, key)
+	}
+	for key := range refLines {
+		if _, ok := locLines[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		locLine, locOK := locLines[key]
+		refLine, refOK := refLines[key]
+		item := strings.Replace(key, " ", ": ", 1)
+		if locOK != refOK {
+			diffs = append(diffs, system.JConfigDiffEntry{Category: "parameter value", Item: item, Local: actValue(locLine, locOK), Reference: actValue(refLine, refOK)})
+			continue
+		}
+		if actValue(locLine, true) != actValue(refLine, true) {
+			diffs = append(diffs, system.JConfigDiffEntry{Category: "parameter value", Item: item, Local: actValue(locLine, true), Reference: actValue(refLine, true)})
+		}
+		if locLine.OverValue != refLine.OverValue {
+			diffs = append(diffs, system.JConfigDiffEntry{Category: "override value", Item: item, Local: locLine.OverValue, Reference: refLine.OverValue})
+		}
+	}
+	return diffs
+}
+
+// actValue returns the actual value of a verify line
+func actValue(line system.JPNotesLine, available bool) string {
+	if !available {
+		return ""
+	}
+	if line.ActValue == nil {
+		return "NA"
+	}
+	return *line.ActValue
+}
+
+// printConfigDiff prints the differences as table
+func printConfigDiff(writer io.Writer, diffs []system.JConfigDiffEntry) {
+	header := system.JConfigDiffEntry{Category: "Category", Item: "Item", Local: "Local", Reference: "Reference"}
+	width := []int{len(header.Category), len(header.Item), len(header.Local)}
+	for _, diff := range diffs {
+		for i, val := range []string{diff.Category, diff.Item, dashIfEmpty(diff.Local)} {
+			if len(val) > width[i] {
+				width[i] = len(val)
+			}
+		}
+	}
+	format := fmt.Sprintf("    %%-%ds  %%-%ds  %%-%ds  %%s\n", width[0], width[1], width[2])
+	fmt.Fprintf(writer, "\n")
+	fmt.Fprintf(writer, format, header.Category, header.Item, header.Local, header.Reference)
+	for _, diff := range diffs {
+		fmt.Fprintf(writer, format, diff.Category, diff.Item, dashIfEmpty(diff.Local), dashIfEmpty(diff.Reference))
+	}
+	fmt.Fprintf(writer, "\n%d difference(s) found.\n", len(diffs))
+}
@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"github.com/SUSE/saptune/system"
+	"reflect"
+	"testing"
+)
+
+func TestDiffStringSetAndList(t *testing.T) {
+	diffs := diffStringSet("enabled Note", []string{"1410736", "2382421"}, []string{"2382421", "1680803"})
+	exp := []system.JConfigDiffEntry{
+		{Category: "enabled Note", Item: "1410736", Local: "enabled", Reference: ""},
+		{Category: "enabled Note", Item: "1680803", Local: "", Reference: "enabled"},
+	}
+	if !reflect.DeepEqual(diffs, exp) {
+		t.Errorf("got '%+v', expected '%+v'", diffs, exp)
+	}
+	if diffs := diffStringSet("enabled Note", []string{"1410736"}, []string{"1410736"}); len(diffs) != 0 {
+		t.Errorf("expected no differences, got '%+v'", diffs)
+	}
+	if diffs := diffStringList("Note apply order", []string{"1410736", "2382421"}, []string{"1410736", "2382421"}); len(diffs) != 0 {
+		t.Errorf("expected no differences, got '%+v'", diffs)
+	}
+	diffs = diffStringList("Note apply order", []string{"1410736", "2382421"}, []string{"2382421", "1410736"})
+	if len(diffs) != 1 || diffs[0].Local != "1410736 2382421" || diffs[0].Reference != "2382421 1410736" {
+		t.Errorf("wrong order difference '%+v'", diffs)
+	}
+}
+
+func TestDiffParameterValues(t *testing.T) {
+	val := func(str string) *string { return &str }
+	local := &system.JPNotes{Verifications: []system.JPNotesLine{
+		{NoteID: "1410736", Parameter: "net.ipv4.tcp_keepalive_time", ActValue: val("300")},
+		{NoteID: "1410736", Parameter: "net.ipv4.tcp_keepalive_intvl", ActValue: val("75")},
+		{NoteID: "2382421", Parameter: "vm.swappiness", ActValue: val("10"), OverValue: "10"},
+	}}
+	ref := &system.JPNotes{Verifications: []system.JPNotesLine{
+		{NoteID: "1410736", Parameter: "net.ipv4.tcp_keepalive_time", ActValue: val("300")},
+		{NoteID: "1410736", Parameter: "net.ipv4.tcp_keepalive_intvl", ActValue: val("60")},
+		{NoteID: "2382421", Parameter: "vm.swappiness", ActValue: val("10")},
+		{NoteID: "2382421", Parameter: "vm.dirty_ratio", ActValue: val("20")},
+	}}
+	exp := []system.JConfigDiffEntry{
+		{Category: "parameter value", Item: "1410736: net.ipv4.tcp_keepalive_intvl", Local: "75", Reference: "60"},
+		{Category: "parameter value", Item: "2382421: vm.dirty_ratio", Local: "", Reference: "20"},
+		{Category: "override value", Item: "2382421: vm.swappiness", Local: "10", Reference: ""},
+	}
+	if diffs := diffParameterValues(local, ref); !reflect.DeepEqual(diffs, exp) {
+		t.Errorf("got '%+v', expected '%+v'", diffs, exp)
+	}
+	if diffs := diffParameterValues(local, local); len(diffs) != 0 {
+		t.Errorf("expected no differences, got '%+v'", diffs)
+	}
+}
+
+func TestReadVerifyJSON(t *testing.T) {
+	content := `{"$schema": "file:///usr/share/saptune/schemas/1.2/saptune_verify_applied.schema.json", "command": "verify applied", "exit code": 0, "result": {"verifications": [{"Note ID": "1410736", "parameter": "net.ipv4.tcp_keepalive_time", "actual value": "300"}], "attentions": [], "Notes enabled": ["1410736"], "system compliance": true}}`
+	cmd, verify, err := readVerifyJSON([]byte(content))
+	if err != nil || cmd != "verify applied" {
+		t.Fatalf("got '%s' - '%v'", cmd, err)
+	}
+	if len(verify.Verifications) != 1 || *verify.Verifications[0].ActValue != "300" || !reflect.DeepEqual(verify.NotesOrder, []string{"1410736"}) {
+		t.Errorf("wrong verify result '%+v'", verify)
+	}
+	if _, _, err := readVerifyJSON([]byte(`{"command": "note list", "result": {}}`)); err == nil {
+		t.Error("expected an error for an unsupported command")
+	}
+	if _, _, err := readVerifyJSON([]byte(`no json`)); err == nil {
+		t.Error("expected an error for invalid json")
+	}
+}
+
+func TestLocalParameterValuesOfNotes(t *testing.T) {
+	ref := &system.JPNotes{Verifications: []system.JPNotesLine{
+		{NoteID: "simpleNote", Parameter: "net.ipv4.ip_local_port_range"},
+		{NoteID: "simpleNote", Parameter: "vm.swappiness"},
+		{NoteID: "hugo", Parameter: "vm.swappiness"},
+	}}
+	if notes := verifiedNotes(ref); !reflect.DeepEqual(notes, []string{"simpleNote", "hugo"}) {
+		t.Errorf("got '%v'", notes)
+	}
+	local := localParameterValues(tApp, []string{"simpleNote", "hugo"})
+	if local == nil {
+		t.Fatal("no local values")
+	}
+	for _, line := range local.Verifications {
+		if line.NoteID != "simpleNote" {
+			t.Errorf("unexpected Note '%s' in the local values", line.NoteID)
+		}
+	}
+	if len(local.Verifications) == 0 {
+		t.Error("missing local values of Note 'simpleNote'")
+	}
+}
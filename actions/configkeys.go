@@ -0,0 +1,341 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"strings"
+)
+
+// ConfigKey describes a variable of the saptune configuration file
+type ConfigKey struct {
+	// Name of the variable
+	Name string
+	// Type of the variable - 'string', 'list' or 'enum'
+	Type string
+	// Values are the allowed values, empty for variables of type
+	// 'string' and 'list'
+	Values []string
+	// Default is the value used, if the variable is not set
+	Default string
+	// Description is the short description of the variable
+	Description string
+	// SideEffect describes what happens additionally, if the
+	// variable is changed by 'saptune configure'
+	SideEffect string
+	// Mandatory variables need to be available in the configuration file
+	Mandatory bool
+	// Changeable variables can be changed by 'saptune configure'
+	Changeable bool
+	// StartCheck variables need a valid value to start saptune
+	StartCheck bool
+	// State variables contain runtime state maintained by saptune, they
+	// are not allowed in the drop-in files of the configuration
+	State bool
+	// check validates and normalises the value of variables, which can
+	// not be checked by the list of allowed values
+	check func(vals []string) (string, error)
+	// setHook is called before a changed value is written to the
+	// configuration file
+	setHook func(val string) error
+	// startHook is called during the start of saptune with the value
+	// found in the configuration file
+	startHook func(val string)
+}
+
+// configKeys describes all variables of the saptune configuration file
+// in the order of the configuration file
+var configKeys = []ConfigKey{
+	{
+		Name:        app.TuneForSolutionsKey,
+		Type:        "list",
+		Description: "Solution enabled for tuning. Maintained by 'saptune solution apply|change|revert'.",
+		Mandatory:   true,
+		State:       true,
+	},
+	{
+		Name:        app.TuneForNotesKey,
+		Type:        "list",
+		Description: "Notes enabled for tuning in addition to the Notes of the enabled solution. Maintained by 'saptune note apply|revert'.",
+		Mandatory:   true,
+		State:       true,
+	},
+	{
+		Name:        app.NoteApplyOrderKey,
+		Type:        "list",
+		Description: "Order in which the enabled Notes are applied. Maintained by saptune.",
+		Mandatory:   true,
+		State:       true,
+	},
+	{
+		Name:        "SAPTUNE_VERSION",
+		Type:        "enum",
+		Values:      []string{"1", "2", "3"},
+		Default:     "3",
+		Description: "Major version of saptune.",
+		Mandatory:   true,
+		State:       true,
+		StartCheck:  true,
+	},
+	{
+		Name:        "STAGING",
+		Type:        "enum",
+		Values:      []string{"true", "false"},
+		Default:     "false",
+		Description: "Enables or disables the staging of the saptune internal Notes and solutions. Maintained by 'saptune staging enable|disable'.",
+		Mandatory:   true,
+		State:       true,
+		StartCheck:  true,
+	},
+	{
+		Name:        "COLOR_SCHEME",
+		Type:        "enum",
+		Values:      []string{"", "full-green-zebra", "cmpl-green-zebra", "full-blue-zebra", "cmpl-blue-zebra", "full-red-noncmpl", "red-noncmpl", "full-yellow-noncmpl", "yellow-noncmpl"},
+		Default:     "",
+		Description: "Default color scheme of the verify output. An empty value resets the color scheme.",
+		Mandatory:   true,
+		Changeable:  true,
+	},
+	{
+		Name:        "SKIP_SYSCTL_FILES",
+		Type:        "list",
+		Default:     "/boot",
+		Description: "Comma-separated list of sysctl config files or directories, which are excluded from the check, if parameters handled by saptune are handled by sysctl as well. Only locations searched by the sysctl command are accepted.",
+		Mandatory:   true,
+		Changeable:  true,
+		check:       checkSkipSysctlFiles,
+	},
+	{
+		Name:        "IGNORE_RELOAD",
+		Type:        "enum",
+		Values:      []string{"yes", "no"},
+		Default:     "no",
+		Description: "Controls the behavior of 'systemctl reload saptune.service' and 'systemctl try-restart saptune.service' during package installation. If set to 'yes' a reload does nothing.",
+		Mandatory:   true,
+		Changeable:  true,
+	},
+	{
+		Name:        "DEBUG",
+		Type:        "enum",
+		Values:      []string{"on", "off"},
+		Default:     "off",
+		Description: "Turns the debug log output on or off. Only turn it on, if instructed by SUSE support to do so.",
+		Changeable:  true,
+	},
+	{
+		Name:        "VERBOSE",
+		Type:        "enum",
+		Values:      []string{"on", "off"},
+		Default:     "on",
+		Description: "Turns the verbose log output on or off.",
+	},
+	{
+		Name:        "ERROR",
+		Type:        "enum",
+		Values:      []string{"on", "off"},
+		Default:     "on",
+		Description: "Turns the error log output on or off.",
+	},
+	{
+		Name:        "TrentoASDP",
+		Type:        "enum",
+		Values:      []string{"300", "600", "900", "1800", "3600", "off"},
+		Default:     "off",
+		Description: "Discovery period (saptune-discovery-period) in seconds of the Trento Agent. If set, saptune checks during start, if the value is still set in the Trento Agent configuration. 'off' disables the check.",
+		SideEffect:  "sets 'saptune-discovery-period' in the Trento Agent configuration file /etc/trento/agent.yaml",
+		Changeable:  true,
+		setHook:     setTrentoASDP,
+		startHook:   checkTrentoASDP,
+	},
+	{
+		Name:        "PROMETHEUS_TEXTFILE_DIR",
+		Type:        "string",
+		Description: "Directory of the textfile collector of the prometheus node_exporter. If set, the verify commands with '--format prometheus' additionally write the metrics to the file 'saptune.prom' in this directory.",
+	},
+	{
+		Name:        "INTEGRITY_PUBKEY",
+		Type:        "string",
+		Description: "Public key (PEM format) used to verify the signature of the checksum manifests of the package area and the staging area. Empty disables the integrity check.",
+	},
+	{
+		Name:        "INTEGRITY_CHECK_APPLY",
+		Type:        "enum",
+		Values:      []string{"yes", "no"},
+		Default:     "no",
+		Description: "If set to 'yes' (and INTEGRITY_PUBKEY is set), the Note and solution definitions of the working area are verified against the signed manifests before they are applied.",
+	},
+}
+
+// ConfigKeys returns the descriptions of all variables of the saptune
+// configuration file
+func ConfigKeys() []ConfigKey {
+	return configKeys
+}
+
+// ConfigKeyByName returns the description of the variable 'name'
+func ConfigKeyByName(name string) (ConfigKey, bool) {
+	for _, key := range configKeys {
+		if key.Name == name {
+			return key, true
+		}
+	}
+	return ConfigKey{}, false
+}
+
+// CheckValue checks, if the value(s) are valid for the variable and returns
+// the value to write to the configuration file
+func (key ConfigKey) CheckValue(vals []string) (string, error) {
+	if key.check != nil {
+		return key.check(vals)
+	}
+	val := strings.Join(vals, " ")
+	if len(key.Values) == 0 {
+		return val, nil
+	}
+	for _, allowed := range key.Values {
+		if val == allowed {
+			return val, nil
+		}
+	}
+	return val, fmt.Errorf("wrong value '%s' for config variable '%s'. Supported values are %s. Please check", val, key.Name, key.ValuesText())
+}
+
+// ValuesText returns the allowed values of the variable in a readable form
+func (key ConfigKey) ValuesText() string {
+	txt := ""
+	for i, val := range key.Values {
+		switch {
+		case i == 0:
+		case i == len(key.Values)-1:
+			txt = txt + " or "
+		default:
+			txt = txt + ", "
+		}
+		txt = txt + "'" + val + "'"
+	}
+	return txt
+}
+
+// StartHook runs the checks of the variable needed during the start of
+// saptune
+func (key ConfigKey) StartHook(val string) {
+	if key.startHook != nil {
+		key.startHook(val)
+	}
+}
+
+// checkSkipSysctlFiles checks, if the given files are in a location, which
+// is searched by the sysctl command and returns the comma separated list of
+// the files
+func checkSkipSysctlFiles(vals []string) (string, error) {
+	confVals := vals
+	if len(vals) == 1 && strings.Contains(vals[0], ",") {
+		confVals = strings.Split(vals[0], ",")
+	}
+	files := []string{}
+	for _, file := range confVals {
+		file = strings.TrimSpace(strings.TrimSuffix(file, ","))
+		if file == "" {
+			continue
+		}
+		if !system.IsValidSysctlLocations(file) {
+			return "", fmt.Errorf("wrong value '%s' for config variable 'SKIP_SYSCTL_FILES' provided. sysctl command will not search in this locaction. Exiting without changing saptune configuration. Please check", file)
+		}
+		files = append(files, file)
+	}
+	return strings.Join(files, ", "), nil
+}
+
+// setTrentoASDP sets the saptune-discovery-period in the Trento Agent
+// configuration file
+func setTrentoASDP(val string) error {
+	return system.CheckAndSetTrento("TrentoASDP", val, true)
+}
+
+// checkTrentoASDP checks, if the saptune-discovery-period is still set in
+// the Trento Agent configuration file
+func checkTrentoASDP(val string) {
+	_ = system.CheckAndSetTrento("TrentoASDP", val, false)
+}
+
+// mandatoryConfigKeys returns the names of the variables, which need to be
+// available in the saptune configuration file
+func mandatoryConfigKeys() []string {
+	keys := []string{}
+	for _, key := range configKeys {
+		if key.Mandatory {
+			keys = append(keys, key.Name)
+		}
+	}
+	return keys
+}
+
+// stateConfigKeys returns the names of the variables, which contain runtime
+// state maintained by saptune
+func stateConfigKeys() []string {
+	keys := []string{}
+	for _, key := range configKeys {
+		if key.State {
+			keys = append(keys, key.Name)
+		}
+	}
+	return keys
+}
+
+// ParseSaptuneConfig reads the saptune configuration file and merges the
+// admin owned drop-in files of SaptuneConfigDropInDir over it. The runtime
+// state variables are only read from the saptune configuration file.
+func ParseSaptuneConfig(fileName string, autoCreate bool) (*txtparser.Sysconfig, error) {
+	return txtparser.ParseSysconfigFileWithDropIns(fileName, system.SaptuneConfigDropInDir, autoCreate, stateConfigKeys())
+}
+
+// changeableConfigKeys returns the names of the variables, which can be
+// changed by 'saptune configure'
+func changeableConfigKeys() []string {
+	keys := []string{}
+	for _, key := range configKeys {
+		if key.Changeable {
+			keys = append(keys, key.Name)
+		}
+	}
+	return keys
+}
+
+// configKeyInfo returns the description of a variable together with its
+// current value and the file, which supplied the value, for the json output
+func configKeyInfo(key ConfigKey, sconf *txtparser.Sysconfig) system.JConfigKey {
+	info := system.JConfigKey{
+		Name:        key.Name,
+		Type:        key.Type,
+		Values:      append([]string{}, key.Values...),
+		Default:     key.Default,
+		Mandatory:   key.Mandatory,
+		Changeable:  key.Changeable,
+		DropIn:      !key.State,
+		Description: key.Description,
+		SideEffect:  key.SideEffect,
+	}
+	if sconf.IsKeyAvail(key.Name) {
+		val := sconf.GetString(key.Name, "")
+		info.Value = &val
+		info.Source = saptuneSysconfig
+		if dropIn := sconf.DropInFile(key.Name); dropIn != "" {
+			info.Source = dropIn
+		}
+	}
+	return info
+}
+
+// configTemplateFromKeys creates the content of a saptune configuration
+// file with the default values of the mandatory variables
+func configTemplateFromKeys() string {
+	txt := "## Path:           SAP/System Tuning/General\n## Description:    Global settings for saptune - the comprehensive optimisation management utility for SAP solutions\n## ServiceRestart: saptune\n# _STCV1_\n"
+	for _, key := range configKeys {
+		if !key.Mandatory {
+			continue
+		}
+		txt = txt + fmt.Sprintf("\n## Type:    %s\n## Default: \"%s\"\n#\n# %s\n%s=\"%s\"\n", key.Type, key.Default, key.Description, key.Name, key.Default)
+	}
+	return txt
+}
@@ -0,0 +1,192 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestConfigKeyLists(t *testing.T) {
+	mand := []string{"TUNE_FOR_SOLUTIONS", "TUNE_FOR_NOTES", "NOTE_APPLY_ORDER", "SAPTUNE_VERSION", "STAGING", "COLOR_SCHEME", "SKIP_SYSCTL_FILES", "IGNORE_RELOAD"}
+	if !reflect.DeepEqual(MandKeyList(), mand) {
+		t.Errorf("got '%v', expected '%v'", MandKeyList(), mand)
+	}
+	change := []string{"COLOR_SCHEME", "SKIP_SYSCTL_FILES", "IGNORE_RELOAD", "DEBUG", "TrentoASDP"}
+	if !reflect.DeepEqual(ChangeKeyList(), change) {
+		t.Errorf("got '%v', expected '%v'", ChangeKeyList(), change)
+	}
+	// all keys of the configuration file template need a description
+	template := path.Join(TstFilesInGOPATH, "../ospackage/etc/sysconfig/saptune")
+	sconf, err := txtparser.ParseSysconfigFile(template, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range regexp.MustCompile(`(?m)^(\w+)=`).FindAllStringSubmatch(string(content), -1) {
+		key, ok := ConfigKeyByName(name[1])
+		if !ok {
+			t.Errorf("missing description of variable '%s'", name[1])
+			continue
+		}
+		if sconf.GetString(name[1], "") != key.Default {
+			t.Errorf("variable '%s': default '%s' differs from template value '%s'", name[1], key.Default, sconf.GetString(name[1], ""))
+		}
+	}
+	if _, ok := ConfigKeyByName("hugo"); ok {
+		t.Error("unexpected description of variable 'hugo'")
+	}
+}
+
+func TestConfigKeyCheckValue(t *testing.T) {
+	key, _ := ConfigKeyByName("STAGING")
+	if val, err := key.CheckValue([]string{"true"}); err != nil || val != "true" {
+		t.Errorf("got '%s', '%v'", val, err)
+	}
+	if _, err := key.CheckValue([]string{"hugo"}); err == nil {
+		t.Error("expected an error for value 'hugo'")
+	}
+	if key.ValuesText() != "'true' or 'false'" {
+		t.Errorf("wrong values text '%s'", key.ValuesText())
+	}
+	key, _ = ConfigKeyByName("TrentoASDP")
+	if key.ValuesText() != "'300', '600', '900', '1800', '3600' or 'off'" {
+		t.Errorf("wrong values text '%s'", key.ValuesText())
+	}
+	key, _ = ConfigKeyByName("PROMETHEUS_TEXTFILE_DIR")
+	if val, err := key.CheckValue([]string{"/var/lib/node_exporter"}); err != nil || val != "/var/lib/node_exporter" {
+		t.Errorf("got '%s', '%v'", val, err)
+	}
+	key, _ = ConfigKeyByName("SKIP_SYSCTL_FILES")
+	if val, err := key.CheckValue([]string{""}); err != nil || val != "" {
+		t.Errorf("got '%s', '%v'", val, err)
+	}
+	if _, err := key.CheckValue([]string{"/hugo/sysctl.conf"}); err == nil {
+		t.Error("expected an error for location '/hugo/sysctl.conf'")
+	}
+}
+
+func TestConfigureActionSetShowDescribe(t *testing.T) {
+	oldOSExit := system.OSExit
+	defer func() { system.OSExit = oldOSExit }()
+	system.OSExit = tstosExit
+	oldErrorExitOut := system.ErrorExitOut
+	defer func() { system.ErrorExitOut = oldErrorExitOut }()
+	system.ErrorExitOut = tstErrorExitOut
+	errExitbuffer := bytes.Buffer{}
+	tstwriter = &errExitbuffer
+	oldSaptuneSysconfig := saptuneSysconfig
+	defer func() { saptuneSysconfig = oldSaptuneSysconfig }()
+	saptuneSysconfig = "/tmp/saptune_configure_test"
+	defer os.Remove(saptuneSysconfig)
+	if err := system.CopyFile(path.Join(TstFilesInGOPATH, "etc/sysconfig/saptune"), saptuneSysconfig); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.Buffer{}
+	ConfigureAction(&buffer, "IGNORE_RELOAD", []string{"yes"}, tApp)
+	if !system.CheckForPattern(saptuneSysconfig, `IGNORE_RELOAD="yes"`) {
+		t.Error("IGNORE_RELOAD not set")
+	}
+	tstRetErrorExit = -1
+	ConfigureAction(&buffer, "IGNORE_RELOAD", []string{"hugo"}, tApp)
+	if tstRetErrorExit != 1 || !strings.Contains(errExitbuffer.String(), "wrong value 'hugo' for config variable 'IGNORE_RELOAD'. Supported values are 'yes' or 'no'. Please check.") {
+		t.Errorf("wrong error exit '%d' - '%s'", tstRetErrorExit, errExitbuffer.String())
+	}
+	if !system.CheckForPattern(saptuneSysconfig, `IGNORE_RELOAD="yes"`) {
+		t.Error("IGNORE_RELOAD changed by wrong value")
+	}
+
+	buffer.Reset()
+	ConfigureActionShow(&buffer)
+	txt := buffer.String()
+	if !strings.Contains(txt, "IGNORE_RELOAD            'yes'") || !strings.Contains(txt, "DEBUG                    not set (default 'off')") {
+		t.Errorf("wrong output of 'configure show': '%s'", txt)
+	}
+
+	buffer.Reset()
+	ConfigureActionDescribe(&buffer, []string{"TrentoASDP"})
+	txt = buffer.String()
+	for _, line := range []string{"TrentoASDP\n", "Allowed values: '300', '600', '900', '1800', '3600' or 'off'", "Default:        'off'", "Current value:  not set", "Changeable:     yes, by 'saptune configure TrentoASDP VALUE'", "Side effect:    sets 'saptune-discovery-period'"} {
+		if !strings.Contains(txt, line) {
+			t.Errorf("missing '%s' in output of 'configure describe': '%s'", line, txt)
+		}
+	}
+
+	// drop-in file
+	oldDropInDir := system.SaptuneConfigDropInDir
+	defer func() { system.SaptuneConfigDropInDir = oldDropInDir }()
+	system.SaptuneConfigDropInDir = "/tmp/saptune_configure_test.d"
+	defer os.RemoveAll(system.SaptuneConfigDropInDir)
+	dropIn := path.Join(system.SaptuneConfigDropInDir, "50-admin.conf")
+	if err := os.MkdirAll(system.SaptuneConfigDropInDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dropIn, []byte("DEBUG=\"on\"\nTUNE_FOR_NOTES=\"1410736\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	errExitbuffer.Reset()
+	tstRetErrorExit = -1
+	ConfigureAction(&buffer, "DEBUG", []string{"off"}, tApp)
+	if tstRetErrorExit != 1 || !strings.Contains(errExitbuffer.String(), "Variable 'DEBUG' is set in the drop-in file '"+dropIn+"'") {
+		t.Errorf("wrong error exit '%d' - '%s'", tstRetErrorExit, errExitbuffer.String())
+	}
+	if system.CheckForPattern(saptuneSysconfig, `DEBUG=`) {
+		t.Error("DEBUG written to the saptune configuration file")
+	}
+	buffer.Reset()
+	ConfigureActionShow(&buffer)
+	txt = buffer.String()
+	if !strings.Contains(txt, "DEBUG                    'on' (from drop-in file "+dropIn+")") || !strings.Contains(txt, "TUNE_FOR_NOTES           '1680803 2205917 2684254'\n") {
+		t.Errorf("wrong output of 'configure show': '%s'", txt)
+	}
+
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := ConfigKeyByName("IGNORE_RELOAD")
+	info, err := json.Marshal(configKeyInfo(key, sconf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(info), `"allowed values":["yes","no"],"default":"no","value":"yes","source":"/tmp/saptune_configure_test"`) {
+		t.Errorf("wrong json '%s'", string(info))
+	}
+	key, _ = ConfigKeyByName("VERBOSE")
+	info, _ = json.Marshal(configKeyInfo(key, sconf))
+	if !strings.Contains(string(info), `"value":null,"source":""`) {
+		t.Errorf("wrong json '%s'", string(info))
+	}
+	key, _ = ConfigKeyByName("DEBUG")
+	if jkey := configKeyInfo(key, sconf); jkey.Source != dropIn || *jkey.Value != "on" || !jkey.DropIn {
+		t.Errorf("wrong info '%+v'", jkey)
+	}
+}
+
+func TestConfigTemplateFromKeys(t *testing.T) {
+	fileName := "/tmp/saptune_configure_template"
+	defer os.Remove(fileName)
+	if err := os.WriteFile(fileName, []byte(configTemplateFromKeys()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sconf, err := txtparser.ParseSysconfigFile(fileName, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range MandKeyList() {
+		key, _ := ConfigKeyByName(name)
+		if !sconf.IsKeyAvail(name) || sconf.GetString(name, "") != key.Default {
+			t.Errorf("wrong value '%s' for variable '%s'", sconf.GetString(name, ""), name)
+		}
+	}
+}
@@ -4,134 +4,70 @@ import (
 	"fmt"
 	"github.com/SUSE/saptune/app"
 	"github.com/SUSE/saptune/system"
-	"github.com/SUSE/saptune/txtparser"
 	"io"
 	"os"
 	"strings"
 )
 
-var mandatoryConfigKeys = []string{app.TuneForSolutionsKey, app.TuneForNotesKey, app.NoteApplyOrderKey, "SAPTUNE_VERSION", "STAGING", "COLOR_SCHEME", "SKIP_SYSCTL_FILES", "IGNORE_RELOAD"}
-var changeableConfigKeys = []string{"COLOR_SCHEME", "SKIP_SYSCTL_FILES", "IGNORE_RELOAD", "DEBUG", "TrentoASDP"}
-
 // MandKeyList returns a list of mandatory configuration parameter, which need
 // to be available in the saptune configuration file
 func MandKeyList() []string {
-	return mandatoryConfigKeys
+	return mandatoryConfigKeys()
 }
 
 // ChangeKeyList returns a list of configuration parameter, which can be set
 // or changed by the customer
 func ChangeKeyList() []string {
-	return changeableConfigKeys
+	return changeableConfigKeys()
 }
 
 // ConfigureAction changes entries in the main saptune configuration file
 // Replaces the direct editing of the config file
+// The supported variables and their values are defined in configKeys
 //
 // saptune configure STAGING -- not needed because of 'saptune staging enable'
 func ConfigureAction(writer io.Writer, configEntry string, configVals []string, tuneApp *app.App) {
-	if len(configVals) == 0 && !(configEntry == "reset" || configEntry == "show") {
-		// missing value to be configured
-		PrintHelpAndExit(writer, 1)
-	}
 	switch configEntry {
-	case "COLOR_SCHEME":
-		ConfigureActionSetColorScheme(configVals[0])
-	case "SKIP_SYSCTL_FILES":
-		ConfigureActionSetSkipSysctlFiles(configVals)
-	case "IGNORE_RELOAD":
-		ConfigureActionSetIgnoreReload(configVals[0])
-	case "DEBUG":
-		ConfigureActionSetDebug(configVals[0])
-	case "TrentoASDP":
-		ConfigureActionSetTrentoASDP(configVals[0])
 	case "reset":
 		ConfigureActionReset(os.Stdin, writer, tuneApp)
 	case "show":
 		ConfigureActionShow(writer)
+	case "describe":
+		ConfigureActionDescribe(writer, configVals)
 	default:
-		PrintHelpAndExit(writer, 1)
-	}
-}
-
-// ConfigureActionSetColorScheme sets the color scheme
-func ConfigureActionSetColorScheme(configVal string) {
-	switch configVal {
-	case "", "full-green-zebra", "cmpl-green-zebra", "full-blue-zebra", "cmpl-blue-zebra", "full-red-noncmpl", "red-noncmpl", "full-yellow-noncmpl", "yellow-noncmpl":
-		writeConfigEntry("COLOR_SCHEME", configVal)
-	default:
-		system.ErrorExit("wrong value '%s' for config variable 'COLOR_SCHEME'. Please check.", configVal)
-	}
-}
-
-// ConfigureActionSetIgnoreReload sets the variable IGNORE_RELOAD
-func ConfigureActionSetIgnoreReload(configVal string) {
-	switch configVal {
-	case "yes", "no":
-		writeConfigEntry("IGNORE_RELOAD", configVal)
-	default:
-		system.ErrorExit("wrong value '%s' for config variable 'IGNORE_RELOAD'. Only 'yes' or 'no' supported. Please check.", configVal)
-	}
-}
-
-// ConfigureActionSetDebug sets the variable DEBUG
-func ConfigureActionSetDebug(configVal string) {
-	switch configVal {
-	case "on", "off":
-		writeConfigEntry("DEBUG", configVal)
-	default:
-		system.ErrorExit("wrong value '%s' for config variable 'DEBUG'. Only 'on' or 'off' supported. Please check.", configVal)
-	}
-}
-
-// ConfigureActionSetTrentoASDP sets the saptune-discovery-period of the
-// Trento Agent
-func ConfigureActionSetTrentoASDP(configVal string) {
-	switch configVal {
-	case "300", "600", "900", "1800", "3600", "off":
-		err := system.CheckAndSetTrento("TrentoASDP", configVal, true)
-		if err != nil {
-			system.ErrorExit("", 1)
+		key, ok := ConfigKeyByName(configEntry)
+		if !ok || !key.Changeable || len(configVals) == 0 {
+			// unknown variable or missing value to be configured
+			PrintHelpAndExit(writer, 1)
+			return
 		}
-		writeConfigEntry("TrentoASDP", configVal)
-	default:
-		system.ErrorExit("wrong value '%s' for the Trento Agent saptune-discovery-period. Supported values are '300', '600', '900', '1800', '3600'. Please check.", configVal)
+		ConfigureActionSet(key, configVals)
 	}
 }
 
-// ConfigureActionSetSkipSysctlFiles sets the exclude list for the sysctl
-// config warnings
-func ConfigureActionSetSkipSysctlFiles(configVals []string) {
-	if configVals[0] == "" {
-		writeConfigEntry("SKIP_SYSCTL_FILES", configVals[0])
-		system.ErrorExit("", 0)
-	}
-	confVals := configVals
-	if len(configVals) == 1 && strings.Contains(configVals[0], ",") {
-		confVals = strings.Split(configVals[0], ",")
-	}
-	confVal := ""
-	for _, file := range confVals {
-		file = strings.TrimSuffix(file, ",")
-		if system.IsValidSysctlLocations(file) {
-			if confVal == "" {
-				confVal = file
-			} else {
-				confVal = confVal + ", " + file
-			}
-		} else {
-			system.ErrorExit("wrong value '%s' for config variable 'SKIP_SYSCTL_FILES' provided. sysctl command will not search in this locaction. Exiting without changing saptune configuration. Please check.", file)
-		}
-	}
-	if confVal != "" {
-		writeConfigEntry("SKIP_SYSCTL_FILES", confVal)
+// ConfigureActionSet checks the value(s) of a variable against its
+// description, runs the side effects and writes the variable to the saptune
+// configuration file
+func ConfigureActionSet(key ConfigKey, configVals []string) {
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, true)
+	if err != nil {
+		system.ErrorExit("Unable to read file '%s': '%v'\n", saptuneSysconfig, err, 128)
+	} else if dropIn := sconf.DropInFile(key.Name); dropIn != "" {
+		// the drop-in file would override the changed value
+		system.ErrorExit("Variable '%s' is set in the drop-in file '%s', which overrides the saptune configuration file. Please change the value there.", key.Name, dropIn)
+	} else if configVal, err := key.CheckValue(configVals); err != nil {
+		system.ErrorExit("%v.", err)
+	} else if key.setHook != nil && key.setHook(configVal) != nil {
+		system.ErrorExit("", 1)
+	} else {
+		writeConfigEntry(key.Name, configVal)
 	}
 }
 
 // writeConfigEntry writes the changed config entry setting to the saptune
 // config file
 func writeConfigEntry(entry, val string) {
-	sconf, err := txtparser.ParseSysconfigFile(saptuneSysconfig, true)
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, true)
 	if err != nil {
 		system.ErrorExit("Unable to read file '%s': '%v'\n", saptuneSysconfig, err, 128)
 	}
@@ -146,13 +82,102 @@ func writeConfigEntry(entry, val string) {
 	}
 }
 
-// ConfigureActionShow shows the content of the saptune configuration file
+// ConfigureActionShow shows the values of the variables of the saptune
+// configuration file
 func ConfigureActionShow(writer io.Writer) {
-	cont, err := os.ReadFile(saptuneSysconfig)
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
 	if err != nil {
 		system.ErrorExit("Unable to read file '%s': '%v'\n", saptuneSysconfig, err, 128)
 	}
-	fmt.Fprintf(writer, "\nContent of saptune configuration file %s:\n\n%s\n", saptuneSysconfig, string(cont))
+	result := system.JConfigKeys{ConfigFile: saptuneSysconfig, Keys: []system.JConfigKey{}}
+	fmt.Fprintf(writer, "\nContent of saptune configuration file %s:\n\n", saptuneSysconfig)
+	for _, key := range configKeys {
+		info := configKeyInfo(key, sconf)
+		result.Keys = append(result.Keys, info)
+		switch {
+		case info.Value == nil:
+			fmt.Fprintf(writer, "    %-24s not set (default '%s')\n", key.Name, key.Default)
+		case info.Source != saptuneSysconfig:
+			fmt.Fprintf(writer, "    %-24s '%s' (from drop-in file %s)\n", key.Name, *info.Value, info.Source)
+		default:
+			fmt.Fprintf(writer, "    %-24s '%s'\n", key.Name, *info.Value)
+		}
+	}
+	system.Jcollect(result)
+	fmt.Fprintf(writer, "\nUse 'saptune configure describe VARIABLE' to get a description of a variable.\n\n")
+}
+
+// ConfigureActionDescribe shows the description, the allowed values, the
+// default and the current value of the given variables or of all variables
+// of the saptune configuration file
+func ConfigureActionDescribe(writer io.Writer, configEntries []string) {
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
+	if err != nil {
+		system.ErrorExit("Unable to read file '%s': '%v'\n", saptuneSysconfig, err, 128)
+	}
+	keys := configKeys
+	if len(configEntries) != 0 {
+		keys = []ConfigKey{}
+		for _, name := range configEntries {
+			key, ok := ConfigKeyByName(name)
+			if !ok {
+				system.ErrorExit("Unknown configuration variable '%s'. Supported are '%s'.", name, strings.Join(configKeyNames(), "', '"))
+			}
+			keys = append(keys, key)
+		}
+	}
+	result := system.JConfigKeys{ConfigFile: saptuneSysconfig, Keys: []system.JConfigKey{}}
+	for _, key := range keys {
+		info := configKeyInfo(key, sconf)
+		result.Keys = append(result.Keys, info)
+		fmt.Fprintf(writer, "\n%s\n", key.Name)
+		fmt.Fprintf(writer, "    Description:    %s\n", key.Description)
+		fmt.Fprintf(writer, "    Type:           %s\n", key.Type)
+		if len(key.Values) != 0 {
+			fmt.Fprintf(writer, "    Allowed values: %s\n", key.ValuesText())
+		}
+		fmt.Fprintf(writer, "    Default:        '%s'\n", key.Default)
+		switch {
+		case info.Value == nil:
+			fmt.Fprintf(writer, "    Current value:  not set\n")
+		case info.Source != saptuneSysconfig:
+			fmt.Fprintf(writer, "    Current value:  '%s' (from drop-in file %s)\n", *info.Value, info.Source)
+		default:
+			fmt.Fprintf(writer, "    Current value:  '%s'\n", *info.Value)
+		}
+		if key.State {
+			fmt.Fprintf(writer, "    Drop-in:        no, runtime state maintained by saptune\n")
+		}
+		fmt.Fprintf(writer, "    Mandatory:      %s\n", yesNo(key.Mandatory))
+		if key.Changeable {
+			fmt.Fprintf(writer, "    Changeable:     yes, by 'saptune configure %s VALUE'\n", key.Name)
+		} else {
+			fmt.Fprintf(writer, "    Changeable:     no\n")
+		}
+		if key.SideEffect != "" {
+			fmt.Fprintf(writer, "    Side effect:    %s\n", key.SideEffect)
+		}
+	}
+	fmt.Fprintf(writer, "\n")
+	system.Jcollect(result)
+}
+
+// configKeyNames returns the names of all variables of the saptune
+// configuration file
+func configKeyNames() []string {
+	names := []string{}
+	for _, key := range configKeys {
+		names = append(names, key.Name)
+	}
+	return names
+}
+
+// yesNo returns 'yes' for true and 'no' for false
+func yesNo(flag bool) string {
+	if flag {
+		return "yes"
+	}
+	return "no"
 }
 
 // ConfigureActionReset resets the main saptune configuration to the delivery
@@ -175,7 +200,15 @@ func ConfigureActionReset(reader io.Reader, writer io.Writer, tuneApp *app.App)
 
 		// set configuration file back to default/delivery
 		saptuneTemplate := system.SaptuneConfigTemplate()
-		if err := system.CopyFile(saptuneTemplate, saptuneSysconfig); err != nil {
+		if _, err := os.Stat(saptuneTemplate); os.IsNotExist(err) {
+			// no template available, use the defaults of the
+			// variable descriptions
+			system.WarningLog("template file '%s' not found, using the default values of the saptune configuration variables", saptuneTemplate)
+			if err := os.WriteFile(saptuneSysconfig, []byte(configTemplateFromKeys()), 0644); err != nil {
+				system.ErrorLog("Failed to set saptune configuration file '%s' back to delivery state: %v", saptuneSysconfig, err)
+				errcnt = errcnt + 1
+			}
+		} else if err := system.CopyFile(saptuneTemplate, saptuneSysconfig); err != nil {
 			system.ErrorLog("Failed to set saptune configuration file '%s' back to delivery state by copying the template file '%s'", saptuneSysconfig, saptuneTemplate)
 			errcnt = errcnt + 1
 		}
@@ -0,0 +1,424 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// steps of 'saptune converge' in the order they are executed
+const (
+	stepConfigure      = "set configure value"
+	stepRevertNote     = "revert Note"
+	stepRevertSol      = "revert solution"
+	stepReorderNote    = "revert Note for reorder"
+	stepSuspendNote    = "revert Note for reapply"
+	stepWriteOverride  = "write override file"
+	stepRemoveOverride = "remove override file"
+	stepApplySol       = "apply solution"
+	stepApplyNote      = "apply Note"
+)
+
+// convergeState is the desired tuning state of a host read from the file
+// of 'saptune converge'
+type convergeState struct {
+	// Solution to be enabled and applied, empty for no solution
+	Solution string `json:"solution"`
+	// Notes to be enabled and applied in addition to the Notes of
+	// the solution, in the order they should be applied. The Notes of
+	// the solution are applied in the order of the solution definition
+	Notes []string `json:"notes"`
+	// Overrides maps the Note IDs to the content of their override
+	// file. If missing, the override files are not changed
+	Overrides map[string]string `json:"overrides"`
+	// Configure contains the values of the changeable variables of the
+	// saptune configuration. Variables not listed are not changed
+	Configure map[string]string `json:"configure"`
+}
+
+// convergeStep is a single operation needed to reach the desired state
+type convergeStep struct {
+	action string
+	object string
+	value  string
+}
+
+// ConvergeAction compares the desired state described in the file with the
+// current tuning and performs only the operations needed to reach the
+// desired state. With '--dry-run' the operations are only shown.
+func ConvergeAction(writer io.Writer, fileName string, tuneApp *app.App) {
+	if fileName == "" {
+		PrintHelpAndExit(writer, 1)
+		return
+	}
+	state, err := readConvergeState(fileName)
+	if err != nil {
+		system.ErrorExit("Failed to read the desired state file '%s': %v", fileName, err)
+	}
+	steps, err := convergePlan(state, tuneApp)
+	if err != nil {
+		system.ErrorExit("The desired state file '%s' is invalid: %v", fileName, err)
+	}
+	if len(steps) == 0 {
+		fmt.Fprintf(writer, "The system already matches the desired state of '%s', nothing to do.\n", fileName)
+		return
+	}
+	fmt.Fprintf(writer, "Operations needed to reach the desired state of '%s':\n\n", fileName)
+	printConvergeSteps(writer, steps)
+	if system.IsFlagSet("dryrun") {
+		fmt.Fprintf(writer, "\nDry run, nothing changed.\n")
+		return
+	}
+	noteIDs, solNames := convergeApplyObjects(steps)
+	verifyApplyIntegrity(noteIDs, solNames)
+	for _, step := range steps {
+		system.InfoLog("converge: %s '%s'", step.action, step.object)
+		if err := runConvergeStep(step, tuneApp); err != nil {
+			system.ErrorExit("Failed to %s '%s': %v", step.action, step.object, err)
+		}
+	}
+	system.NoticeLog("The system has been converged to the desired state of '%s'", fileName)
+	fmt.Fprintf(writer, "\nThe system now matches the desired state of '%s'.\n", fileName)
+	if len(noteIDs) != 0 || len(solNames) != 0 {
+		rememberMessage(writer)
+	}
+}
+
+// readConvergeState reads the desired state file, which is in json format.
+// Unknown entries are reported as error to detect typos
+func readConvergeState(fileName string) (convergeState, error) {
+	state := convergeState{}
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return state, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+// convergePlan validates the desired state and returns the operations
+// needed to get from the current tuning to the desired state in the order
+// they have to be executed:
+// configure values, revert of the Notes and the solution no longer
+// wanted, revert of the Notes not in the desired apply order, revert of the
+// applied Notes with changed override files, changes of the override
+// files, apply of the solution and of the Notes
+func convergePlan(state convergeState, tuneApp *app.App) ([]convergeStep, error) {
+	steps := []convergeStep{}
+	solNotes := make(map[string]bool)
+	if state.Solution != "" {
+		sol, ok := tuneApp.AllSolutions[state.Solution]
+		if !ok {
+			return steps, fmt.Errorf("solution '%s' not found", state.Solution)
+		}
+		for _, noteID := range sol {
+			solNotes[noteID] = true
+		}
+	}
+	notes := []string{}
+	wanted := make(map[string]bool)
+	for _, noteID := range state.Notes {
+		if _, ok := tuneApp.AllNotes[noteID]; !ok {
+			return steps, fmt.Errorf("Note '%s' not found", noteID)
+		}
+		if wanted[noteID] || solNotes[noteID] {
+			// duplicate or already covered by the solution
+			continue
+		}
+		wanted[noteID] = true
+		notes = append(notes, noteID)
+	}
+	for noteID := range state.Overrides {
+		if _, ok := tuneApp.AllNotes[noteID]; !ok {
+			return steps, fmt.Errorf("override for unknown Note '%s'", noteID)
+		}
+	}
+
+	// configure values
+	cfgSteps, err := convergeConfigure(state.Configure)
+	if err != nil {
+		return steps, err
+	}
+	steps = append(steps, cfgSteps...)
+
+	// revert the Notes and the solution no longer wanted
+	reverted := make(map[string]bool)
+	kept := make(map[string]bool)
+	for _, noteID := range tuneApp.TuneForNotes {
+		if wanted[noteID] || solNotes[noteID] {
+			kept[noteID] = true
+			continue
+		}
+		steps = append(steps, convergeStep{action: stepRevertNote, object: noteID})
+		reverted[noteID] = true
+	}
+	curSol := ""
+	if len(tuneApp.TuneForSolutions) != 0 {
+		curSol = tuneApp.TuneForSolutions[0]
+	}
+	if curSol != "" && curSol != state.Solution {
+		steps = append(steps, convergeStep{action: stepRevertSol, object: curSol})
+		for _, noteID := range tuneApp.AllSolutions[curSol] {
+			if !kept[noteID] {
+				reverted[noteID] = true
+			}
+		}
+	}
+	for _, noteID := range tuneApp.NoteApplyOrder {
+		// left-overs in the Note apply order
+		if !wanted[noteID] && !solNotes[noteID] && !reverted[noteID] {
+			steps = append(steps, convergeStep{action: stepRevertNote, object: noteID})
+			reverted[noteID] = true
+		}
+	}
+
+	// Note apply order of the additional Notes
+	for _, noteID := range convergeNoteOrder(notes, tuneApp.NoteApplyOrder, reverted) {
+		steps = append(steps, convergeStep{action: stepReorderNote, object: noteID})
+		reverted[noteID] = true
+	}
+
+	// override files
+	ovSteps, changed, err := convergeOverrides(state.Overrides, tuneApp)
+	if err != nil {
+		return steps, err
+	}
+	suspended := make(map[string]bool)
+	for _, noteID := range changed {
+		if _, applied := tuneApp.IsNoteApplied(noteID); applied && !reverted[noteID] {
+			steps = append(steps, convergeStep{action: stepSuspendNote, object: noteID})
+			suspended[noteID] = true
+		}
+	}
+	steps = append(steps, ovSteps...)
+
+	// apply solution and Notes
+	solApply := false
+	if state.Solution != "" {
+		applyState, _ := tuneApp.IsSolutionApplied(state.Solution)
+		if curSol != state.Solution || applyState != "fully" || len(suspended) != 0 {
+			steps = append(steps, convergeStep{action: stepApplySol, object: state.Solution})
+			solApply = true
+		}
+	}
+	applied := make(map[string]bool)
+	for _, noteID := range notes {
+		_, isApplied := tuneApp.IsNoteApplied(noteID)
+		if !isApplied || suspended[noteID] || reverted[noteID] || !kept[noteID] {
+			steps = append(steps, convergeStep{action: stepApplyNote, object: noteID})
+			applied[noteID] = true
+		}
+	}
+	for _, noteID := range changed {
+		if suspended[noteID] && !applied[noteID] && !(solApply && solNotes[noteID]) {
+			// reapply a Note of the enabled solution, which is not
+			// applied again by the apply of the solution
+			steps = append(steps, convergeStep{action: stepApplyNote, object: noteID})
+		}
+	}
+	return steps, nil
+}
+
+// convergeNoteOrder compares the desired order of the additional Notes with
+// their order in the Note apply order and returns the Notes, which need to
+// be reverted, so that they are applied again in the desired order.
+// Applying a Note appends it to the Note apply order, so the Notes keeping
+// their position need to be the beginning of the desired order. As many
+// Notes as possible keep their position
+func convergeNoteOrder(notes, applyOrder []string, reverted map[string]bool) []string {
+	wanted := make(map[string]bool)
+	for _, noteID := range notes {
+		wanted[noteID] = true
+	}
+	current := []string{}
+	for _, noteID := range applyOrder {
+		if wanted[noteID] && !reverted[noteID] {
+			current = append(current, noteID)
+		}
+	}
+	keep := make(map[string]bool)
+	pos := 0
+	for _, noteID := range current {
+		if pos < len(notes) && noteID == notes[pos] {
+			keep[noteID] = true
+			pos++
+		}
+	}
+	reorder := []string{}
+	for _, noteID := range current {
+		if !keep[noteID] {
+			reorder = append(reorder, noteID)
+		}
+	}
+	return reorder
+}
+
+// convergeConfigure checks the configure values of the desired state and
+// returns the steps for the values, which differ from the current ones
+func convergeConfigure(values map[string]string) ([]convergeStep, error) {
+	steps := []convergeStep{}
+	if len(values) == 0 {
+		return steps, nil
+	}
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
+	if err != nil {
+		return steps, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key, ok := ConfigKeyByName(name)
+		if !ok || !key.Changeable {
+			return steps, fmt.Errorf("configuration variable '%s' can not be changed by 'saptune configure'", name)
+		}
+		val, err := key.CheckValue([]string{values[name]})
+		if err != nil {
+			return steps, err
+		}
+		if dropIn := sconf.DropInFile(name); dropIn != "" {
+			if sconf.GetString(name, "") != val {
+				return steps, fmt.Errorf("configuration variable '%s' is set in the drop-in file '%s'", name, dropIn)
+			}
+			continue
+		}
+		if sconf.GetString(name, "") != val {
+			steps = append(steps, convergeStep{action: stepConfigure, object: name, value: val})
+		}
+	}
+	return steps, nil
+}
+
+// convergeOverrides compares the override files of the desired state with
+// the current override files of the Notes and returns the steps to write or
+// remove the override files together with the list of the affected Notes.
+// The override files of unknown Notes are not touched. Override drop-in
+// files would override the desired content, so they are reported as error
+func convergeOverrides(overrides map[string]string, tuneApp *app.App) ([]convergeStep, []string, error) {
+	steps := []convergeStep{}
+	changed := []string{}
+	if overrides == nil {
+		// override files not managed by the desired state
+		return steps, changed, nil
+	}
+	dirs, files := system.ListDir(OverrideTuningSheets, "")
+	for _, dir := range dirs {
+		noteID := strings.TrimSuffix(dir, ".d")
+		if _, ok := tuneApp.AllNotes[noteID]; !ok || noteID == dir {
+			continue
+		}
+		if dropIns := txtparser.GetOverrideDropIns(OverrideTuningSheets, noteID); len(dropIns) != 0 {
+			return steps, changed, fmt.Errorf("the override drop-in files '%s' of Note '%s' would override the desired override file. Please remove them or move their content to the desired state", strings.Join(dropIns, "', '"), noteID)
+		}
+	}
+	for _, noteID := range files {
+		if _, ok := tuneApp.AllNotes[noteID]; !ok {
+			continue
+		}
+		if _, ok := overrides[noteID]; !ok {
+			steps = append(steps, convergeStep{action: stepRemoveOverride, object: noteID})
+			changed = append(changed, noteID)
+		}
+	}
+	for _, noteID := range sortedOverrideIDs(overrides) {
+		content := overrideContent(overrides[noteID])
+		cur, err := os.ReadFile(path.Join(OverrideTuningSheets, noteID))
+		if err != nil && !os.IsNotExist(err) {
+			return steps, changed, err
+		}
+		if err == nil && string(cur) == content {
+			continue
+		}
+		steps = append(steps, convergeStep{action: stepWriteOverride, object: noteID, value: content})
+		changed = append(changed, noteID)
+	}
+	sort.Strings(changed)
+	return steps, changed, nil
+}
+
+// sortedOverrideIDs returns the Note IDs of the override map in sorted order
+func sortedOverrideIDs(overrides map[string]string) []string {
+	noteIDs := make([]string, 0, len(overrides))
+	for noteID := range overrides {
+		noteIDs = append(noteIDs, noteID)
+	}
+	sort.Strings(noteIDs)
+	return noteIDs
+}
+
+// overrideContent returns the content of an override file terminated by a
+// newline
+func overrideContent(content string) string {
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content = content + "\n"
+	}
+	return content
+}
+
+// convergeApplyObjects returns the Notes and solutions, which will be
+// applied by the steps
+func convergeApplyObjects(steps []convergeStep) ([]string, []string) {
+	noteIDs := []string{}
+	solNames := []string{}
+	for _, step := range steps {
+		switch step.action {
+		case stepApplyNote:
+			noteIDs = append(noteIDs, step.object)
+		case stepApplySol:
+			solNames = append(solNames, step.object)
+		}
+	}
+	return noteIDs, solNames
+}
+
+// runConvergeStep executes a single step
+func runConvergeStep(step convergeStep, tuneApp *app.App) error {
+	var err error
+	switch step.action {
+	case stepConfigure:
+		key, _ := ConfigKeyByName(step.object)
+		ConfigureActionSet(key, []string{step.value})
+	case stepRevertNote, stepReorderNote:
+		err = tuneApp.RevertNote(step.object, true)
+	case stepRevertSol:
+		err = tuneApp.RevertSolution(step.object)
+	case stepSuspendNote:
+		err = tuneApp.RevertNote(step.object, false)
+	case stepWriteOverride:
+		if err = os.MkdirAll(OverrideTuningSheets, 0755); err == nil {
+			err = os.WriteFile(path.Join(OverrideTuningSheets, step.object), []byte(step.value), 0644)
+		}
+	case stepRemoveOverride:
+		err = os.Remove(path.Join(OverrideTuningSheets, step.object))
+	case stepApplySol:
+		_, err = tuneApp.TuneSolution(step.object)
+	case stepApplyNote:
+		err = tuneApp.TuneNote(step.object)
+	}
+	return err
+}
+
+// printConvergeSteps prints the steps needed to reach the desired state
+func printConvergeSteps(writer io.Writer, steps []convergeStep) {
+	for i, step := range steps {
+		if step.action == stepConfigure {
+			fmt.Fprintf(writer, "    %2d. %-24s %s = '%s'\n", i+1, step.action, step.object, step.value)
+			continue
+		}
+		fmt.Fprintf(writer, "    %2d. %-24s %s\n", i+1, step.action, step.object)
+	}
+}
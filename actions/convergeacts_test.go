@@ -0,0 +1,193 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/system"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadConvergeState(t *testing.T) {
+	fileName := "/tmp/saptune_converge_state.json"
+	defer os.Remove(fileName)
+	content := `{"solution": "sol1", "notes": ["900929"], "overrides": {"900929": "[sysctl]\nvm.swappiness = 20\n"}, "configure": {"DEBUG": "on"}}`
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state, err := readConvergeState(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := convergeState{Solution: "sol1", Notes: []string{"900929"}, Overrides: map[string]string{"900929": "[sysctl]\nvm.swappiness = 20\n"}, Configure: map[string]string{"DEBUG": "on"}}
+	if !reflect.DeepEqual(state, exp) {
+		t.Errorf("got '%+v', expected '%+v'", state, exp)
+	}
+	if err := os.WriteFile(fileName, []byte(`{"solutions": ["sol1"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readConvergeState(fileName); err == nil {
+		t.Error("expected an error for the unknown entry 'solutions'")
+	}
+	if _, err := readConvergeState("/tmp/saptune_converge_missing.json"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestConvergePlan(t *testing.T) {
+	tstDir := "/tmp/saptune_converge_test"
+	os.RemoveAll(tstDir)
+	defer os.RemoveAll(tstDir)
+	oldOverrideTuningSheets := OverrideTuningSheets
+	defer func() { OverrideTuningSheets = oldOverrideTuningSheets }()
+	OverrideTuningSheets = path.Join(tstDir, "override") + "/"
+	oldSaptuneSysconfig := saptuneSysconfig
+	defer func() { saptuneSysconfig = oldSaptuneSysconfig }()
+	saptuneSysconfig = path.Join(tstDir, "saptune")
+	oldDropInDir := system.SaptuneConfigDropInDir
+	defer func() { system.SaptuneConfigDropInDir = oldDropInDir }()
+	system.SaptuneConfigDropInDir = path.Join(tstDir, "saptune.conf.d")
+	if err := os.MkdirAll(tstDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := system.CopyFile(path.Join(TstFilesInGOPATH, "etc/sysconfig/saptune"), saptuneSysconfig); err != nil {
+		t.Fatal(err)
+	}
+
+	// solution 'sol1' and Note '900929' applied
+	cApp := app.InitialiseApp(tstDir, tstDir, tuningOpts, AllTestSolutions)
+	cApp.TuneForSolutions = []string{"sol1"}
+	cApp.TuneForNotes = []string{"900929"}
+	cApp.NoteApplyOrder = []string{"simpleNote", "900929"}
+	for _, noteID := range cApp.NoteApplyOrder {
+		stateFile := cApp.State.GetPathToNote(noteID)
+		if err := os.MkdirAll(path.Dir(stateFile), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(stateFile, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// system matches the desired state
+	state := convergeState{Solution: "sol1", Notes: []string{"900929", "simpleNote"}, Configure: map[string]string{"COLOR_SCHEME": "full-green-zebra"}}
+	steps, err := convergePlan(state, cApp)
+	if err != nil || len(steps) != 0 {
+		t.Errorf("expected no steps, got '%+v' - '%v'", steps, err)
+	}
+
+	// change solution, override file and configure value
+	state = convergeState{
+		Solution:  "sol2",
+		Notes:     []string{"900929", "900929", "extraNote"},
+		Overrides: map[string]string{"900929": "[sysctl]\nvm.swappiness = 20"},
+		Configure: map[string]string{"DEBUG": "on"},
+	}
+	steps, err = convergePlan(state, cApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []convergeStep{
+		{action: stepConfigure, object: "DEBUG", value: "on"},
+		{action: stepRevertSol, object: "sol1"},
+		{action: stepSuspendNote, object: "900929"},
+		{action: stepWriteOverride, object: "900929", value: "[sysctl]\nvm.swappiness = 20\n"},
+		{action: stepApplySol, object: "sol2"},
+		{action: stepApplyNote, object: "900929"},
+	}
+	if !reflect.DeepEqual(steps, exp) {
+		t.Errorf("got '%+v', expected '%+v'", steps, exp)
+	}
+	noteIDs, solNames := convergeApplyObjects(steps)
+	if !reflect.DeepEqual(noteIDs, []string{"900929"}) || !reflect.DeepEqual(solNames, []string{"sol2"}) {
+		t.Errorf("wrong apply objects '%v' - '%v'", noteIDs, solNames)
+	}
+	buffer := bytes.Buffer{}
+	printConvergeSteps(&buffer, steps)
+	if !strings.Contains(buffer.String(), "     1. set configure value      DEBUG = 'on'\n") || !strings.Contains(buffer.String(), "     5. apply solution           sol2\n") {
+		t.Errorf("wrong output '%s'", buffer.String())
+	}
+
+	// write and remove override files
+	for _, step := range []convergeStep{{action: stepWriteOverride, object: "900929", value: "[sysctl]\n"}, {action: stepWriteOverride, object: "simpleNote", value: "[sysctl]\n"}} {
+		if err := runConvergeStep(step, cApp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(path.Join(OverrideTuningSheets, "900929.d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	state = convergeState{Solution: "sol1", Notes: []string{"900929"}, Overrides: map[string]string{"900929": "[sysctl]"}}
+	steps, err = convergePlan(state, cApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp = []convergeStep{
+		{action: stepSuspendNote, object: "simpleNote"},
+		{action: stepRemoveOverride, object: "simpleNote"},
+		{action: stepApplySol, object: "sol1"},
+	}
+	if !reflect.DeepEqual(steps, exp) {
+		t.Errorf("got '%+v', expected '%+v'", steps, exp)
+	}
+	if err := runConvergeStep(exp[1], cApp); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path.Join(OverrideTuningSheets, "simpleNote")); !os.IsNotExist(err) {
+		t.Error("override file 'simpleNote' should be removed")
+	}
+
+	// Note apply order and left-overs in the Note apply order
+	cApp.TuneForNotes = []string{"900929", "NEWSOL2NOTE"}
+	cApp.NoteApplyOrder = []string{"simpleNote", "900929", "NEWSOL2NOTE", "extraNote"}
+	for _, noteID := range []string{"NEWSOL2NOTE", "extraNote"} {
+		if err := os.WriteFile(cApp.State.GetPathToNote(noteID), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state = convergeState{Solution: "sol1", Notes: []string{"NEWSOL2NOTE", "900929"}}
+	steps, err = convergePlan(state, cApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp = []convergeStep{
+		{action: stepRevertNote, object: "extraNote"},
+		{action: stepReorderNote, object: "900929"},
+		{action: stepApplyNote, object: "900929"},
+	}
+	if !reflect.DeepEqual(steps, exp) {
+		t.Errorf("got '%+v', expected '%+v'", steps, exp)
+	}
+	if reorder := convergeNoteOrder([]string{"B", "C", "A", "D"}, []string{"A", "B", "C"}, map[string]bool{}); !reflect.DeepEqual(reorder, []string{"A"}) {
+		t.Errorf("got '%v', expected '[A]'", reorder)
+	}
+
+	// override drop-in files are not managed by converge
+	if err := os.WriteFile(path.Join(OverrideTuningSheets, "900929.d", "10-swap.conf"), []byte("[sysctl]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state = convergeState{Solution: "sol1", Notes: []string{"900929"}, Overrides: map[string]string{"900929": "[sysctl]"}}
+	if _, err := convergePlan(state, cApp); err == nil || !strings.Contains(err.Error(), "10-swap.conf") {
+		t.Errorf("expected an error for the override drop-in file, got '%v'", err)
+	}
+	state.Overrides = nil
+	if _, err := convergePlan(state, cApp); err != nil {
+		t.Errorf("override drop-in files without desired overrides should not fail - '%v'", err)
+	}
+
+	// invalid desired states
+	for _, state := range []convergeState{
+		{Solution: "hugo"},
+		{Notes: []string{"hugo"}},
+		{Overrides: map[string]string{"hugo": ""}},
+		{Configure: map[string]string{"SAPTUNE_VERSION": "3"}},
+		{Configure: map[string]string{"DEBUG": "hugo"}},
+	} {
+		if _, err := convergePlan(state, cApp); err == nil {
+			t.Errorf("expected an error for '%+v'", state)
+		}
+	}
+}
@@ -35,6 +35,74 @@ const (
 // set 'unsupported' footnote regarding the architecture
 var footnote1 = footnote1X86
 
+// footnote categories
+// 'not-applicable' - the parameter is not supported or not available on the
+// system, so there is nothing to compare
+// 'environment' - the expected value can not be reached because of a
+// limitation of the hardware or the system setup
+// 'deviation' - details about a real deviation from the expected value
+// 'informational' - additional information, no deviation
+const (
+	fnCatNotApplicable = "not-applicable"
+	fnCatEnvironment   = "environment"
+	fnCatDeviation     = "deviation"
+	fnCatInformational = "informational"
+)
+
+// footnoteCode is the stable, machine readable code and the category of
+// a footnote
+type footnoteCode struct {
+	code     string
+	category string
+}
+
+// footnoteCodes holds the code and category of the footnotes [1] - [16]
+// in the order of the footnote index. The codes are part of the json
+// output and must not be changed
+var footnoteCodes = []footnoteCode{
+	{"unsupported", fnCatNotApplicable},
+	{"not-available", fnCatNotApplicable},
+	{"check-only", fnCatInformational},
+	{"cpu-idle-state-differs", fnCatDeviation},
+	{"unsupported-scheduler", fnCatEnvironment},
+	{"grub-covered", fnCatInformational},
+	{"untouched", fnCatInformational},
+	{"secure-boot", fnCatEnvironment},
+	{"limited-by-max-hw-sectors", fnCatEnvironment},
+	{"defined-twice", fnCatInformational},
+	{"defined-in-sysctl-config", fnCatInformational},
+	{"filesystem-option", fnCatDeviation},
+	{"nr-requests-multiqueue", fnCatEnvironment},
+	{"exceeds-nr-open", fnCatEnvironment},
+	{"tmpfs-calculation-only", fnCatInformational},
+	{"parameter-not-available", fnCatNotApplicable},
+}
+
+// footnote1Codes holds the codes of the architecture and cloud service
+// provider specific variants of footnote [1]
+var footnote1Codes = map[string]string{
+	footnote1X86: "unsupported",
+	footnote1IBM: "not-relevant",
+	footnote1AZR: "not-available-azure",
+	footnote1AWS: "not-available-aws",
+}
+
+// getFootnoteCode returns the code and the category of the footnote with
+// the given index. Footnote [1] is identified by its text, as the text
+// differs for the architecture and the cloud service provider
+func getFootnoteCode(idx int, fntxt string) (string, string) {
+	if idx < 1 || idx > len(footnoteCodes) {
+		return "", ""
+	}
+	fnc := footnoteCodes[idx-1]
+	if idx == 1 {
+		if code, ok := footnote1Codes[fntxt]; ok {
+			fnc.code = code
+		}
+	}
+	return fnc.code, fnc.category
+}
+
 // prepFN checks, if we need to prepare the footnote for a parameter
 // if the command line flage '--show-non-compliant' is used only non compliant
 // parameter rows will be printed and that has to be reflected to the footnotes
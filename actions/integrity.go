@@ -0,0 +1,200 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// integrityManifest is the file name of the checksum manifests. The detached
+// signature of a manifest is expected in the file with the additional
+// suffix '.sig'
+const integrityManifest = "SHA256SUMS"
+
+// ManifestArchive is the directory for the verified manifests of the staging
+// area, which are archived during 'staging release'
+var ManifestArchive = "/var/lib/saptune/staging/manifests/"
+
+// getIntegrityConf reads the integrity settings from /etc/sysconfig/saptune.
+// It returns the public key file used to verify the signature of the
+// manifests (empty, if the integrity check is disabled) and if the files
+// should be verified before apply, too
+func getIntegrityConf() (string, bool) {
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
+	if err != nil {
+		return "", false
+	}
+	pubKey := sconf.GetString("INTEGRITY_PUBKEY", "")
+	return pubKey, pubKey != "" && sconf.GetString("INTEGRITY_CHECK_APPLY", "no") == "yes"
+}
+
+// manifestEntry returns the file name of a Note or solution definition as
+// used in the manifests - 'notes/<NoteID>' or 'sols/<solution>.sol'
+func manifestEntry(name string) string {
+	if strings.HasSuffix(name, ".sol") {
+		return "sols/" + name
+	}
+	return "notes/" + name
+}
+
+// getTrustedManifests returns the manifests of the package area, the
+// staging area and the archived staging manifests
+func getTrustedManifests() []string {
+	manifests := []string{path.Join(PackageArea, integrityManifest), path.Join(StagingArea, integrityManifest)}
+	archived, _ := filepath.Glob(path.Join(ManifestArchive, "*."+integrityManifest))
+	sort.Strings(archived)
+	return append(manifests, archived...)
+}
+
+// loadTrustedChecksums verifies the signature of the given manifests with
+// the public key and returns the checksums of all valid manifests by file
+// name. Missing manifests are skipped, manifests with an invalid signature
+// are reported and skipped
+func loadTrustedChecksums(manifests []string, pubKey string) map[string]map[string]bool {
+	trusted := make(map[string]map[string]bool)
+	for _, manifest := range manifests {
+		if _, err := os.Stat(manifest); os.IsNotExist(err) {
+			continue
+		}
+		sums, err := system.VerifyManifestFile(manifest, pubKey)
+		if err != nil {
+			system.ErrorLog("Untrusted manifest, skipping - %v", err)
+			continue
+		}
+		for entry, sum := range sums {
+			if trusted[entry] == nil {
+				trusted[entry] = make(map[string]bool)
+			}
+			trusted[entry][sum] = true
+		}
+	}
+	return trusted
+}
+
+// verifyIntegrity checks the files (manifest entry -> file name) against
+// the trusted checksums and returns the problems found
+func verifyIntegrity(files map[string]string, trusted map[string]map[string]bool) []string {
+	problems := []string{}
+	entries := make([]string, 0, len(files))
+	for entry := range files {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	for _, entry := range entries {
+		fileName := files[entry]
+		sum, err := system.GetSHA256Hash(fileName)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("'%s': %v", fileName, err))
+			continue
+		}
+		if len(trusted[entry]) == 0 {
+			problems = append(problems, fmt.Sprintf("'%s': not listed in a trusted manifest", fileName))
+		} else if !trusted[entry][sum] {
+			problems = append(problems, fmt.Sprintf("'%s': checksum %s does not match the trusted manifest", fileName, sum))
+		}
+	}
+	return problems
+}
+
+// verifyStagingIntegrity verifies the staging files, which should be
+// released, against the signed manifests of the staging or the package
+// area. Deleted Notes and solutions are skipped as there is nothing to
+// release.
+// If the check fails, saptune exits without releasing anything
+func verifyStagingIntegrity(stageNames []string) {
+	pubKey, _ := getIntegrityConf()
+	if pubKey == "" {
+		return
+	}
+	files := make(map[string]string)
+	for _, stageName := range stageNames {
+		if stgFiles.StageAttributes[stageName]["deleted"] == "true" {
+			continue
+		}
+		files[manifestEntry(stageName)] = stgFiles.StageAttributes[stageName]["sfilename"]
+	}
+	manifests := []string{path.Join(StagingArea, integrityManifest), path.Join(PackageArea, integrityManifest)}
+	problems := verifyIntegrity(files, loadTrustedChecksums(manifests, pubKey))
+	if len(problems) != 0 {
+		for _, prob := range problems {
+			system.ErrorLog("Integrity check failed for %s", prob)
+		}
+		system.ErrorExit("Integrity check of the staging area failed, so nothing is released", 1)
+	}
+	system.InfoLog("Integrity check of the staging files successful")
+}
+
+// archiveStagingManifest saves the signed manifest of the staging area
+// together with its signature in the manifest archive, so the released
+// files can be verified before apply later on.
+// The archive name is the checksum of the manifest
+func archiveStagingManifest() {
+	pubKey, _ := getIntegrityConf()
+	manifest := path.Join(StagingArea, integrityManifest)
+	if pubKey == "" {
+		return
+	}
+	if _, err := os.Stat(manifest); err != nil {
+		return
+	}
+	if _, err := system.VerifyManifestFile(manifest, pubKey); err != nil {
+		system.WarningLog("Manifest of the staging area not archived - %v", err)
+		return
+	}
+	sum, err := system.GetSHA256Hash(manifest)
+	if err != nil {
+		system.WarningLog("Manifest of the staging area not archived - %v", err)
+		return
+	}
+	dest := path.Join(ManifestArchive, sum+"."+integrityManifest)
+	if err := os.MkdirAll(ManifestArchive, 0755); err != nil {
+		system.WarningLog("Manifest of the staging area not archived - %v", err)
+		return
+	}
+	if err := system.CopyFile(manifest, dest); err != nil {
+		system.WarningLog("Manifest of the staging area not archived - %v", err)
+		return
+	}
+	if err := system.CopyFile(manifest+".sig", dest+".sig"); err != nil {
+		system.WarningLog("Manifest of the staging area not archived - %v", err)
+		_ = os.Remove(dest)
+	}
+}
+
+// verifyApplyIntegrity verifies the Note and solution definitions of the
+// working area before apply, if INTEGRITY_CHECK_APPLY is set to 'yes'.
+// Trusted are the signed manifests of the package area, the staging area
+// and the archived manifests of former staging releases. Custom Notes and
+// solutions from the extra directory are not covered by a manifest and
+// therefore skipped.
+// If the check fails, saptune exits without applying anything
+func verifyApplyIntegrity(noteIDs []string, solNames []string) {
+	pubKey, chkApply := getIntegrityConf()
+	if !chkApply {
+		return
+	}
+	files := make(map[string]string)
+	for _, noteID := range noteIDs {
+		if fileName, extraDef, err := chkFileName(noteID, NoteTuningSheets, ExtraTuningSheets); err == nil && !extraDef {
+			files[manifestEntry(noteID)] = fileName
+		}
+	}
+	for _, solName := range solNames {
+		solFName := solName + ".sol"
+		if fileName, extraDef, err := chkFileName(solFName, SolutionSheets, ExtraTuningSheets); err == nil && !extraDef {
+			files[manifestEntry(solFName)] = fileName
+		}
+	}
+	problems := verifyIntegrity(files, loadTrustedChecksums(getTrustedManifests(), pubKey))
+	if len(problems) != 0 {
+		for _, prob := range problems {
+			system.ErrorLog("Integrity check failed for %s", prob)
+		}
+		system.ErrorExit("Integrity check of the Note and solution definitions failed, so nothing is applied", 1)
+	}
+	system.InfoLog("Integrity check of the Note and solution definitions successful")
+}
@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestVerifyIntegrity(t *testing.T) {
+	oldManifestArchive := ManifestArchive
+	oldStagingArea := StagingArea
+	oldPackageArea := PackageArea
+	defer func() {
+		ManifestArchive = oldManifestArchive
+		StagingArea = oldStagingArea
+		PackageArea = oldPackageArea
+	}()
+	// the archive must not be touched by a release of the working area
+	if strings.HasPrefix(ManifestArchive, WorkingArea) {
+		t.Errorf("manifest archive '%s' is located in the working area '%s'", ManifestArchive, WorkingArea)
+	}
+	tstDir := "/tmp/saptune_integrity_test/"
+	defer os.RemoveAll(tstDir)
+	StagingArea = tstDir + "staging/"
+	ManifestArchive = StagingArea + "manifests/"
+	PackageArea = tstDir + "package/"
+	_ = os.MkdirAll(StagingArea+"latest", 0755)
+	_ = os.MkdirAll(PackageArea, 0755)
+
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	pubKeyFile := tstDir + "pubkey.pem"
+	_ = os.WriteFile(pubKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644)
+
+	noteFile := StagingArea + "latest/4711"
+	solFile := StagingArea + "latest/TST.sol"
+	_ = os.WriteFile(noteFile, []byte("[version]\nVERSION=1\n"), 0644)
+	_ = os.WriteFile(solFile, []byte("[ArchX86]\n4711\n"), 0644)
+	files := map[string]string{manifestEntry("4711"): noteFile, manifestEntry("TST.sol"): solFile}
+
+	// no manifest available
+	manifests := getTrustedManifests()
+	if len(manifests) != 2 {
+		t.Errorf("got '%v'", manifests)
+	}
+	problems := verifyIntegrity(files, loadTrustedChecksums(manifests, pubKeyFile))
+	if len(problems) != 2 || !strings.Contains(problems[0], "not listed in a trusted manifest") {
+		t.Errorf("got '%v'", problems)
+	}
+
+	// signed manifest of the staging area
+	manifest := ""
+	for _, entry := range []string{"notes/4711", "sols/TST.sol"} {
+		sum, _ := system.GetSHA256Hash(files[entry])
+		manifest = manifest + fmt.Sprintf("%s  %s\n", sum, entry)
+	}
+	manifestFile := path.Join(StagingArea, integrityManifest)
+	_ = os.WriteFile(manifestFile, []byte(manifest), 0644)
+	_ = os.WriteFile(manifestFile+".sig", ed25519.Sign(priv, []byte(manifest)), 0644)
+	if problems = verifyIntegrity(files, loadTrustedChecksums(manifests, pubKeyFile)); len(problems) != 0 {
+		t.Errorf("got '%v'", problems)
+	}
+
+	// tampered file
+	_ = os.WriteFile(noteFile, []byte("[version]\nVERSION=2\n"), 0644)
+	problems = verifyIntegrity(files, loadTrustedChecksums(manifests, pubKeyFile))
+	if len(problems) != 1 || !strings.Contains(problems[0], "does not match the trusted manifest") {
+		t.Errorf("got '%v'", problems)
+	}
+
+	// manifest with an invalid signature is not trusted
+	_ = os.WriteFile(manifestFile+".sig", []byte("invalid"), 0644)
+	_ = os.WriteFile(noteFile, []byte("[version]\nVERSION=1\n"), 0644)
+	if problems = verifyIntegrity(files, loadTrustedChecksums(manifests, pubKeyFile)); len(problems) != 2 {
+		t.Errorf("got '%v'", problems)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/SUSE/saptune/app"
 	"github.com/SUSE/saptune/sap/note"
 	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"io"
 	"os"
 	"sort"
@@ -30,11 +31,11 @@ func NoteAction(writer io.Writer, actionName, noteID, newNoteID string, tuneApp
 		system.WarningLog("the action 'note simulate' is deprecated!.\nsaptune will still handle this action in the current version, but it will be removed in future versions of saptune.")
 		NoteActionSimulate(writer, noteID, tuneApp)
 	case "customise", "customize":
-		NoteActionCustomise(writer, noteID, tuneApp)
+		NoteActionCustomise(os.Stdin, writer, noteID, tuneApp)
 	case "edit":
-		NoteActionEdit(writer, noteID, tuneApp)
+		NoteActionEdit(os.Stdin, writer, noteID, tuneApp)
 	case "create":
-		NoteActionCreate(writer, noteID, tuneApp)
+		NoteActionCreate(os.Stdin, writer, noteID, tuneApp)
 	case "show":
 		NoteActionShow(writer, noteID, tuneApp)
 	case "delete":
@@ -51,6 +52,10 @@ func NoteAction(writer io.Writer, actionName, noteID, newNoteID string, tuneApp
 		NoteActionApplied(writer, tuneApp)
 	case "enabled":
 		NoteActionEnabled(writer, tuneApp)
+	case "lint":
+		NoteActionLint(writer, noteID, tuneApp)
+	case "override":
+		NoteActionOverride(writer, system.CliArgs(3), tuneApp)
 	default:
 		PrintHelpAndExit(writer, 1)
 	}
@@ -72,6 +77,7 @@ func NoteActionApply(writer io.Writer, noteID string, tuneApp *app.App) {
 		}
 		system.ErrorExit("", 0)
 	}
+	verifyApplyIntegrity([]string{noteID}, []string{})
 	if err := tuneApp.TuneNote(noteID); err != nil {
 		system.ErrorExit("Failed to tune for note %s: %v", noteID, err)
 	}
@@ -102,7 +108,7 @@ func NoteActionList(writer io.Writer, tuneApp *app.App) {
 	}
 	tuneApp.PrintNoteApplyOrder(writer)
 	remember := bytes.Buffer{}
-	if system.GetFlagVal("format") == "json" {
+	if system.IsStructuredOut() {
 		writer = &remember
 	}
 	rememberMessage(writer)
@@ -121,8 +127,8 @@ func setupNoteListFormat(noteID string, solutionNoteIDs []string, tuneApp *app.A
 	if len(noteID) >= 8 {
 		format = "\t%s\t%s\n"
 	}
-	if _, err := os.Stat(fmt.Sprintf("%s%s", OverrideTuningSheets, noteID)); err == nil {
-		// override file exists
+	if len(getOverrideFiles(noteID)) != 0 {
+		// override file or drop-in override files exist
 		format = " O" + format
 		jnoteListEntry.NoteOverride = true
 	}
@@ -215,8 +221,10 @@ func NoteActionSimulate(writer io.Writer, noteID string, tuneApp *app.App) {
 }
 
 // NoteActionCustomise creates an override file and allows to editing the Note
-// definition override file
-func NoteActionCustomise(writer io.Writer, noteID string, tuneApp *app.App) {
+// definition override file.
+// The edited override file is checked against the Note definition file
+// before it is saved
+func NoteActionCustomise(reader io.Reader, writer io.Writer, noteID string, tuneApp *app.App) {
 	if noteID == "" {
 		PrintHelpAndExit(writer, 1)
 	}
@@ -236,24 +244,34 @@ func NoteActionCustomise(writer io.Writer, noteID string, tuneApp *app.App) {
 		editDestFile = ovFileName
 	}
 
-	changed, err := system.EditAndCheckFile(editSrcFile, editDestFile, noteID, "note")
+	validate := lintValidator(reader, writer, editDestFile, func(tmpFile string) ([]txtparser.LintProblem, error) {
+		return note.LintOverrideFile(tmpFile, fileName)
+	})
+	changed, err := system.EditValidateAndCheckFile(editSrcFile, editDestFile, noteID, "note", validate)
 	if err != nil {
 		system.ErrorExit("Problems while editing note definition file '%s' - %v", editSrcFile, err)
 	}
 	if changed {
-		if _, ok := tuneApp.IsNoteApplied(noteID); !ok {
-			system.NoticeLog("Do not forget to apply the just edited Note to get your changes to take effect\n")
-		} else { // noteID already applied
-			system.NoticeLog("Your just edited Note is already applied. To get your changes to take effect, please 'revert' the Note and apply again.\n")
-		}
+		noteChangedHint(noteID, tuneApp)
 	} else {
 		system.NoticeLog("Nothing changed during the editor session, so no update of the note definition file '%s'", editSrcFile)
 	}
 }
 
+// noteChangedHint reminds the user to apply the just changed Note or to
+// revert and apply the Note again, if it is already applied
+func noteChangedHint(noteID string, tuneApp *app.App) {
+	if _, ok := tuneApp.IsNoteApplied(noteID); !ok {
+		system.NoticeLog("Do not forget to apply the just edited Note to get your changes to take effect\n")
+	} else { // noteID already applied
+		system.NoticeLog("Your just edited Note is already applied. To get your changes to take effect, please 'revert' the Note and apply again.\n")
+	}
+}
+
 // NoteActionEdit allows to editing the custom/vendor specific Note definition
-// file and NOT the override file
-func NoteActionEdit(writer io.Writer, noteID string, tuneApp *app.App) {
+// file and NOT the override file.
+// The edited file is parsed in strict mode before it is saved
+func NoteActionEdit(reader io.Reader, writer io.Writer, noteID string, tuneApp *app.App) {
 	if noteID == "" {
 		PrintHelpAndExit(writer, 1)
 	}
@@ -264,20 +282,15 @@ func NoteActionEdit(writer io.Writer, noteID string, tuneApp *app.App) {
 	ovFileName, overrideNote := getovFile(noteID, OverrideTuningSheets)
 	if !extraNote {
 		system.ErrorExit("The Note definition file you want to edit is a saptune internal (shipped) Note and can NOT be edited. Use 'saptune note customise' instead. Exiting ...")
-	}
-
-	changed, err := system.EditAndCheckFile(fileName, fileName, noteID, "note")
-	if err != nil {
+	} else if changed, err := system.EditValidateAndCheckFile(fileName, fileName, noteID, "note", lintValidator(reader, writer, fileName, parseNoteStrict)); err != nil {
 		system.ErrorExit("Problems while editing Note definition file '%s' - %v", fileName, err)
-	}
-	if changed {
-		if _, ok := tuneApp.IsNoteApplied(noteID); !ok {
-			system.NoticeLog("Do not forget to apply the just edited Note to get your changes to take effect\n")
-		} else { // noteID already applied
-			system.NoticeLog("Your just edited Note is already applied. To get your changes to take effect, please 'revert' the Note and apply again.\n")
-		}
+	} else if changed {
+		noteChangedHint(noteID, tuneApp)
 		if overrideNote {
 			system.NoticeLog("Note override file '%s' exists. Please check, if the content of this file is still valid", ovFileName)
+			chkOverrideFile(ovFileName, func() ([]txtparser.LintProblem, error) {
+				return note.LintOverrideFile(ovFileName, fileName)
+			})
 		}
 
 	} else {
@@ -285,8 +298,9 @@ func NoteActionEdit(writer io.Writer, noteID string, tuneApp *app.App) {
 	}
 }
 
-// NoteActionCreate helps the customer to create an own Note definition
-func NoteActionCreate(writer io.Writer, noteID string, tuneApp *app.App) {
+// NoteActionCreate helps the customer to create an own Note definition.
+// The new Note definition file is parsed in strict mode before it is saved
+func NoteActionCreate(reader io.Reader, writer io.Writer, noteID string, tuneApp *app.App) {
 	if noteID == "" {
 		PrintHelpAndExit(writer, 1)
 	}
@@ -302,7 +316,7 @@ func NoteActionCreate(writer io.Writer, noteID string, tuneApp *app.App) {
 		system.ErrorExit("Note '%s' already exists in %s. Please use 'saptune note edit %s' instead to modify this custom specific Note or 'saptune note customise %s' to create an override file or choose another NoteID.", noteID, ExtraTuningSheets, noteID)
 	}
 
-	changed, err := system.EditAndCheckFile(templateFile, extraFileName, noteID, "note")
+	changed, err := system.EditValidateAndCheckFile(templateFile, extraFileName, noteID, "note", lintValidator(reader, writer, extraFileName, parseNoteStrict))
 	if err != nil {
 		system.ErrorExit("Problems while editing note definition file '%s' - %v", extraFileName, err)
 	}
@@ -313,6 +327,16 @@ func NoteActionCreate(writer io.Writer, noteID string, tuneApp *app.App) {
 	}
 }
 
+// parseNoteStrict parses a Note definition file in strict mode and returns
+// the problems found
+func parseNoteStrict(fileName string) ([]txtparser.LintProblem, error) {
+	_, err := txtparser.ParseINIFileStrict(fileName, note.NoteSections)
+	if perrs, ok := err.(txtparser.ParseErrors); ok {
+		return perrs, nil
+	}
+	return nil, err
+}
+
 // NoteActionShow shows the content of the Note definition file
 func NoteActionShow(writer io.Writer, noteID string, tuneApp *app.App) {
 	if noteID == "" {
@@ -327,10 +351,59 @@ func NoteActionShow(writer io.Writer, noteID string, tuneApp *app.App) {
 		system.ErrorExit("Failed to read file '%s' - %v", fileName, err)
 	}
 	fmt.Fprintf(writer, "\nContent of Note %s:\n%s\n", noteID, string(cont))
+	printOverrideValues(writer, noteID)
+}
+
+// printOverrideValues prints the override values of a Note, which are in
+// effect, together with the override file or drop-in override file, which
+// supplied the value
+func printOverrideValues(writer io.Writer, noteID string) {
+	if len(getOverrideFiles(noteID)) == 0 {
+		return
+	}
+	ow, err := txtparser.ParseOverrides(OverrideTuningSheets, noteID)
+	if err != nil {
+		system.ErrorLog("Problems reading the override files of Note '%s' - %v", noteID, err)
+		return
+	}
+	fmt.Fprintf(writer, "\nOverride values in effect for Note %s:\n", noteID)
+	for _, entry := range ow.AllValues {
+		if entry.Section == note.INISectionVersion || entry.Section == note.INISectionReminder {
+			continue
+		}
+		key := strings.TrimPrefix(entry.Key, overrideKeyPrefix[entry.Section])
+		fmt.Fprintf(writer, "   [%s] %s %s %s  (%s)\n", entry.Section, key, entry.Operator, entry.Value, entry.Source)
+	}
+	fmt.Fprintf(writer, "\n")
+}
+
+// NoteActionLint checks a Note definition file in strict mode and reports
+// all problems with file name and line number.
+// The Note can be given by its NoteID or by the name of the file, which
+// allows to check a Note definition before it is copied to
+// /etc/saptune/extra
+func NoteActionLint(writer io.Writer, noteID string, tuneApp *app.App) {
+	if noteID == "" {
+		PrintHelpAndExit(writer, 1)
+	}
+	fileName := noteID
+	if _, err := os.Stat(fileName); err != nil || !strings.Contains(noteID, "/") {
+		if _, err := tuneApp.GetNoteByID(noteID); err != nil {
+			system.ErrorExit("%v", err)
+		}
+		fileName, _ = getFileName(noteID, NoteTuningSheets, ExtraTuningSheets)
+	}
+	problems, err := note.LintNoteFile(fileName)
+	if err != nil {
+		system.ErrorExit("Failed to read file '%s' - %v", fileName, err)
+	}
+	if printLintProblems(writer, fileName, problems) != 0 {
+		system.ErrorExit("", 1)
+	}
 }
 
 // NoteActionDelete deletes a custom Note definition file and
-// the corresponding override file
+// the corresponding override file and override drop-in directory
 func NoteActionDelete(reader io.Reader, writer io.Writer, noteID string, tuneApp *app.App) {
 	if noteID == "" {
 		PrintHelpAndExit(writer, 1)
@@ -341,7 +414,9 @@ func NoteActionDelete(reader io.Reader, writer io.Writer, noteID string, tuneApp
 
 	txtConfirm := fmt.Sprintf("Do you really want to delete Note (%s)?", noteID)
 	fileName, extraNote := getFileName(noteID, NoteTuningSheets, ExtraTuningSheets)
-	ovFileName, overrideNote := getovFile(noteID, OverrideTuningSheets)
+	ovFileName, overrideFile := getovFile(noteID, OverrideTuningSheets)
+	dropInDir, dropIns := getovDropInDir(noteID)
+	overrideNote := overrideFile || dropIns
 
 	// check, if note is active - applied
 	if _, ok := tuneApp.IsNoteApplied(noteID); ok {
@@ -354,16 +429,21 @@ func NoteActionDelete(reader io.Reader, writer io.Writer, noteID string, tuneApp
 
 	if !extraNote && overrideNote {
 		// system note, override file exists
-		txtConfirm = fmt.Sprintf("Note to delete is a saptune internal (shipped) Note, so it can NOT be deleted. But an override file for the Note exists.\nDo you want to remove the override file for Note %s?", noteID)
+		txtConfirm = fmt.Sprintf("Note to delete is a saptune internal (shipped) Note, so it can NOT be deleted. But override files for the Note exist.\nDo you want to remove the override file and the override drop-in directory for Note %s?", noteID)
 	}
 	if extraNote && overrideNote {
 		// custom note with override file
-		txtConfirm = fmt.Sprintf("Note to delete is a customer/vendor specific Note and an override file for the Note exists.\nDo you want to remove the override file for Note %s?", noteID)
+		txtConfirm = fmt.Sprintf("Note to delete is a customer/vendor specific Note and override files for the Note exist.\nDo you want to remove the override file and the override drop-in directory for Note %s?", noteID)
 	}
 	if overrideNote {
-		// remove override file
+		// remove override file and drop-in directory
 		if readYesNo(txtConfirm, reader, writer) {
-			deleteDefFile(ovFileName)
+			if overrideFile {
+				deleteDefFile(ovFileName)
+			}
+			if dropIns {
+				deleteDropInDir(dropInDir)
+			}
 		}
 	}
 	if extraNote {
@@ -381,7 +461,7 @@ func NoteActionDelete(reader io.Reader, writer io.Writer, noteID string, tuneApp
 }
 
 // NoteActionRename renames a custom Note definition file and
-// the corresponding override file
+// the corresponding override file and override drop-in directory
 func NoteActionRename(reader io.Reader, writer io.Writer, noteID, newNoteID string, tuneApp *app.App) {
 	if noteID == "" || newNoteID == "" {
 		PrintHelpAndExit(writer, 1)
@@ -399,8 +479,14 @@ func NoteActionRename(reader io.Reader, writer io.Writer, noteID, newNoteID stri
 	if !extraNote {
 		system.ErrorExit("The Note definition file you want to rename is a saptune internal (shipped) Note and can NOT be renamed. Exiting ...")
 	}
-	ovFileName, overrideNote := getovFile(noteID, OverrideTuningSheets)
+	ovFileName, overrideFile := getovFile(noteID, OverrideTuningSheets)
 	newovFileName := fmt.Sprintf("%s%s", OverrideTuningSheets, newNoteID)
+	dropInDir, dropIns := getovDropInDir(noteID)
+	newDropInDir, newDropIns := getovDropInDir(newNoteID)
+	overrideNote := overrideFile || dropIns
+	if newDropIns {
+		system.ErrorExit("An override drop-in directory '%s' for the new name '%s' already exists, can't rename.", newDropInDir, newNoteID)
+	}
 
 	// check, if note is active - applied
 	if _, ok := tuneApp.IsNoteApplied(noteID); ok {
@@ -413,7 +499,7 @@ func NoteActionRename(reader io.Reader, writer io.Writer, noteID, newNoteID stri
 
 	if extraNote && overrideNote {
 		// custom note with override file
-		txtConfirm = fmt.Sprintf("Note to rename is a customer/vendor specific Note.\nDo you really want to rename this Note (%s) and the corresponding override file to the new name '%s'?", noteID, newNoteID)
+		txtConfirm = fmt.Sprintf("Note to rename is a customer/vendor specific Note.\nDo you really want to rename this Note (%s) and the corresponding override files to the new name '%s'?", noteID, newNoteID)
 	}
 	if extraNote && !overrideNote {
 		// custom note
@@ -422,9 +508,12 @@ func NoteActionRename(reader io.Reader, writer io.Writer, noteID, newNoteID stri
 
 	if readYesNo(txtConfirm, reader, writer) {
 		renameDefFile(fileName, newFileName)
-		if overrideNote {
+		if overrideFile {
 			renameDefFile(ovFileName, newovFileName)
 		}
+		if dropIns {
+			renameDefFile(dropInDir, newDropInDir)
+		}
 	}
 }
 
@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"github.com/SUSE/saptune/app"
@@ -355,7 +356,7 @@ func TestNoteActionCreate(t *testing.T) {
 	nID := "hugo"
 	createMatchText := "ERROR: Problems while editing note definition file '/etc/saptune/extra/hugo.conf' - open /usr/share/saptune/NoteTemplate.conf: no such file or directory\n"
 	cMatchText := ""
-	NoteActionCreate(&createBuf, nID, nApp)
+	NoteActionCreate(strings.NewReader(""), &createBuf, nID, nApp)
 	txt := createBuf.String()
 	checkOut(t, txt, cMatchText)
 	if tstRetErrorExit != 1 {
@@ -375,7 +376,7 @@ func TestNoteActionCreate(t *testing.T) {
 	defer func() { ExtraTuningSheets = oldExtraTuningSheets }()
 	ExtraTuningSheets = ExtraFilesInGOPATH
 	fname := fmt.Sprintf("%s%s.conf", ExtraTuningSheets, nID)
-	NoteActionCreate(&createBuf, nID, nApp)
+	NoteActionCreate(strings.NewReader(""), &createBuf, nID, nApp)
 	txt = createBuf.String()
 	checkOut(t, txt, cMatchText)
 	if tstRetErrorExit != -1 {
@@ -394,7 +395,7 @@ func TestNoteActionCreate(t *testing.T) {
 	createMatchText = PrintHelpAndExitMatchText
 	cMatchText = ""
 	tstRetErrorExit = -1
-	NoteActionCreate(&createBuf, "", nApp)
+	NoteActionCreate(strings.NewReader(""), &createBuf, "", nApp)
 	txt = createBuf.String()
 	checkOut(t, txt, createMatchText)
 	if tstRetErrorExit != 1 {
@@ -544,6 +545,54 @@ net.ipv4.ip_local_port_range = 31768 61999
 	}
 }
 
+func TestNoteActionRenameDeleteDropIns(t *testing.T) {
+	tstDir := "/tmp/saptune_note_dropin_test/"
+	defer os.RemoveAll(tstDir)
+	oldNoteTuningSheets := NoteTuningSheets
+	defer func() { NoteTuningSheets = oldNoteTuningSheets }()
+	NoteTuningSheets = ""
+	oldExtraTuningSheets := ExtraTuningSheets
+	defer func() { ExtraTuningSheets = oldExtraTuningSheets }()
+	ExtraTuningSheets = tstDir + "extra/"
+	oldOverrideTuningSheets := OverrideTuningSheets
+	defer func() { OverrideTuningSheets = oldOverrideTuningSheets }()
+	OverrideTuningSheets = tstDir + "override/"
+	_ = os.MkdirAll(ExtraTuningSheets, 0755)
+	_ = os.MkdirAll(OverrideTuningSheets+"dropInNote.d", 0755)
+	if err := system.CopyFile(ExtraFilesInGOPATH+"simpleNote.conf", ExtraTuningSheets+"dropInNote.conf"); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.WriteFile(OverrideTuningSheets+"dropInNote.d/50-tst.conf", []byte("[sysctl]\nnet.ipv4.ip_local_port_range = 32768 60999\n"), 0644)
+	dApp := app.InitialiseApp(TstFilesInGOPATH, "", note.GetTuningOptions("", ExtraTuningSheets), AllTestSolutions)
+
+	// rename moves the drop-in directory
+	buffer := bytes.Buffer{}
+	NoteActionRename(strings.NewReader("yes\n"), &buffer, "dropInNote", "dropInRenamed", dApp)
+	if !strings.Contains(buffer.String(), "and the corresponding override files to the new name 'dropInRenamed'") {
+		t.Errorf("wrong confirmation text '%s'", buffer.String())
+	}
+	if _, err := os.Stat(OverrideTuningSheets + "dropInNote.d"); !os.IsNotExist(err) {
+		t.Error("drop-in directory of the old Note still exists")
+	}
+	if _, err := os.Stat(OverrideTuningSheets + "dropInRenamed.d/50-tst.conf"); err != nil {
+		t.Errorf("drop-in directory not renamed: %v", err)
+	}
+
+	// delete removes the drop-in directory
+	rApp := app.InitialiseApp(TstFilesInGOPATH, "", note.GetTuningOptions("", ExtraTuningSheets), AllTestSolutions)
+	buffer.Reset()
+	NoteActionDelete(bufio.NewReader(strings.NewReader("yes\nyes\n")), &buffer, "dropInRenamed", rApp)
+	if !strings.Contains(buffer.String(), "remove the override file and the override drop-in directory for Note dropInRenamed") {
+		t.Errorf("wrong confirmation text '%s'", buffer.String())
+	}
+	if _, err := os.Stat(OverrideTuningSheets + "dropInRenamed.d"); !os.IsNotExist(err) {
+		t.Error("drop-in directory not removed")
+	}
+	if _, err := os.Stat(ExtraTuningSheets + "dropInRenamed.conf"); !os.IsNotExist(err) {
+		t.Error("Note definition file not removed")
+	}
+}
+
 func TestNoteActionCustomise(t *testing.T) {
 	tstRetErrorExit = -1
 	oldOSExit := system.OSExit
@@ -582,7 +631,7 @@ Run "saptune note list" for a complete list of supported notes.
 and then please double check your input
 ERROR: Problems while editing note definition file '/home/ci_tst/gopath/src/github.com/SUSE/saptune/testdata/etc/saptune/override/' - write /tmp/.sttemp: copy_file_range: is a directory
 `
-	NoteActionCustomise(&custBuffer, "", cApp)
+	NoteActionCustomise(strings.NewReader(""), &custBuffer, "", cApp)
 	if tstRetErrorExit != 1 {
 		t.Errorf("error exit should be '1' and NOT '%v'\n", tstRetErrorExit)
 	}
@@ -592,7 +641,14 @@ ERROR: Problems while editing note definition file '/home/ci_tst/gopath/src/gith
 	// change EDITOR command
 	fakeEditorCommand := path.Join(TstFilesInGOPATH, "tstedit")
 	os.Setenv("EDITOR", fakeEditorCommand)
-	editorTxt := `Hello from test editor
+	editorTxt := `[version]
+VERSION=1
+DATE=19.10.2026
+DESCRIPTION=Note written by test editor
+REFERENCES=https://www.suse.com
+
+[vm]
+THP=never
 `
 
 	// test with existing override file - testNote (not applied)
@@ -601,29 +657,48 @@ ERROR: Problems while editing note definition file '/home/ci_tst/gopath/src/gith
 	errMatchText = ""
 	tstRetErrorExit = -1
 	errMatchText = ""
+	// the test editor writes an invalid override file, edit it once
+	// again and abort then
 	overFileName := path.Join(OverTstFilesInGOPATH, "testNote")
-	NoteActionCustomise(&custBuffer, "testNote", cApp)
+	custMatchText = `
+The edited file contains 1 problem(s) and can not be saved as '/home/ci_tst/gopath/src/github.com/SUSE/saptune/testdata/etc/saptune/override/testNote':
+  /home/ci_tst/gopath/src/github.com/SUSE/saptune/testdata/etc/saptune/override/testNote:1: line 'Hello from test editor' is not part of a section
+Do you want to edit the file again? Otherwise your changes will be discarded. [y/n]: 
+The edited file contains 1 problem(s) and can not be saved as '/home/ci_tst/gopath/src/github.com/SUSE/saptune/testdata/etc/saptune/override/testNote':
+  /home/ci_tst/gopath/src/github.com/SUSE/saptune/testdata/etc/saptune/override/testNote:1: line 'Hello from test editor' is not part of a section
+Do you want to edit the file again? Otherwise your changes will be discarded. [y/n]: `
+	errMatchText = `ERROR: Problems while editing note definition file '/home/ci_tst/gopath/src/github.com/SUSE/saptune/testdata/etc/saptune/override/testNote' - editing aborted, changes discarded
+`
+	NoteActionCustomise(strings.NewReader("y\nn\n"), &custBuffer, "testNote", cApp)
 	custTxt := custBuffer.String()
 	checkOut(t, custTxt, custMatchText)
 	cont, err := system.ReadConfigFile(overFileName, false)
 	if err != nil {
 		t.Error(err)
 	}
-	if string(cont) != editorTxt {
-		t.Errorf("got: '%+v', expected: '%s'\n", string(cont), editorTxt)
+	if string(cont) != "ANGI\n" {
+		t.Errorf("got: '%+v', expected: 'ANGI'\n", string(cont))
 	}
-	if tstRetErrorExit != -1 {
-		t.Errorf("error exit should be '-1' and NOT '%v'\n", tstRetErrorExit)
+	if tstRetErrorExit != 1 {
+		t.Errorf("error exit should be '1' and NOT '%v'\n", tstRetErrorExit)
 	}
 	txt = buffer.String()
 	checkOut(t, txt, errMatchText)
 
+	// change EDITOR command to write a valid override file
+	fakeEditorCommand = path.Join(TstFilesInGOPATH, "tstedit-override")
+	os.Setenv("EDITOR", fakeEditorCommand)
+	editorTxt = `[version]
+# override written by test editor
+`
+
 	// test without override file - test2Note (applied Note)
 	buffer.Reset()
 	custBuffer.Reset()
 	errMatchText = ""
 	tstRetErrorExit = -1
 	errMatchText = ""
+	custMatchText = ""
 	// fake note applied
 	cApp.NoteApplyOrder = []string{"test2Note"}
 	emptySrc := path.Join(TstFilesInGOPATH, "saptune_NOEXIT")
@@ -635,7 +710,7 @@ ERROR: Problems while editing note definition file '/home/ci_tst/gopath/src/gith
 	defer os.RemoveAll(path.Join(TstFilesInGOPATH, "/data"))
 
 	overFileName = path.Join(OverTstFilesInGOPATH, "test2Note")
-	NoteActionCustomise(&custBuffer, "test2Note", cApp)
+	NoteActionCustomise(strings.NewReader(""), &custBuffer, "test2Note", cApp)
 	custTxt = custBuffer.String()
 	checkOut(t, custTxt, custMatchText)
 	if _, err := os.Stat(overFileName); os.IsNotExist(err) {
@@ -665,7 +740,7 @@ ERROR: Problems while editing note definition file '/home/ci_tst/gopath/src/gith
 	tstRetErrorExit = -1
 	errMatchText = ""
 	overFileName = path.Join(OverTstFilesInGOPATH, "test2Note")
-	NoteActionCustomise(&custBuffer, "test2Note", cApp)
+	NoteActionCustomise(strings.NewReader(""), &custBuffer, "test2Note", cApp)
 	custTxt = custBuffer.String()
 	checkOut(t, custTxt, custMatchText)
 	if tstRetErrorExit != -1 {
@@ -704,14 +779,27 @@ func TestNoteActionEdit(t *testing.T) {
 
 	oldExtraTuningSheets := ExtraTuningSheets
 	defer func() { ExtraTuningSheets = oldExtraTuningSheets }()
-	ExtraTuningSheets = ExtraTstFilesInGOPATH
+	// the editor changes the extra Notes, so work on a copy
+	extraDir := "/tmp/saptune_edit_extra/"
+	os.RemoveAll(extraDir)
+	defer os.RemoveAll(extraDir)
+	if err := os.MkdirAll(extraDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	_, extraFiles := system.ListDir(ExtraTstFilesInGOPATH, "")
+	for _, extraFile := range extraFiles {
+		if err := system.CopyFile(path.Join(ExtraTstFilesInGOPATH, extraFile), path.Join(extraDir, extraFile)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ExtraTuningSheets = extraDir
 	oldOverrideTuningSheets := OverrideTuningSheets
 	defer func() { OverrideTuningSheets = oldOverrideTuningSheets }()
 	OverrideTuningSheets = OverTstFilesInGOPATH
 	oldNoteTuningSheets := NoteTuningSheets
 	defer func() { NoteTuningSheets = oldNoteTuningSheets }()
 	NoteTuningSheets = TstFilesInGOPATH
-	newTuningOpts := note.GetTuningOptions(TstFilesInGOPATH, ExtraTstFilesInGOPATH)
+	newTuningOpts := note.GetTuningOptions(TstFilesInGOPATH, extraDir)
 	eApp := app.InitialiseApp(TstFilesInGOPATH, "", newTuningOpts, AllTestSolutions)
 
 	// test with empty noteID
@@ -724,9 +812,8 @@ func TestNoteActionEdit(t *testing.T) {
 Run "saptune note list" for a complete list of supported notes.
 and then please double check your input
 ERROR: The Note definition file you want to edit is a saptune internal (shipped) Note and can NOT be edited. Use 'saptune note customise' instead. Exiting ...
-ERROR: Problems while editing Note definition file '/home/ci_tst/gopath/src/github.com/SUSE/saptune/testdata/' - write /tmp/.sttemp: copy_file_range: is a directory
 `
-	NoteActionEdit(&editBuffer, "", eApp)
+	NoteActionEdit(strings.NewReader(""), &editBuffer, "", eApp)
 	if tstRetErrorExit != 1 {
 		t.Errorf("error exit should be '1' and NOT '%v'\n", tstRetErrorExit)
 	}
@@ -736,7 +823,14 @@ ERROR: Problems while editing Note definition file '/home/ci_tst/gopath/src/gith
 	// change EDITOR command
 	fakeEditorCommand := path.Join(TstFilesInGOPATH, "tstedit")
 	os.Setenv("EDITOR", fakeEditorCommand)
-	editorTxt := `Hello from test editor
+	editorTxt := `[version]
+VERSION=1
+DATE=19.10.2026
+DESCRIPTION=Note written by test editor
+REFERENCES=https://www.suse.com
+
+[vm]
+THP=never
 `
 
 	// test with a system note - testNote
@@ -747,7 +841,7 @@ ERROR: Problems while editing Note definition file '/home/ci_tst/gopath/src/gith
 	errMatchText = `ERROR: The Note definition file you want to edit is a saptune internal (shipped) Note and can NOT be edited. Use 'saptune note customise' instead. Exiting ...
 `
 	tstRetErrorExit = -1
-	NoteActionEdit(&editBuffer, "testNote", eApp)
+	NoteActionEdit(strings.NewReader(""), &editBuffer, "testNote", eApp)
 	editTxt := editBuffer.String()
 	checkOut(t, editTxt, editMatchText)
 	if tstRetErrorExit != 1 {
@@ -756,14 +850,38 @@ ERROR: Problems while editing Note definition file '/home/ci_tst/gopath/src/gith
 	txt = buffer.String()
 	checkOut(t, txt, errMatchText)
 
+	// test with invalid Note definition, editing aborted - extraTestNote
+	buffer.Reset()
+	editBuffer.Reset()
+	tstRetErrorExit = -1
+	extraFileName := path.Join(extraDir, "extraTestNote.conf")
+	origCont, err := os.ReadFile(extraFileName)
+	if err != nil {
+		t.Error(err)
+	}
+	NoteActionEdit(strings.NewReader("n\n"), &editBuffer, "extraTestNote", eApp)
+	editTxt = editBuffer.String()
+	if !strings.Contains(editTxt, "The edited file contains 2 problem(s) and can not be saved as '"+extraFileName+"':") || !strings.Contains(editTxt, extraFileName+":1: line 'Hello from test editor' is not part of a section") {
+		t.Errorf("missing problem report, got: '%s'\n", editTxt)
+	}
+	if tstRetErrorExit != 1 {
+		t.Errorf("error exit should be '1' and NOT '%v'\n", tstRetErrorExit)
+	}
+	if cont, _ := os.ReadFile(extraFileName); string(cont) != string(origCont) {
+		t.Errorf("Note definition file changed even that editing was aborted: '%s'\n", string(cont))
+	}
+
+	// valid Note definition
+	fakeEditorCommand = path.Join(TstFilesInGOPATH, "tstedit-note")
+	os.Setenv("EDITOR", fakeEditorCommand)
+
 	// test with existing override file - extraTestNote (not applied)
 	buffer.Reset()
 	editBuffer.Reset()
 	editMatchText = ""
 	errMatchText = ""
 	tstRetErrorExit = -1
-	extraFileName := path.Join(ExtraTstFilesInGOPATH, "extraTestNote.conf")
-	NoteActionEdit(&editBuffer, "extraTestNote", eApp)
+	NoteActionEdit(strings.NewReader(""), &editBuffer, "extraTestNote", eApp)
 	editTxt = editBuffer.String()
 	checkOut(t, editTxt, editMatchText)
 	cont, err := system.ReadConfigFile(extraFileName, false)
@@ -795,8 +913,8 @@ ERROR: Problems while editing Note definition file '/home/ci_tst/gopath/src/gith
 	}
 	defer os.RemoveAll(path.Join(TstFilesInGOPATH, "/data"))
 
-	extraFileName = path.Join(ExtraTstFilesInGOPATH, "extraTest2Note.conf")
-	NoteActionEdit(&editBuffer, "extraTest2Note", eApp)
+	extraFileName = path.Join(extraDir, "extraTest2Note.conf")
+	NoteActionEdit(strings.NewReader(""), &editBuffer, "extraTest2Note", eApp)
 	editTxt = editBuffer.String()
 	checkOut(t, editTxt, editMatchText)
 	cont, err = system.ReadConfigFile(extraFileName, false)
@@ -820,7 +938,7 @@ ERROR: Problems while editing Note definition file '/home/ci_tst/gopath/src/gith
 	errMatchText = ""
 	tstRetErrorExit = -1
 	errMatchText = ""
-	NoteActionEdit(&editBuffer, "extraTest2Note", eApp)
+	NoteActionEdit(strings.NewReader(""), &editBuffer, "extraTest2Note", eApp)
 	editTxt = editBuffer.String()
 	checkOut(t, editTxt, editMatchText)
 	if tstRetErrorExit != -1 {
@@ -0,0 +1,260 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// overrideKeyPrefix contains the prefix, which the parser adds to the
+// parameter names of some sections
+var overrideKeyPrefix = map[string]string{
+	note.INISectionGrub:    "grub:",
+	note.INISectionSys:     "sys:",
+	note.INISectionService: "systemd:",
+}
+
+// NoteActionOverride manages the override file of a Note without an editor
+// session, so that it can be used by configuration management tools.
+// args contains the override action, the NoteID and the parameters of the
+// override action
+func NoteActionOverride(writer io.Writer, args []string, tuneApp *app.App) {
+	if len(args) < 2 {
+		PrintHelpAndExit(writer, 1)
+	}
+	action := args[0]
+	noteID := args[1]
+	params := args[2:]
+	if _, err := tuneApp.GetNoteByID(noteID); err != nil {
+		system.ErrorExit("%v", err)
+	}
+	switch action {
+	case "set":
+		if len(params) == 2 {
+			NoteActionOverrideSet(noteID, "", params[0], params[1], tuneApp)
+		} else if len(params) == 3 {
+			NoteActionOverrideSet(noteID, params[0], params[1], params[2], tuneApp)
+		} else {
+			PrintHelpAndExit(writer, 1)
+		}
+	case "unset":
+		if len(params) == 1 {
+			NoteActionOverrideUnset(noteID, "", params[0], tuneApp)
+		} else if len(params) == 2 {
+			NoteActionOverrideUnset(noteID, params[0], params[1], tuneApp)
+		} else {
+			PrintHelpAndExit(writer, 1)
+		}
+	case "show":
+		if len(params) != 0 {
+			PrintHelpAndExit(writer, 1)
+		}
+		NoteActionOverrideShow(writer, noteID)
+	case "clear":
+		if len(params) != 0 {
+			PrintHelpAndExit(writer, 1)
+		}
+		NoteActionOverrideClear(noteID, tuneApp)
+	default:
+		PrintHelpAndExit(writer, 1)
+	}
+}
+
+// NoteActionOverrideSet sets a parameter in the override file of a Note.
+// The parameter needs to be part of the Note definition. If no section is
+// given, the section is taken from the Note definition. The override file
+// is created, if it does not exist
+func NoteActionOverrideSet(noteID, section, key, value string, tuneApp *app.App) {
+	fileName, _ := getFileName(noteID, NoteTuningSheets, ExtraTuningSheets)
+	header, op, err := getOverrideSection(fileName, section, key)
+	if err != nil {
+		system.ErrorExit("%v", err)
+	} else {
+		setOverrideEntry(noteID, fileName, section, header, key, string(op), value, tuneApp)
+	}
+}
+
+// setOverrideEntry sets the parameter in the override file of a Note. The
+// section of the override file, which already contains the parameter, is
+// preferred over the section header of the Note definition
+func setOverrideEntry(noteID, fileName, section, header, key, op, value string, tuneApp *app.App) {
+	ovFileName, overrideNote := getovFile(noteID, OverrideTuningSheets)
+	content := ""
+	if overrideNote {
+		cont, err := os.ReadFile(ovFileName)
+		if err != nil {
+			system.ErrorExit("Failed to read file '%s' - %v", ovFileName, err)
+		}
+		content = string(cont)
+		// prefer the section of the override file, which already
+		// contains the parameter
+		if ovHeader, _, err := getOverrideSection(ovFileName, section, key); err == nil {
+			header = ovHeader
+		}
+	}
+	newContent := txtparser.SetINIEntry(content, header, key, op, value)
+	if newContent == content {
+		system.NoticeLog("Parameter '%s' is already set to '%s' in the override file '%s', nothing to do", key, value, ovFileName)
+		return
+	}
+	writeOverrideFile(ovFileName, fileName, newContent)
+	system.NoticeLog("Parameter '%s' of section [%s] set to '%s' in the override file '%s'", key, header, value, ovFileName)
+	noteChangedHint(noteID, tuneApp)
+}
+
+// NoteActionOverrideUnset removes a parameter from the override file of a
+// Note, so that the value of the Note definition is used again. If no
+// section is given, the section is taken from the override file.
+// The override file is removed, if no parameter is left
+func NoteActionOverrideUnset(noteID, section, key string, tuneApp *app.App) {
+	fileName, _ := getFileName(noteID, NoteTuningSheets, ExtraTuningSheets)
+	ovFileName, overrideNote := getovFile(noteID, OverrideTuningSheets)
+	if !overrideNote {
+		system.NoticeLog("No override file available for Note '%s', nothing to do", noteID)
+		return
+	}
+	header, _, err := getOverrideSection(ovFileName, section, key)
+	if err != nil {
+		system.NoticeLog("Nothing to do, %v", err)
+		return
+	}
+	cont, err := os.ReadFile(ovFileName)
+	if err != nil {
+		system.ErrorExit("Failed to read file '%s' - %v", ovFileName, err)
+	}
+	newContent, _ := txtparser.RemoveINIEntry(string(cont), header, key)
+	if res := txtparser.LintINI(ovFileName, newContent, note.NoteSections); len(res.Entries) == 0 {
+		// no parameter left, so the override file is no longer needed
+		if err := os.Remove(ovFileName); err != nil {
+			system.ErrorExit("Failed to remove file '%s' - %v", ovFileName, err)
+		}
+		system.NoticeLog("Parameter '%s' removed. No parameter left, so the override file '%s' was removed", key, ovFileName)
+	} else {
+		writeOverrideFile(ovFileName, fileName, newContent)
+		system.NoticeLog("Parameter '%s' of section [%s] removed from the override file '%s'", key, header, ovFileName)
+	}
+	noteChangedHint(noteID, tuneApp)
+}
+
+// NoteActionOverrideShow shows the content of the override file and of the
+// override drop-in files of a Note
+func NoteActionOverrideShow(writer io.Writer, noteID string) {
+	ovFiles := getOverrideFiles(noteID)
+	if len(ovFiles) == 0 {
+		system.NoticeLog("No override file available for Note '%s'", noteID)
+		return
+	}
+	for _, ovFileName := range ovFiles {
+		cont, err := os.ReadFile(ovFileName)
+		if err != nil {
+			system.ErrorExit("Failed to read file '%s' - %v", ovFileName, err)
+		}
+		fmt.Fprintf(writer, "\nContent of override file '%s' of Note %s:\n%s\n", ovFileName, noteID, string(cont))
+	}
+}
+
+// NoteActionOverrideClear removes the override file and the override
+// drop-in directory (NOTEID.d) of a Note without confirmation
+func NoteActionOverrideClear(noteID string, tuneApp *app.App) {
+	ovFileName, overrideNote := getovFile(noteID, OverrideTuningSheets)
+	dropInDir, dropIns := getovDropInDir(noteID)
+	if !overrideNote && !dropIns {
+		system.NoticeLog("No override file or override drop-in directory available for Note '%s', nothing to do", noteID)
+		return
+	}
+	if overrideNote {
+		if err := os.Remove(ovFileName); err != nil {
+			system.ErrorExit("Failed to remove file '%s' - %v", ovFileName, err)
+		}
+		system.NoticeLog("Override file '%s' removed", ovFileName)
+	}
+	if dropIns {
+		if err := os.RemoveAll(dropInDir); err != nil {
+			system.ErrorExit("Failed to remove directory '%s' - %v", dropInDir, err)
+		}
+		system.NoticeLog("Override drop-in directory '%s' removed", dropInDir)
+	}
+	noteChangedHint(noteID, tuneApp)
+}
+
+// getOverrideSection returns the section header of a parameter of a
+// definition file and the operator used for the parameter.
+// If a section is given (name with or without section tags), the parameter
+// needs to be part of this section. If no section is given, the parameter
+// needs to be unique in the file
+func getOverrideSection(fileName, section, key string) (string, txtparser.Operator, error) {
+	sectName := strings.Split(section, ":")[0]
+	if sectName == note.INISectionVersion || sectName == note.INISectionRpm || sectName == note.INISectionReminder {
+		return "", "", fmt.Errorf("parameters of section [%s] can not be overridden", sectName)
+	}
+	res, err := txtparser.LintINIFile(fileName, note.NoteSections)
+	if err != nil {
+		return "", "", err
+	}
+	var op txtparser.Operator
+	headers := []string{}
+	found := make(map[string]bool)
+	for _, entry := range res.Entries {
+		if strings.TrimPrefix(entry.Key, overrideKeyPrefix[entry.Section]) != key {
+			continue
+		}
+		if (strings.Contains(section, ":") && entry.Header != section) || (sectName != "" && entry.Section != sectName) {
+			continue
+		}
+		if !found[entry.Header] {
+			found[entry.Header] = true
+			headers = append(headers, entry.Header)
+		}
+		op = entry.Operator
+	}
+	if len(headers) == 0 {
+		if section != "" {
+			return "", "", fmt.Errorf("parameter '%s' is not part of section [%s] of '%s'", key, section, fileName)
+		}
+		return "", "", fmt.Errorf("parameter '%s' is not part of '%s'", key, fileName)
+	}
+	if len(headers) > 1 {
+		return "", "", fmt.Errorf("parameter '%s' is defined in more than one section ([%s]) of '%s'. Please specify the section", key, strings.Join(headers, "], ["), fileName)
+	}
+	if op == "" {
+		op = txtparser.OperatorEqual
+	}
+	return headers[0], op, nil
+}
+
+// writeOverrideFile checks the new content of an override file against the
+// Note definition file and writes the override file, if no problems are
+// found. Warnings do not prevent the change
+func writeOverrideFile(ovFileName, noteFile, content string) {
+	tmpFile := fmt.Sprintf("/tmp/%s.sttemp", path.Base(ovFileName))
+	defer os.Remove(tmpFile)
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		system.ErrorExit("Failed to write file '%s' - %v", tmpFile, err)
+	}
+	problems, err := note.LintOverrideFile(tmpFile, noteFile)
+	if err != nil {
+		system.ErrorExit("Problems while checking the override file '%s' - %v", ovFileName, err)
+	}
+	errs, warnings := txtparser.SplitLintProblems(problems)
+	for _, warning := range warnings {
+		warning.File = ovFileName
+		system.WarningLog("%s", warning.String())
+	}
+	if len(errs) != 0 {
+		for _, prob := range errs {
+			prob.File = ovFileName
+			system.ErrorLog("%s", prob.String())
+		}
+		system.ErrorExit("The override file '%s' would contain %d problem(s), so it is not changed", ovFileName, len(errs))
+	} else if err := os.MkdirAll(path.Dir(ovFileName), 0755); err != nil {
+		system.ErrorExit("Failed to create directory '%s' - %v", path.Dir(ovFileName), err)
+	} else if err := os.WriteFile(ovFileName, []byte(content), 0644); err != nil {
+		system.ErrorExit("Failed to write file '%s' - %v", ovFileName, err)
+	}
+}
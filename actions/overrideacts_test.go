@@ -0,0 +1,145 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+var ovTestNote = `[version]
+VERSION=1
+DATE=01.01.2024
+DESCRIPTION=override test
+REFERENCES=https://me.sap.com
+
+[vm]
+THP=never
+KSM=0
+
+[grub:csp=aws]
+intel_idle.max_cstate=1
+
+[grub:csp=google]
+intel_idle.max_cstate=1
+`
+
+func TestNoteActionOverride(t *testing.T) {
+	tstRetErrorExit = -1
+	oldOSExit := system.OSExit
+	defer func() { system.OSExit = oldOSExit }()
+	system.OSExit = tstosExit
+	oldErrorExitOut := system.ErrorExitOut
+	defer func() { system.ErrorExitOut = oldErrorExitOut }()
+	system.ErrorExitOut = tstErrorExitOut
+	buffer := bytes.Buffer{}
+	tstwriter = &buffer
+
+	tstDir := "/tmp/saptune_override_test/"
+	defer os.RemoveAll(tstDir)
+	oldNoteTuningSheets := NoteTuningSheets
+	defer func() { NoteTuningSheets = oldNoteTuningSheets }()
+	NoteTuningSheets = path.Join(tstDir, "notes") + "/"
+	oldExtraTuningSheets := ExtraTuningSheets
+	defer func() { ExtraTuningSheets = oldExtraTuningSheets }()
+	ExtraTuningSheets = path.Join(tstDir, "extra") + "/"
+	oldOverrideTuningSheets := OverrideTuningSheets
+	defer func() { OverrideTuningSheets = oldOverrideTuningSheets }()
+	OverrideTuningSheets = path.Join(tstDir, "override") + "/"
+	for _, dir := range []string{NoteTuningSheets, ExtraTuningSheets} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(path.Join(ExtraTuningSheets, "ovTest.conf"), []byte(ovTestNote), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ovFileName := path.Join(OverrideTuningSheets, "ovTest")
+	oApp := app.InitialiseApp(TstFilesInGOPATH, "", note.GetTuningOptions(NoteTuningSheets, ExtraTuningSheets), AllTestSolutions)
+
+	// set parameter, section taken from the Note
+	NoteActionOverride(&buffer, []string{"set", "ovTest", "THP", "madvise"}, oApp)
+	cont, _ := os.ReadFile(ovFileName)
+	if string(cont) != "[vm]\nTHP=madvise\n" {
+		t.Errorf("got: '%s'", string(cont))
+	}
+	// change parameter and add an empty value
+	NoteActionOverride(&buffer, []string{"set", "ovTest", "vm", "THP", "always"}, oApp)
+	NoteActionOverride(&buffer, []string{"set", "ovTest", "KSM", ""}, oApp)
+	cont, _ = os.ReadFile(ovFileName)
+	if string(cont) != "[vm]\nTHP=always\nKSM=\n" {
+		t.Errorf("got: '%s'", string(cont))
+	}
+	if tstRetErrorExit != -1 {
+		t.Errorf("error exit should be '-1' and NOT '%v'\n", tstRetErrorExit)
+	}
+
+	// invalid value, unknown parameter, ambiguous section
+	for _, args := range [][]string{{"set", "ovTest", "THP", "sometimes"}, {"set", "ovTest", "HUGE", "1"}, {"set", "ovTest", "intel_idle.max_cstate", "2"}, {"set", "ovTest", "version", "VERSION", "2"}} {
+		tstRetErrorExit = -1
+		NoteActionOverride(&buffer, args, oApp)
+		if tstRetErrorExit != 1 {
+			t.Errorf("%v: error exit should be '1' and NOT '%v'\n", args, tstRetErrorExit)
+		}
+	}
+	cont, _ = os.ReadFile(ovFileName)
+	if string(cont) != "[vm]\nTHP=always\nKSM=\n" {
+		t.Errorf("override file changed, got: '%s'", string(cont))
+	}
+	if !strings.Contains(buffer.String(), "defined in more than one section ([grub:csp=aws], [grub:csp=google])") {
+		t.Errorf("missing ambiguous section message in '%s'", buffer.String())
+	}
+
+	// section with tags
+	tstRetErrorExit = -1
+	NoteActionOverride(&buffer, []string{"set", "ovTest", "grub:csp=aws", "intel_idle.max_cstate", "2"}, oApp)
+	cont, _ = os.ReadFile(ovFileName)
+	if string(cont) != "[vm]\nTHP=always\nKSM=\n\n[grub:csp=aws]\nintel_idle.max_cstate=2\n" {
+		t.Errorf("got: '%s'", string(cont))
+	}
+
+	// show
+	showBuffer := bytes.Buffer{}
+	NoteActionOverride(&showBuffer, []string{"show", "ovTest"}, oApp)
+	if !strings.Contains(showBuffer.String(), "intel_idle.max_cstate=2") {
+		t.Errorf("got: '%s'", showBuffer.String())
+	}
+
+	// unset
+	NoteActionOverride(&buffer, []string{"unset", "ovTest", "intel_idle.max_cstate"}, oApp)
+	NoteActionOverride(&buffer, []string{"unset", "ovTest", "KSM"}, oApp)
+	cont, _ = os.ReadFile(ovFileName)
+	if string(cont) != "[vm]\nTHP=always\n" {
+		t.Errorf("got: '%s'", string(cont))
+	}
+	// last parameter removes the override file
+	NoteActionOverride(&buffer, []string{"unset", "ovTest", "THP"}, oApp)
+	if _, err := os.Stat(ovFileName); !os.IsNotExist(err) {
+		t.Errorf("override file '%s' should be removed", ovFileName)
+	}
+
+	// clear, including the override drop-in directory
+	NoteActionOverride(&buffer, []string{"set", "ovTest", "THP", "never"}, oApp)
+	dropIn := path.Join(OverrideTuningSheets, "ovTest.d", "50-tst.conf")
+	_ = os.MkdirAll(path.Dir(dropIn), 0755)
+	_ = os.WriteFile(dropIn, []byte("[vm]\nKSM=1\n"), 0644)
+	showBuffer.Reset()
+	NoteActionOverride(&showBuffer, []string{"show", "ovTest"}, oApp)
+	if !strings.Contains(showBuffer.String(), "THP=never") || !strings.Contains(showBuffer.String(), "Content of override file '"+dropIn+"' of Note ovTest:\n[vm]\nKSM=1\n") {
+		t.Errorf("got: '%s'", showBuffer.String())
+	}
+	NoteActionOverride(&buffer, []string{"clear", "ovTest"}, oApp)
+	if _, err := os.Stat(ovFileName); !os.IsNotExist(err) {
+		t.Errorf("override file '%s' should be removed", ovFileName)
+	}
+	if _, err := os.Stat(path.Dir(dropIn)); !os.IsNotExist(err) {
+		t.Errorf("override drop-in directory '%s' should be removed", path.Dir(dropIn))
+	}
+	if tstRetErrorExit != -1 {
+		t.Errorf("error exit should be '-1' and NOT '%v'\n", tstRetErrorExit)
+	}
+}
@@ -0,0 +1,283 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/system"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// reportNote is the data of one Note in the html report
+type reportNote struct {
+	NoteID     string
+	NoteVers   string
+	Compliant  bool
+	Lines      []system.JPNotesLine
+	Footnotes  []string
+	Attention  string
+	Override   string
+	OverParams []system.JPNotesLine
+}
+
+// reportData is the data rendered into the html report
+type reportData struct {
+	Created         string
+	Hostname        string
+	Vendor          string
+	Model           string
+	CSP             string
+	OsName          string
+	OsVers          string
+	Virtualization  string
+	RPMVersion      string
+	SaptuneVersion  string
+	Solutions       []string
+	EnabledNotes    []string
+	AppliedNotes    []string
+	Notes           []reportNote
+	SysCompliance   bool
+	Verified        bool
+	StagingEnabled  bool
+	StagedNotes     []string
+	StagedSolutions []string
+}
+
+// ReportAction creates compliance reports
+func ReportAction(writer io.Writer, actionName, saptuneVers string, tuneApp *app.App) {
+	switch actionName {
+	case "html":
+		ReportActionHTML(writer, system.GetFlagVal("output"), saptuneVers, tuneApp)
+	default:
+		PrintHelpAndExit(writer, 1)
+	}
+}
+
+// ReportActionHTML writes a self-contained html compliance report of the
+// enabled Notes to the given file or to stdout, if no file is given.
+// The report contains the host identity, the enabled solution and Notes,
+// the verify tables with footnotes, the overrides in effect and the staging
+// status.
+func ReportActionHTML(writer io.Writer, fileName, saptuneVers string, tuneApp *app.App) {
+	if fileName == "flag_value" {
+		// '--output' without a file name
+		PrintHelpAndExit(writer, 1)
+	}
+	data := collectReportData(saptuneVers, tuneApp)
+	out := writer
+	if fileName != "" {
+		file, err := os.OpenFile(fileName, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			system.ErrorExit("Problems creating report file '%s' - %v", fileName, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := renderHTMLReport(out, data); err != nil {
+		system.ErrorExit("Problems writing html report - %v", err)
+	}
+	if fileName != "" {
+		system.NoticeLog("html compliance report written to '%s'", fileName)
+	}
+}
+
+// collectReportData collects the report data. The verify results are based
+// on the comparisons of app.VerifyAll, the same data as used by
+// 'saptune note verify', but without terminal colour codes
+func collectReportData(saptuneVers string, tuneApp *app.App) reportData {
+	data := reportData{
+		Created:        time.Now().Format("2006-01-02 15:04:05"),
+		CSP:            system.GetCSP(),
+		OsName:         system.GetOsName(),
+		OsVers:         system.GetOsVers(),
+		Virtualization: system.GetVirtStatus(),
+		RPMVersion:     RPMVersion,
+		SaptuneVersion: saptuneVers,
+		Solutions:      tuneApp.TuneForSolutions,
+		EnabledNotes:   tuneApp.NoteApplyOrder,
+		AppliedNotes:   strings.Fields(tuneApp.AppliedNotes()),
+		SysCompliance:  true,
+	}
+	data.Hostname, _ = os.Hostname()
+	data.Vendor, _ = system.GetHWIdentity("vendor")
+	data.Model, _ = system.GetHWIdentity("model")
+	data.StagingEnabled = getStagingFromConf()
+	data.StagedNotes, data.StagedSolutions = listStageNotesAndSols()
+
+	if len(tuneApp.NoteApplyOrder) == 0 {
+		return data
+	}
+	unsatisfiedNotes, comparisons, err := tuneApp.VerifyAll(false)
+	if err != nil {
+		system.ErrorExit("Failed to inspect the current system: %v", err)
+	}
+	result := system.JPNotes{}
+	PrintNoteFields(io.Discard, "NONE", comparisons, true, &result)
+	data.Verified = true
+	data.SysCompliance = len(unsatisfiedNotes) == 0
+	data.Notes = reportNotes(result, unsatisfiedNotes)
+	return data
+}
+
+// reportNotes groups the verify result lines by Note in the order of the
+// verify table
+func reportNotes(result system.JPNotes, unsatisfiedNotes []string) []reportNote {
+	notes := []reportNote{}
+	noteIdx := make(map[string]int)
+	for _, line := range result.Verifications {
+		idx, ok := noteIdx[line.NoteID]
+		if !ok {
+			idx = len(notes)
+			noteIdx[line.NoteID] = idx
+			rnote := reportNote{NoteID: line.NoteID, NoteVers: line.NoteVers, Compliant: true}
+			for _, unsat := range unsatisfiedNotes {
+				if unsat == line.NoteID {
+					rnote.Compliant = false
+				}
+			}
+			if ovFiles := getOverrideFiles(line.NoteID); len(ovFiles) != 0 {
+				rnote.Override = strings.Join(ovFiles, ", ")
+			}
+			notes = append(notes, rnote)
+		}
+		rnote := &notes[idx]
+		rnote.Lines = append(rnote.Lines, line)
+		if line.OverValue != "" {
+			rnote.OverParams = append(rnote.OverParams, line)
+		}
+		for _, fn := range line.Footnotes {
+			addReportFootnote(rnote, strings.TrimSpace(fn.FNoteTxt))
+		}
+	}
+	for _, remind := range result.Attentions {
+		if idx, ok := noteIdx[remind.NoteID]; ok {
+			notes[idx].Attention = remind.NoteReminder
+		}
+	}
+	return notes
+}
+
+// addReportFootnote adds a footnote text once to the footnotes of a Note
+func addReportFootnote(rnote *reportNote, fnTxt string) {
+	if fnTxt == "" {
+		return
+	}
+	for _, fn := range rnote.Footnotes {
+		if fn == fnTxt {
+			return
+		}
+	}
+	rnote.Footnotes = append(rnote.Footnotes, fnTxt)
+}
+
+// renderHTMLReport writes the html report. Style sheet is embedded, so the
+// file can be viewed offline
+func renderHTMLReport(writer io.Writer, data reportData) error {
+	funcs := template.FuncMap{
+		"join": strings.Join,
+		"compliant": func(comp *bool) string {
+			if comp == nil {
+				return "-"
+			}
+			if *comp {
+				return "yes"
+			}
+			return "no"
+		},
+		"actual": func(act *string) string {
+			if act == nil {
+				return "NA"
+			}
+			return *act
+		},
+		"footnotes": func(fns []system.JFootNotes) string {
+			idx := []string{}
+			for _, fn := range fns {
+				if fn.FNoteNumber > 0 {
+					idx = append(idx, fmt.Sprintf("[%d]", fn.FNoteNumber))
+				}
+			}
+			return strings.Join(idx, " ")
+		},
+	}
+	tmpl, err := template.New("report").Funcs(funcs).Parse(htmlReportTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(writer, data)
+}
+
+// htmlReportTemplate is the template of the html compliance report
+var htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>saptune compliance report - {{.Hostname}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1, h2, h3 { color: #0c322c; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #bbb; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { background: #eee; }
+td.value { font-family: monospace; white-space: pre-wrap; word-break: break-all; }
+.yes { color: #1a7f37; font-weight: bold; }
+.no { color: #cf222e; font-weight: bold; }
+.attention { color: #cf222e; white-space: pre-wrap; }
+.footnotes { font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>saptune compliance report</h1>
+<p>created {{.Created}}</p>
+
+<h2>Host</h2>
+<table>
+<tr><th>hostname</th><td>{{.Hostname}}</td></tr>
+<tr><th>hardware vendor</th><td>{{.Vendor}}</td></tr>
+<tr><th>hardware model</th><td>{{.Model}}</td></tr>
+<tr><th>cloud service provider</th><td>{{.CSP}}</td></tr>
+<tr><th>virtualization</th><td>{{.Virtualization}}</td></tr>
+<tr><th>operating system</th><td>{{.OsName}} {{.OsVers}}</td></tr>
+<tr><th>saptune package</th><td>{{.RPMVersion}}</td></tr>
+<tr><th>configured version</th><td>{{.SaptuneVersion}}</td></tr>
+</table>
+
+<h2>Tuning</h2>
+<table>
+<tr><th>Solution enabled</th><td>{{join .Solutions " "}}</td></tr>
+<tr><th>Notes enabled</th><td>{{join .EnabledNotes " "}}</td></tr>
+<tr><th>Notes applied</th><td>{{join .AppliedNotes " "}}</td></tr>
+<tr><th>system compliance</th><td>{{if not .Verified}}nothing to verify{{else if .SysCompliance}}<span class="yes">compliant</span>{{else}}<span class="no">not compliant</span>{{end}}</td></tr>
+</table>
+{{range .Notes}}
+<h3>SAP Note {{.NoteID}}, Version {{.NoteVers}} - {{if .Compliant}}<span class="yes">compliant</span>{{else}}<span class="no">not compliant</span>{{end}}</h3>
+<table>
+<tr><th>Parameter</th><th>Expected</th><th>Override</th><th>Actual</th><th>Compliant</th><th>Footnotes</th></tr>
+{{range .Lines}}<tr><td>{{.Parameter}}</td><td class="value">{{.ExpValue}}</td><td class="value">{{.OverValue}}</td><td class="value">{{actual .ActValue}}</td><td class="{{compliant .Compliant}}">{{compliant .Compliant}}</td><td>{{footnotes .Footnotes}}</td></tr>
+{{end}}</table>
+{{if .Footnotes}}<ul class="footnotes">
+{{range .Footnotes}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}{{if .Attention}}<p class="attention">{{.Attention}}</p>
+{{end}}{{end}}
+<h2>Overrides in effect</h2>
+{{$found := false}}{{range .Notes}}{{if .Override}}{{$found = true}}
+<h3>SAP Note {{.NoteID}} - {{.Override}}</h3>
+{{if .OverParams}}<table>
+<tr><th>Parameter</th><th>Override</th></tr>
+{{range .OverParams}}<tr><td>{{.Parameter}}</td><td class="value">{{.OverValue}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}{{end}}{{if not $found}}<p>no override files in effect</p>
+{{end}}
+<h2>Staging</h2>
+<table>
+<tr><th>staging</th><td>{{if .StagingEnabled}}enabled{{else}}disabled{{end}}</td></tr>
+<tr><th>staged Notes</th><td>{{join .StagedNotes " "}}</td></tr>
+<tr><th>staged Solutions</th><td>{{join .StagedSolutions " "}}</td></tr>
+</table>
+</body>
+</html>
+`
@@ -0,0 +1,69 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/system"
+	"strings"
+	"testing"
+)
+
+func TestReportNotes(t *testing.T) {
+	yes := true
+	no := false
+	result := system.JPNotes{
+		Verifications: []system.JPNotesLine{
+			{NoteID: "1410736", NoteVers: "6", Parameter: "net.ipv4.tcp_keepalive_intvl", Compliant: &yes, ExpValue: "75"},
+			{NoteID: "1410736", NoteVers: "6", Parameter: "net.ipv4.tcp_keepalive_time", Compliant: &no, ExpValue: "300", OverValue: "300", Footnotes: []system.JFootNotes{{FNoteNumber: 3, FNoteTxt: " [3] value is only checked, but NOT set"}}},
+			{NoteID: "1410736", NoteVers: "6", Parameter: "rpm:glibc", Compliant: &yes, Footnotes: []system.JFootNotes{{FNoteNumber: 3, FNoteTxt: " [3] value is only checked, but NOT set"}}},
+			{NoteID: "941735", NoteVers: "11", Parameter: "energy_perf_bias"},
+		},
+		Attentions: []system.JPNotesRemind{{NoteID: "941735", NoteReminder: "check manually"}},
+	}
+	notes := reportNotes(result, []string{"1410736"})
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 Notes, got '%+v'", notes)
+	}
+	if notes[0].NoteID != "1410736" || notes[0].Compliant || len(notes[0].Lines) != 3 {
+		t.Errorf("wrong first Note '%+v'", notes[0])
+	}
+	if len(notes[0].Footnotes) != 1 || notes[0].Footnotes[0] != "[3] value is only checked, but NOT set" {
+		t.Errorf("wrong footnotes '%+v'", notes[0].Footnotes)
+	}
+	if len(notes[0].OverParams) != 1 || notes[0].OverParams[0].Parameter != "net.ipv4.tcp_keepalive_time" {
+		t.Errorf("wrong override parameter '%+v'", notes[0].OverParams)
+	}
+	if !notes[1].Compliant || notes[1].Attention != "check manually" {
+		t.Errorf("wrong second Note '%+v'", notes[1])
+	}
+
+	data := reportData{
+		Hostname:       "sapnode<1>",
+		Verified:       true,
+		StagingEnabled: true,
+		StagedNotes:    []string{"1410736"},
+		Notes:          notes,
+	}
+	buf := bytes.Buffer{}
+	if err := renderHTMLReport(&buf, data); err != nil {
+		t.Fatalf("unexpected error: '%v'", err)
+	}
+	report := buf.String()
+	for _, expected := range []string{
+		"<title>saptune compliance report - sapnode&lt;1&gt;</title>",
+		`<span class="no">not compliant</span>`,
+		"<h3>SAP Note 1410736, Version 6 - ",
+		`<td>net.ipv4.tcp_keepalive_time</td><td class="value">300</td><td class="value">300</td><td class="value">NA</td><td class="no">no</td><td>[3]</td>`,
+		`<td class="-">-</td>`,
+		"<li>[3] value is only checked, but NOT set</li>",
+		`<p class="attention">check manually</p>`,
+		"<p>no override files in effect</p>",
+		"<tr><th>staging</th><td>enabled</td></tr>",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("missing '%s' in report:\n%s", expected, report)
+		}
+	}
+	if strings.Contains(report, "\033[") {
+		t.Errorf("report contains terminal colour codes")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"github.com/SUSE/saptune/app"
 	"github.com/SUSE/saptune/system"
-	"github.com/SUSE/saptune/txtparser"
 	"io"
 	"os"
 	"strings"
@@ -166,6 +165,7 @@ func ServiceActionApply(tuneApp *app.App) {
 	if system.IsSapconfActive(SapconfService) {
 		system.ErrorExit("found an active sapconf, so refuse any action")
 	}
+	verifyApplyIntegrity(tuneApp.NoteApplyOrder, tuneApp.TuneForSolutions)
 	system.NoticeLog("saptune is now tuning the system...")
 	if err := tuneApp.TuneAll(); err != nil {
 		system.ErrorExit("%v", err)
@@ -231,7 +231,7 @@ func ServiceActionStatus(writer io.Writer, tuneApp *app.App, saptuneVersion stri
 	infoTrigger["notCompliant"] = chkTuningResult(writer, tuneApp, &jstatus)
 
 	infoMsg := bytes.Buffer{}
-	if system.GetFlagVal("format") == "json" {
+	if system.IsStructuredOut() {
 		writer = &infoMsg
 	}
 	printInfoBlock(writer, infoTrigger)
@@ -815,7 +815,7 @@ func DaemonAction(writer io.Writer, actionName, saptuneVersion string, tuneApp *
 // the saptune configuration file. Otherwise it returns false
 func ignoreServiceReload() bool {
 	ret := false
-	sconf, err := txtparser.ParseSysconfigFile(saptuneSysconfig, true)
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, true)
 	if err != nil {
 		system.ErrorExit("Unable to read file '%s': '%v'\n", saptuneSysconfig, err, 2)
 	}
@@ -857,6 +857,13 @@ func CheckOrphanedOverrides() {
 	workDir := NoteTuningSheets
 	for _, entry := range dirCont {
 		ovFile := entry.Name()
+		if entry.IsDir() {
+			if !strings.HasSuffix(ovFile, ".d") {
+				continue
+			}
+			// drop-in override directory of a Note
+			ovFile = strings.TrimSuffix(ovFile, ".d")
+		}
 		if strings.HasSuffix(ovFile, ".sol") {
 			object = "solution"
 			workDir = SolutionSheets
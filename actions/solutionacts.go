@@ -7,6 +7,7 @@ import (
 	"github.com/SUSE/saptune/app"
 	"github.com/SUSE/saptune/sap/solution"
 	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"io"
 	"os"
 	"regexp"
@@ -32,7 +33,7 @@ func SolutionAction(writer io.Writer, actionName, solName, newSolName string, tu
 		system.WarningLog("the action 'solution simulate' is deprecated!.\nsaptune will still handle this action in the current version, but it will be removed in future versions of saptune.")
 		SolutionActionSimulate(writer, solName, tuneApp)
 	case "customise", "customize":
-		SolutionActionCustomise(writer, solName, tuneApp)
+		SolutionActionCustomise(os.Stdin, writer, solName, tuneApp)
 	case "edit":
 		SolutionActionEdit(writer, solName, tuneApp)
 	case "create":
@@ -51,6 +52,12 @@ func SolutionAction(writer io.Writer, actionName, solName, newSolName string, tu
 		SolutionActionApplied(writer, tuneApp)
 	case "enabled":
 		SolutionActionEnabled(writer, tuneApp)
+	case "lint":
+		SolutionActionLint(writer, solName)
+	case "recommend":
+		SolutionActionRecommend(os.Stdin, writer, tuneApp)
+	case "upgrade-check":
+		SolutionActionUpgradeCheck(writer, tuneApp)
 	default:
 		PrintHelpAndExit(writer, 1)
 	}
@@ -140,7 +147,7 @@ func SolutionActionList(writer io.Writer, tuneApp *app.App) {
 		jsolutionList = append(jsolutionList, jsolutionListEntry)
 	}
 	remember := bytes.Buffer{}
-	if system.GetFlagVal("format") == "json" {
+	if system.IsStructuredOut() {
 		writer = &remember
 	}
 	rememberMessage(writer)
@@ -321,8 +328,10 @@ func SolutionActionApplied(writer io.Writer, tuneApp *app.App) {
 }
 
 // SolutionActionCustomise creates an override file and allows to editing the
-// solution definition override file
-func SolutionActionCustomise(writer io.Writer, customSol string, tuneApp *app.App) {
+// solution definition override file.
+// The edited override file is checked against the solution definition file
+// before it is saved
+func SolutionActionCustomise(reader io.Reader, writer io.Writer, customSol string, tuneApp *app.App) {
 	if customSol == "" {
 		PrintHelpAndExit(writer, 1)
 	}
@@ -349,7 +358,10 @@ func SolutionActionCustomise(writer io.Writer, customSol string, tuneApp *app.Ap
 		editDestFile = ovFileName
 	}
 
-	changed, err := system.EditAndCheckFile(editSrcFile, editDestFile, customSol, "solution")
+	validate := lintValidator(reader, writer, editDestFile, func(tmpFile string) ([]txtparser.LintProblem, error) {
+		return solution.LintSolutionOverrideFile(tmpFile, fileName, NoteTuningSheets, ExtraTuningSheets)
+	})
+	changed, err := system.EditValidateAndCheckFile(editSrcFile, editDestFile, customSol, "solution", validate)
 	if err != nil {
 		system.ErrorExit("Problems while editing solution definition file '%s' - %v", editSrcFile, err)
 	}
@@ -401,6 +413,9 @@ func SolutionActionEdit(writer io.Writer, customSol string, tuneApp *app.App) {
 		}
 		if overrideSol {
 			system.NoticeLog("Solution override file '%s' exists. Please check, if the content of this file is still valid", ovFileName)
+			chkOverrideFile(ovFileName, func() ([]txtparser.LintProblem, error) {
+				return solution.LintSolutionOverrideFile(ovFileName, fileName, NoteTuningSheets, ExtraTuningSheets)
+			})
 		}
 	} else {
 		system.NoticeLog("Nothing changed during the editor session, so no update of the solution definition file '%s'", fileName)
@@ -462,6 +477,42 @@ func SolutionActionShow(writer io.Writer, solName string) {
 	fmt.Fprintf(writer, "\nContent of Solution %s:\n%s\n", solName, string(cont))
 }
 
+// SolutionActionLint checks Solution definition files in strict mode and
+// reports all problems with file name and line number.
+// The Solution can be given by its name or by the name of the file.
+// Without a Solution all Solution definition files of the working area,
+// the custom Solutions and the override Solutions are checked
+func SolutionActionLint(writer io.Writer, solName string) {
+	files := []string{}
+	switch {
+	case solName == "":
+		for _, dir := range []string{SolutionSheets, ExtraTuningSheets, OverrideTuningSheets} {
+			_, solFiles := system.ListDir(dir, "saptune solution definitions")
+			for _, fName := range solFiles {
+				if strings.HasSuffix(fName, ".sol") {
+					files = append(files, fmt.Sprintf("%s%s", dir, fName))
+				}
+			}
+		}
+	case strings.Contains(solName, "/"):
+		files = append(files, solName)
+	default:
+		fileName, _ := getFileName(fmt.Sprintf("%s.sol", solName), SolutionSheets, ExtraTuningSheets)
+		files = append(files, fileName)
+	}
+	cnt := 0
+	for _, fileName := range files {
+		problems, err := solution.LintSolutionFile(fileName, NoteTuningSheets, ExtraTuningSheets)
+		if err != nil {
+			system.ErrorExit("Failed to read file '%s' - %v", fileName, err)
+		}
+		cnt = cnt + printLintProblems(writer, fileName, problems)
+	}
+	if cnt != 0 {
+		system.ErrorExit("", 1)
+	}
+}
+
 // SolutionActionDelete deletes a custom solution definition file and
 // the corresponding override file
 func SolutionActionDelete(reader io.Reader, writer io.Writer, solName string, tuneApp *app.App) {
@@ -647,6 +698,7 @@ func getNoteInSol(tApp *app.App, noteName string) (string, string) {
 
 // applySolution will apply the given solution
 func applySolution(writer io.Writer, solName string, tuneApp *app.App) {
+	verifyApplyIntegrity(solution.AllSolutions[solutionSelector][solName], []string{solName})
 	removedAdditionalNotes, err := tuneApp.TuneSolution(solName)
 	if err != nil {
 		system.ErrorExit("Failed to tune for solution %s: %v", solName, err)
@@ -0,0 +1,140 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/system"
+	"io"
+	"strings"
+)
+
+// s4Alternatives are the S/4HANA solutions with the same tuning as the
+// recommended NetWeaver or HANA solution
+var s4Alternatives = map[string]string{
+	"NETWEAVER+HANA": "S4HANA-APP+DB",
+	"NETWEAVER":      "S4HANA-APPSERVER",
+	"HANA":           "S4HANA-DBSERVER",
+}
+
+// SolutionActionRecommend inspects the host for installed SAP components and
+// recommends the matching solution of the current architecture.
+// With '--apply' the recommended solution is applied
+func SolutionActionRecommend(reader io.Reader, writer io.Writer, tuneApp *app.App) {
+	comps := system.DetectSAPComponents()
+	result := system.JSolRecommend{Components: comps}
+	if len(comps) == 0 {
+		fmt.Fprintf(writer, "No SAP components detected on this host.\n")
+	} else {
+		fmt.Fprintf(writer, "Detected SAP components:\n")
+		format := "    %-4s %-10s %-14s %s\n"
+		fmt.Fprintf(writer, format, "SID", "Instance", "Type", "Detected by")
+		for _, comp := range comps {
+			fmt.Fprintf(writer, format, dashIfEmpty(comp.SID), dashIfEmpty(comp.Instance), comp.Type, comp.Source)
+		}
+	}
+	available := make(map[string]bool)
+	for solName := range tuneApp.AllSolutions {
+		available[solName] = true
+	}
+	result.Solution, result.Explanation = recommendSolution(comps, available)
+	fmt.Fprintf(writer, "\n")
+	for _, expl := range result.Explanation {
+		fmt.Fprintf(writer, "%s\n", expl)
+	}
+	if result.Solution == "" {
+		system.Jcollect(result)
+		if system.IsFlagSet("apply") {
+			system.ErrorExit("No solution recommended, so nothing to apply.", 1)
+		}
+		return
+	}
+	fmt.Fprintf(writer, "\nRecommended solution: %s\n", result.Solution)
+	if len(tuneApp.TuneForSolutions) > 0 && tuneApp.TuneForSolutions[0] != result.Solution {
+		system.WarningLog("The enabled solution '%s' does not match the recommended solution '%s'.", tuneApp.TuneForSolutions[0], result.Solution)
+	}
+	if !system.IsFlagSet("apply") {
+		system.Jcollect(result)
+		fmt.Fprintf(writer, "To apply the recommended solution run 'saptune solution recommend --apply' or 'saptune solution apply %s'.\n", result.Solution)
+		return
+	}
+	// collect the result before the change, as a failing change exits
+	system.Jcollect(result)
+	changeSolution(reader, writer, result.Solution, tuneApp)
+	result.Applied = true
+	system.Jcollect(result)
+}
+
+// recommendSolution returns the solution matching the detected SAP
+// components together with the explanation of the recommendation.
+// The recommendation is restricted to the available solutions of the
+// current architecture
+func recommendSolution(comps []system.JSAPComponent, available map[string]bool) (string, []string) {
+	expl := []string{}
+	sids := make(map[string][]string)
+	types := []string{}
+	seen := make(map[string]bool)
+	for _, comp := range comps {
+		if _, ok := sids[comp.Type]; !ok {
+			types = append(types, comp.Type)
+			sids[comp.Type] = []string{}
+		}
+		if comp.SID != "" && !seen[comp.Type+" "+comp.SID] {
+			seen[comp.Type+" "+comp.SID] = true
+			sids[comp.Type] = append(sids[comp.Type], comp.SID)
+		}
+	}
+	for _, compType := range types {
+		if len(sids[compType]) == 0 {
+			expl = append(expl, fmt.Sprintf("%s detected.", compType))
+		} else {
+			expl = append(expl, fmt.Sprintf("%s detected (SID %s).", compType, strings.Join(sids[compType], ", ")))
+		}
+	}
+	has := func(compType string) bool {
+		_, ok := sids[compType]
+		return ok
+	}
+	appServer := has(system.SAPCompABAP) || has(system.SAPCompJava) || has(system.SAPCompWebDispatcher)
+
+	sol := ""
+	switch {
+	case has(system.SAPCompHANA) && appServer:
+		sol = "NETWEAVER+HANA"
+		expl = append(expl, "SAP HANA database and SAP NetWeaver application server instances are running on the same host.")
+	case has(system.SAPCompHANA):
+		sol = "HANA"
+		expl = append(expl, "The host runs a SAP HANA database.")
+	case has(system.SAPCompASE):
+		sol = "SAP-ASE"
+		expl = append(expl, "The host runs a SAP ASE database.")
+		if appServer {
+			expl = append(expl, "There is no combined solution for SAP ASE and SAP NetWeaver, the solution covers the database.")
+		}
+	case has(system.SAPCompMaxDB) && appServer:
+		sol = "NETWEAVER+MAXDB"
+		expl = append(expl, "MaxDB database and SAP NetWeaver application server instances are running on the same host.")
+	case has(system.SAPCompMaxDB):
+		sol = "MAXDB"
+		expl = append(expl, "The host runs a MaxDB database.")
+	case has(system.SAPCompBOBJ):
+		sol = "BOBJ"
+		expl = append(expl, "The host runs SAP BusinessObjects.")
+	case appServer:
+		sol = "NETWEAVER"
+		expl = append(expl, "The host runs SAP NetWeaver application server instances.")
+	default:
+		expl = append(expl, "No SAP workload detected, so no solution can be recommended. Please choose a solution from 'saptune solution list'.")
+		return "", expl
+	}
+	if has(system.SAPCompBOBJ) && sol != "BOBJ" {
+		expl = append(expl, "SAP BusinessObjects is installed additionally, please check, if the solution 'BOBJ' fits better.")
+	}
+	if !available[sol] {
+		expl = append(expl, fmt.Sprintf("The solution '%s' is not available for the current architecture, so no solution can be recommended.", sol))
+		return "", expl
+	}
+	if alt := s4Alternatives[sol]; available[alt] {
+		expl = append(expl, fmt.Sprintf("For SAP S/4HANA the solution '%s' can be used instead.", alt))
+	}
+	return sol, expl
+}
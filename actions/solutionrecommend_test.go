@@ -0,0 +1,39 @@
+package actions
+
+import (
+	"github.com/SUSE/saptune/system"
+	"strings"
+	"testing"
+)
+
+func TestRecommendSolution(t *testing.T) {
+	available := map[string]bool{"HANA": true, "NETWEAVER": true, "NETWEAVER+HANA": true, "S4HANA-APP+DB": true, "MAXDB": true, "NETWEAVER+MAXDB": true, "BOBJ": true}
+	hana := system.JSAPComponent{SID: "HA0", Instance: "HDB00", Type: system.SAPCompHANA}
+	ascs := system.JSAPComponent{SID: "NW1", Instance: "ASCS01", Type: system.SAPCompABAP}
+	dia := system.JSAPComponent{SID: "NW1", Instance: "D02", Type: system.SAPCompABAP}
+	maxdb := system.JSAPComponent{SID: "MX1", Type: system.SAPCompMaxDB}
+	bobj := system.JSAPComponent{SID: "BO1", Type: system.SAPCompBOBJ}
+	ase := system.JSAPComponent{SID: "AS1", Type: system.SAPCompASE}
+
+	tests := []struct {
+		comps []system.JSAPComponent
+		sol   string
+		expl  string
+	}{
+		{[]system.JSAPComponent{}, "", "No SAP workload detected"},
+		{[]system.JSAPComponent{hana}, "HANA", "HANA detected (SID HA0)."},
+		{[]system.JSAPComponent{hana, ascs, dia}, "NETWEAVER+HANA", "'S4HANA-APP+DB' can be used instead"},
+		{[]system.JSAPComponent{ascs, dia}, "NETWEAVER", "ABAP detected (SID NW1)."},
+		{[]system.JSAPComponent{maxdb}, "MAXDB", "MaxDB database"},
+		{[]system.JSAPComponent{maxdb, dia}, "NETWEAVER+MAXDB", "MAXDB detected (SID MX1)."},
+		{[]system.JSAPComponent{bobj}, "BOBJ", "BusinessObjects"},
+		{[]system.JSAPComponent{hana, bobj}, "HANA", "please check, if the solution 'BOBJ' fits better"},
+		{[]system.JSAPComponent{ase}, "", "'SAP-ASE' is not available"},
+	}
+	for _, tst := range tests {
+		sol, expl := recommendSolution(tst.comps, available)
+		if sol != tst.sol || !strings.Contains(strings.Join(expl, "\n"), tst.expl) {
+			t.Errorf("components '%+v': got '%s' - '%v', expected '%s' - '%s'", tst.comps, sol, expl, tst.sol, tst.expl)
+		}
+	}
+}
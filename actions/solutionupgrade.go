@@ -0,0 +1,171 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"io"
+	"sort"
+)
+
+// SolutionActionUpgradeCheck compares the Note list of the enabled solution,
+// which was stored during the last apply, with the current solution
+// definition and shows the Notes to be added and removed together with the
+// impact on the parameter values.
+// With '--apply' the transition to the current solution definition is done
+func SolutionActionUpgradeCheck(writer io.Writer, tuneApp *app.App) {
+	result := system.JSolUpgrade{
+		Added:   []system.JSolUpgradeNote{},
+		Removed: []system.JSolUpgradeNote{},
+	}
+	if len(tuneApp.TuneForSolutions) == 0 {
+		fmt.Fprintf(writer, "No solution enabled, nothing to check.\n")
+		system.Jcollect(result)
+		return
+	}
+	solName := tuneApp.TuneForSolutions[0]
+	result.SolName = solName
+	added, removed, err := tuneApp.SolutionNoteChanges(solName)
+	if err != nil {
+		system.ErrorExit("Failed to compare the Note list of solution '%s': %v", solName, err)
+	}
+	for _, noteID := range added {
+		result.Added = append(result.Added, upgradeNoteInfo(noteID, solName, true, tuneApp))
+	}
+	for _, noteID := range removed {
+		result.Removed = append(result.Removed, upgradeNoteInfo(noteID, solName, false, tuneApp))
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Fprintf(writer, "The applied Notes of solution '%s' match the current solution definition, nothing to do.\n", solName)
+		system.Jcollect(result)
+		return
+	}
+	fmt.Fprintf(writer, "The definition of solution '%s' has changed since it was applied.\n", solName)
+	printUpgradeNotes(writer, "Notes to add:", result.Added, "")
+	printUpgradeNotes(writer, "Notes to remove:", result.Removed, "(kept, enabled additionally or by another solution)")
+	if !system.IsFlagSet("apply") {
+		system.Jcollect(result)
+		fmt.Fprintf(writer, "\nTo perform the transition run 'saptune solution upgrade-check --apply'.\n")
+		return
+	}
+	result.Applied = true
+	system.Jcollect(result)
+	reverted, applied, err := tuneApp.UpgradeSolution(solName)
+	if err != nil {
+		system.ErrorExit("Failed to upgrade solution '%s': %v", solName, err)
+	}
+	system.NoticeLog("Solution '%s' upgraded - reverted Notes: '%v', applied Notes: '%v'", solName, reverted, applied)
+	fmt.Fprintf(writer, "\nThe applied Notes of solution '%s' now match the current solution definition.\n", solName)
+	rememberMessage(writer)
+}
+
+// upgradeNoteInfo collects the description and the parameter impact of a
+// Note added to or removed from the solution definition
+func upgradeNoteInfo(noteID, solName string, added bool, tuneApp *app.App) system.JSolUpgradeNote {
+	info := system.JSolUpgradeNote{NoteID: noteID, Parameters: []system.JSolUpgradeParam{}}
+	if aNote, ok := tuneApp.AllNotes[noteID]; ok {
+		info.NoteName = aNote.Name()
+	}
+	if added {
+		info.Parameters = addedNoteParams(noteID, tuneApp)
+	} else if tuneApp.KeepNoteOfSolution(noteID, solName) {
+		info.Kept = true
+	} else {
+		info.Parameters = removedNoteParams(noteID, tuneApp)
+	}
+	return info
+}
+
+// addedNoteParams returns the parameters, which will be changed by applying
+// a Note added to the solution definition
+func addedNoteParams(noteID string, tuneApp *app.App) []system.JSolUpgradeParam {
+	params := []system.JSolUpgradeParam{}
+	_, comparisons, _, err := tuneApp.VerifyNote(noteID)
+	if err != nil {
+		system.WarningLog("Failed to check the parameters of Note '%s': %v", noteID, err)
+		return params
+	}
+	for _, comparison := range comparisons {
+		if !upgradeRelevantField(comparison) || comparison.MatchExpectation {
+			continue
+		}
+		params = append(params, system.JSolUpgradeParam{
+			Parameter: comparison.ReflectMapKey,
+			ActValue:  fmt.Sprintf("%v", comparison.ActualValue),
+			NewValue:  fmt.Sprintf("%v", comparison.ExpectedValue),
+		})
+	}
+	sortUpgradeParams(params)
+	return params
+}
+
+// removedNoteParams returns the parameters, which will be reverted to the
+// saved values by reverting a Note removed from the solution definition
+func removedNoteParams(noteID string, tuneApp *app.App) []system.JSolUpgradeParam {
+	params := []system.JSolUpgradeParam{}
+	if _, ok := tuneApp.IsNoteApplied(noteID); !ok {
+		// nothing to revert
+		return params
+	}
+	savedState := note.INISettings{}
+	if err := tuneApp.State.Retrieve(noteID, &savedState); err != nil {
+		system.WarningLog("Failed to read the saved state of Note '%s': %v", noteID, err)
+		return params
+	}
+	actValues := make(map[string]string)
+	if _, comparisons, _, err := tuneApp.VerifyNote(noteID); err == nil {
+		for _, comparison := range comparisons {
+			if upgradeRelevantField(comparison) {
+				actValues[comparison.ReflectMapKey] = fmt.Sprintf("%v", comparison.ActualValue)
+			}
+		}
+	}
+	for param, value := range savedState.SysctlParams {
+		if param == "reminder" {
+			continue
+		}
+		params = append(params, system.JSolUpgradeParam{
+			Parameter: param,
+			ActValue:  actValues[param],
+			NewValue:  value,
+		})
+	}
+	sortUpgradeParams(params)
+	return params
+}
+
+// upgradeRelevantField returns true, if the field comparison belongs to a
+// parameter of the Note
+func upgradeRelevantField(comparison note.FieldComparison) bool {
+	if comparison.ReflectMapKey == "" || comparison.ReflectMapKey == "reminder" {
+		return false
+	}
+	return comparison.ReflectFieldName != "Inform" && comparison.ReflectFieldName != "OverrideParams"
+}
+
+// sortUpgradeParams sorts the parameters by name
+func sortUpgradeParams(params []system.JSolUpgradeParam) {
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Parameter < params[j].Parameter
+	})
+}
+
+// printUpgradeNotes prints the Notes to add or to remove together with the
+// parameter changes
+func printUpgradeNotes(writer io.Writer, header string, notes []system.JSolUpgradeNote, keepText string) {
+	if len(notes) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "\n%s\n", header)
+	for _, upNote := range notes {
+		if upNote.Kept {
+			fmt.Fprintf(writer, "    %-10s %s %s\n", upNote.NoteID, upNote.NoteName, keepText)
+			continue
+		}
+		fmt.Fprintf(writer, "    %-10s %s\n", upNote.NoteID, upNote.NoteName)
+		for _, param := range upNote.Parameters {
+			fmt.Fprintf(writer, "        %s: '%s' -> '%s'\n", param.Parameter, param.ActValue, param.NewValue)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package actions
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
 	"github.com/SUSE/saptune/app"
 	"github.com/SUSE/saptune/sap/note"
@@ -9,9 +11,11 @@ import (
 	"github.com/SUSE/saptune/txtparser"
 	"io"
 	"os"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type stageFiles struct {
@@ -25,6 +29,18 @@ type stageComparison struct {
 	MatchExpectation bool
 }
 
+// solutionState is the enabled state of a solution changed by releasing
+// the deletion of the solution. It is saved together with the backup of the
+// solution (<backup>.state), so 'staging rollback' can restore it
+type solutionState struct {
+	Solution string   // solution removed from TUNE_FOR_SOLUTIONS
+	Notes    []string // Notes of the solution added to TUNE_FOR_NOTES
+}
+
+// solStateBackupSuffix is the suffix of the file, which contains the saved
+// solution state
+const solStateBackupSuffix = ".state"
+
 type writerDescriptor struct {
 	writer         io.Writer
 	textApplied    string
@@ -82,6 +98,13 @@ func StagingAction(actionName string, stageName []string, tuneApp *app.App) {
 		}
 		chkStageExit(os.Stdout)
 		stagingActionRelease(os.Stdin, os.Stdout, stageName, tuneApp)
+	case "rollback":
+		if len(stageName) == 0 {
+			stageName = []string{"all"}
+		}
+		stagingActionRollback(os.Stdin, os.Stdout, stageName, tuneApp)
+	case "history":
+		stagingActionHistory(os.Stdout, stageName)
 	default:
 		PrintHelpAndExit(os.Stdout, 1)
 	}
@@ -104,6 +127,7 @@ func stagingActionEnable() {
 	if err := writeStagingToConf("true"); err != nil {
 		system.ErrorExit("Staging could NOT be enabled. - '%v'\n", err)
 	}
+	recordStagingHistory("enable", "", "", "")
 	system.NoticeLog("Staging has been enabled.")
 }
 
@@ -114,6 +138,7 @@ func stagingActionDisable() {
 	if err := writeStagingToConf("false"); err != nil {
 		system.ErrorExit("Staging could NOT be disabled. - '%v'\n", err)
 	}
+	recordStagingHistory("disable", "", "", "")
 	system.NoticeLog("Staging has been disabled.")
 }
 
@@ -206,8 +231,15 @@ func stagingActionAnalysis(writer io.Writer, stageObject []string) {
 // First the command will show an analysis of the objects going to be released
 // to make the user aware of further needed actions or potential problems
 // (for details see saptune staging analysis).
-// The customer has to confirm this, because the action is irreversible.
+// The customer has to confirm this, because the action is irreversible. The
+// replaced working area files are saved, so the previous versions can be
+// restored by 'staging rollback'.
+// Before an updated Note is released, the changes of its override files,
+// which are needed for the new Note version, are offered. They are applied
+// after the Note was released successfully
 func stagingActionRelease(reader io.Reader, writer io.Writer, sObject []string, tApp *app.App) {
+	// share the buffered reader between all questions
+	reader = bufio.NewReader(reader)
 	for _, sName := range sObject {
 		stagingFile := stgFiles.StageAttributes[sName]["sfilename"]
 		stageVers := stgFiles.StageAttributes[sName]["version"]
@@ -224,15 +256,17 @@ func stagingActionRelease(reader io.Reader, writer io.Writer, sObject []string,
 					system.ErrorExit("Releasing '%s' will break the functionality of saptune. Please fix", stageName, 2)
 				}
 			}
+			verifyStagingIntegrity(stgFiles.AllStageFiles)
 			if system.IsFlagSet("dryrun") {
 				system.ErrorExit("Flag 'dryrun' set, so staging action 'release' finished now without releasing anything", 0)
 			}
 			if !system.IsFlagSet("force") {
-				txtConfirm := "Releasing is irreversible! Are you sure"
+				txtConfirm := "Releasing is irreversible! The previous versions can only be restored by 'saptune staging rollback'. Are you sure"
 				if !readYesNo(txtConfirm, reader, writer) {
 					system.ErrorExit("Staging action 'release' aborted by user interaction", 0)
 				}
 			}
+			archiveStagingManifest()
 			errs := make([]error, 0)
 			for _, stageName := range stgFiles.AllStageFiles {
 				stagingFile = stgFiles.StageAttributes[stageName]["sfilename"]
@@ -243,10 +277,12 @@ func stagingActionRelease(reader io.Reader, writer io.Writer, sObject []string,
 				}
 				stageVers = stgFiles.StageAttributes[stageName]["version"]
 				stageDate = stgFiles.StageAttributes[stageName]["date"]
+				ovChanges := reconcileOverrides(reader, writer, stageName)
 				err := mvStageToWork(stageName, tApp)
 				if err != nil {
 					errs = append(errs, err)
 				} else {
+					applyOverrideReconcile(stageName, ovChanges)
 					system.NoticeLog("%s Version %s (%s) released", stageName, stageVers, stageDate)
 				}
 			}
@@ -261,23 +297,245 @@ func stagingActionRelease(reader io.Reader, writer io.Writer, sObject []string,
 			if !rel {
 				system.ErrorExit("Releasing '%s' will break the functionality of saptune. Please fix", sName, 2)
 			}
+			verifyStagingIntegrity([]string{sName})
 			if system.IsFlagSet("dryrun") {
 				system.ErrorExit("Flag 'dryrun' set, so staging action 'release' finished now without releasing anything", 0)
 			}
 			if !system.IsFlagSet("force") {
-				txtConfirm := "Releasing is irreversible! Are you sure"
+				txtConfirm := "Releasing is irreversible! The previous versions can only be restored by 'saptune staging rollback'. Are you sure"
 				if !readYesNo(txtConfirm, reader, writer) {
 					system.ErrorExit("Staging action 'release' aborted by user interaction", 0)
 				}
 			}
+			archiveStagingManifest()
+			ovChanges := reconcileOverrides(reader, writer, sName)
 			if err := mvStageToWork(sName, tApp); err != nil {
 				system.ErrorExit("", 1)
 			}
+			applyOverrideReconcile(sName, ovChanges)
 			system.NoticeLog("%s Version %s (%s) released", sName, stageVers, stageDate)
 		}
 	}
 }
 
+// stagingActionRollback restores the previous version of the requested
+// Notes, the solution definition or of all objects released from the
+// staging area.
+// The previous version is taken from the backups written by 'staging
+// release'. The released version is moved back to the staging area, so it
+// can be released again later. A Note or solution, which was new in the
+// release, is removed from the working area.
+// The customer has to confirm the rollback
+func stagingActionRollback(reader io.Reader, writer io.Writer, sObject []string, tApp *app.App) {
+	rbObjects := []string{}
+	for _, sName := range sObject {
+		if sName == "all" {
+			rbObjects = append(rbObjects, listRollbackObjects()...)
+			continue
+		}
+		if _, _, err := getLatestBackup(sName); err != nil {
+			system.ErrorExit("No backup of '%s' available in '%s', so nothing to roll back.", sName, StagingBackup, 1)
+		}
+		rbObjects = append(rbObjects, sName)
+	}
+	if len(rbObjects) == 0 {
+		system.ErrorExit("No released Notes or solutions available for rollback, so nothing to do.", 0)
+	}
+	for _, rbName := range rbObjects {
+		backup, isNew, _ := getLatestBackup(rbName)
+		if isNew {
+			fmt.Fprintf(writer, "Rollback of %s: remove the released version %s from the working area\n", rbName, getWorkVersion(rbName))
+		} else {
+			fmt.Fprintf(writer, "Rollback of %s: restore version %s (released version %s)\n", rbName, txtparser.GetINIFileVersionSectionEntry(backup, "version"), getWorkVersion(rbName))
+		}
+	}
+	if system.IsFlagSet("dryrun") {
+		system.ErrorExit("Flag 'dryrun' set, so staging action 'rollback' finished now without changing anything", 0)
+	}
+	if !system.IsFlagSet("force") {
+		if !readYesNo("Do you want to roll back the listed objects", reader, writer) {
+			system.ErrorExit("Staging action 'rollback' aborted by user interaction", 0)
+		}
+	}
+	errs := 0
+	for _, rbName := range rbObjects {
+		if err := rollbackStageObj(rbName, tApp); err != nil {
+			system.ErrorLog("%v", err)
+			errs++
+		}
+	}
+	if errs != 0 {
+		system.ErrorExit("", 1)
+	}
+}
+
+// rollbackStageObj restores the latest backup of a Note or solution in the
+// working area and records the rollback
+func rollbackStageObj(rbName string, tApp *app.App) error {
+	backup, isNew, err := getLatestBackup(rbName)
+	if err != nil {
+		return err
+	}
+	workingFile := getWorkFileName(rbName)
+	stagingFile := fmt.Sprintf("%s%s", StagingSheets, rbName)
+	relVers := getWorkVersion(rbName)
+	if isNew {
+		// object was new in the release, so it is removed
+		handleAppliedSolution(rbName, getRollbackAttributes(rbName, tApp), tApp)
+	}
+	if _, err := os.Stat(workingFile); err == nil {
+		// move the released version back to the staging area, if
+		// the staging area does not contain a newer one
+		if _, err := os.Stat(stagingFile); os.IsNotExist(err) {
+			if err := os.MkdirAll(StagingSheets, 0755); err != nil {
+				return fmt.Errorf("Problems creating staging area '%s': %v", StagingSheets, err)
+			}
+			err = os.Rename(workingFile, stagingFile)
+		} else {
+			err = os.Remove(workingFile)
+		}
+		if err != nil {
+			return fmt.Errorf("Problems during rollback of '%s' from working area: %v", rbName, err)
+		}
+	}
+	rbVers := ""
+	if !isNew {
+		rbVers = txtparser.GetINIFileVersionSectionEntry(backup, "version")
+		if err := system.CopyFile(backup, workingFile); err != nil {
+			return fmt.Errorf("Problems during restore of '%s' to working area: %v", rbName, err)
+		}
+		if err := restoreSolutionState(backup, tApp); err != nil {
+			return fmt.Errorf("Problems during restore of the enabled state of solution '%s': %v", rbName, err)
+		}
+	}
+	if err := restoreOverrideFiles(backup); err != nil {
+		return fmt.Errorf("Problems during restore of the override files of '%s': %v", rbName, err)
+	}
+	if err := os.Remove(backup); err != nil {
+		system.WarningLog("Problems during removal of backup file '%s': %v", backup, err)
+	}
+	if isNew {
+		system.NoticeLog("'%s' Version %s successfully removed from working area", rbName, relVers)
+	} else {
+		system.NoticeLog("'%s' successfully rolled back from Version %s to Version %s", rbName, relVers, rbVers)
+	}
+	recordStagingHistory("rollback", rbName, relVers, rbVers)
+	if strings.HasSuffix(rbName, ".sol") {
+		if _, ok := tApp.IsSolutionApplied(strings.TrimSuffix(rbName, ".sol")); ok && !isNew {
+			system.NoticeLog("Solution '%s' is applied. To get the restored version to take effect, please 'revert' the solution and apply again.", strings.TrimSuffix(rbName, ".sol"))
+		}
+	} else if _, ok := tApp.IsNoteApplied(rbName); ok {
+		if isNew {
+			system.NoticeLog("Note '%s' is applied, but its definition is removed by the rollback. Please 'revert' the Note.", rbName)
+		} else {
+			system.NoticeLog("Note '%s' is applied. To get the restored version to take effect, please 'revert' the Note and apply again.", rbName)
+		}
+	}
+	return nil
+}
+
+// backupWorkFile saves the working area file of a Note or solution before
+// it is replaced or removed by 'staging release', so that the release can
+// be rolled back by 'staging rollback'. The backups are versioned by the
+// time of the release (<StagingBackup>/<name>/<timestamp>).
+// For a new Note or solution an empty marker file <timestamp>.new is written
+func backupWorkFile(stageName, workingFile string) error {
+	backupDir := path.Join(StagingBackup, stageName)
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return err
+	}
+	stamp := backupStamp(backupDir, time.Now())
+	if _, err := os.Stat(workingFile); os.IsNotExist(err) {
+		return os.WriteFile(path.Join(backupDir, stamp+".new"), []byte{}, 0644)
+	}
+	return system.CopyFile(workingFile, path.Join(backupDir, stamp))
+}
+
+// backupStamp returns the name of a new backup in backupDir. The name is
+// the time with nanoseconds in a fixed width format, so the names still
+// sort in the order the backups were created. If a backup with this name
+// already exists, the time is increased until the name is unique
+func backupStamp(backupDir string, now time.Time) string {
+	for {
+		stamp := now.Format("20060102150405") + fmt.Sprintf("%09d", now.Nanosecond())
+		_, err := os.Stat(path.Join(backupDir, stamp))
+		_, errNew := os.Stat(path.Join(backupDir, stamp+".new"))
+		if os.IsNotExist(err) && os.IsNotExist(errNew) {
+			return stamp
+		}
+		now = now.Add(time.Nanosecond)
+	}
+}
+
+// getLatestBackup returns the latest backup file of a Note or solution and
+// if the object was new in the release (no previous version available)
+func getLatestBackup(rbName string) (string, bool, error) {
+	backupDir := path.Join(StagingBackup, rbName)
+	dirCont, err := os.ReadDir(backupDir)
+	if err != nil || len(dirCont) == 0 || rbName == "" || strings.Contains(rbName, "/") {
+		return "", false, fmt.Errorf("no backup of '%s' available in '%s'", rbName, StagingBackup)
+	}
+	// the backup names are timestamps, so the last one is the latest.
+	// The saved override files and solution state belong to the backup
+	// with the same name
+	latest := ""
+	for _, entry := range dirCont {
+		if !strings.HasSuffix(entry.Name(), overrideBackupSuffix) && !strings.HasSuffix(entry.Name(), solStateBackupSuffix) {
+			latest = entry.Name()
+		}
+	}
+	if latest == "" {
+		return "", false, fmt.Errorf("no backup of '%s' available in '%s'", rbName, StagingBackup)
+	}
+	return path.Join(backupDir, latest), strings.HasSuffix(latest, ".new"), nil
+}
+
+// listRollbackObjects returns all Notes and solutions with backups
+func listRollbackObjects() []string {
+	objects := []string{}
+	dirCont, err := os.ReadDir(StagingBackup)
+	if err != nil {
+		return objects
+	}
+	for _, entry := range dirCont {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, _, err := getLatestBackup(entry.Name()); err == nil {
+			objects = append(objects, entry.Name())
+		}
+	}
+	return objects
+}
+
+// getWorkFileName returns the working area file name of a Note or solution
+func getWorkFileName(name string) string {
+	if strings.HasSuffix(name, ".sol") {
+		return fmt.Sprintf("%s%s", SolutionSheets, name)
+	}
+	return fmt.Sprintf("%s%s", NoteTuningSheets, name)
+}
+
+// getWorkVersion returns the version of a Note or solution from the
+// working area
+func getWorkVersion(name string) string {
+	return txtparser.GetINIFileVersionSectionEntry(getWorkFileName(name), "version")
+}
+
+// getRollbackAttributes returns the attributes of a released solution
+// needed by handleAppliedSolution, when the solution is removed by a
+// rollback
+func getRollbackAttributes(rbName string, tApp *app.App) map[string]string {
+	attrs := make(map[string]string)
+	solName := strings.TrimSuffix(rbName, ".sol")
+	attrs["applied"] = getStageAppliedState(tApp, solName, rbName)
+	attrs["notes"] = strings.Join(tApp.AllSolutions[solName], " ")
+	if len(tApp.TuneForSolutions) > 0 {
+		attrs["enabledSol"] = tApp.TuneForSolutions[0]
+	}
+	return attrs
+}
+
 // showAnalysis does an analysis of the requested object in the staging area
 // to warn the user about possible issues or additional steps to perform.
 func showAnalysis(writer io.Writer, stageName string) (bool, int) {
@@ -339,6 +597,9 @@ func printSolAnalysis(writer io.Writer, stageName, txtPrefix, flag string) (bool
 
 	if stgFiles.StageAttributes[stageName]["override"] == "true" {
 		fmt.Fprint(writer, txtOverrideExists)
+		if flag == "updated" {
+			printOverrideIssues(writer, txtPrefix, getOverrideIssues(stageName))
+		}
 	}
 
 	retVal = writeIfNotNew(flag, stageName, writerDescriptor{writer, txtSolApplied, txtSolEnabled, txtSolNotEnabled, txtDelSolNotes}, retVal)
@@ -434,6 +695,9 @@ func printNoteAnalysis(writer io.Writer, stageName, txtPrefix, flag string) (boo
 	}
 	if stgFiles.StageAttributes[stageName]["override"] == "true" {
 		fmt.Fprint(writer, txtOverrideExists)
+		if flag == "updated" {
+			printOverrideIssues(writer, txtPrefix, getOverrideIssues(stageName))
+		}
 	}
 
 	retVal = writeIfNotNew(flag, stageName, writerDescriptor{writer, txtNoteApplied, txtNoteEnabled, txtNoteNotEnabled, txtNoteHints}, retVal)
@@ -476,11 +740,23 @@ func mvStageToWork(stageName string, tApp *app.App) error {
 	stagingFile := stgFiles.StageAttributes[stageName]["sfilename"]
 	workingFile := stgFiles.StageAttributes[stageName]["wfilename"]
 	packageFile := stgFiles.StageAttributes[stageName]["pfilename"]
+	workVers := ""
+	if _, err := os.Stat(workingFile); err == nil {
+		workVers = txtparser.GetINIFileVersionSectionEntry(workingFile, "version")
+	}
+	// save the working area file for 'staging rollback'
+	if err := backupWorkFile(stageName, workingFile); err != nil {
+		system.ErrorLog("Problems during backup of '%s' from working area, so it is not released: %v", stageName, err)
+		return fmt.Errorf("Problems during releasing '%s' from staging to working area", stageName)
+	}
 	// check, if note should be deleted
 	if _, err := os.Stat(workingFile); err == nil {
 		if _, perr := os.Stat(packageFile); os.IsNotExist(perr) {
 			// in working, but not in packaging, delete from working and staging
-			handleAppliedSolution(stageName, tApp)
+			state := handleAppliedSolution(stageName, stgFiles.StageAttributes[stageName], tApp)
+			if err := saveSolutionState(stageName, state); err != nil {
+				system.WarningLog("Problems saving the enabled state of solution '%s' for rollback: %v", stageName, err)
+			}
 			if rerr := os.Remove(workingFile); rerr != nil {
 				system.ErrorLog("Problems during removal of '%s' from working area: %v", stageName, rerr)
 				errs = append(errs, rerr)
@@ -491,6 +767,7 @@ func mvStageToWork(stageName string, tApp *app.App) error {
 			}
 			if len(errs) == 0 {
 				system.NoticeLog("'%s' successfully removed from working and staging area", stageName)
+				recordStagingHistory("release", stageName, workVers, "")
 				return nil
 			}
 		}
@@ -501,6 +778,7 @@ func mvStageToWork(stageName string, tApp *app.App) error {
 		errs = append(errs, err)
 	} else {
 		system.NoticeLog("'%s' successfully moved from staging to working area", stageName)
+		recordStagingHistory("release", stageName, workVers, txtparser.GetINIFileVersionSectionEntry(workingFile, "version"))
 	}
 	if len(errs) != 0 {
 		return fmt.Errorf("Problems during releasing '%s' from staging to working area", stageName)
@@ -510,16 +788,19 @@ func mvStageToWork(stageName string, tApp *app.App) error {
 
 // handleAppliedSolution checks, if the object to be deleted is a solution,
 // is applied and take care of the related notes of this solution
-func handleAppliedSolution(stageName string, tApp *app.App) {
+// attrs are the attributes of the solution (see collectStageFileInfo)
+// The changes of the enabled state are returned
+func handleAppliedSolution(stageName string, attrs map[string]string, tApp *app.App) solutionState {
+	state := solutionState{}
 	if !strings.HasSuffix(stageName, ".sol") {
 		// stage file is NOT a solution file, skip
-		return
+		return state
 	}
 	changed := false
 	// stage file is a solution file
-	if stgFiles.StageAttributes[stageName]["applied"] == "true" {
+	if attrs["applied"] == "true" {
 		// solution is applied, add notes to TUNE_FOR_NOTES
-		sol := strings.Split(stgFiles.StageAttributes[stageName]["notes"], " ")
+		sol := strings.Split(attrs["notes"], " ")
 		for _, note := range sol {
 			// check, if in NoteApplyOrder
 			pos := tApp.PositionInNoteApplyOrder(note)
@@ -532,13 +813,15 @@ func handleAppliedSolution(stageName string, tApp *app.App) {
 			if !(inTFN < len(tApp.TuneForNotes) && tApp.TuneForNotes[inTFN] == note) {
 				// add solutions's notes to additional notes list (TUNE_FOR_NOTES)
 				tApp.TuneForNotes = append(tApp.TuneForNotes, note)
+				state.Notes = append(state.Notes, note)
 				changed = true
 			}
 		}
 		// remove solution name from TUNE_FOR_SOLUTIONS
 		solName := strings.TrimSuffix(stageName, ".sol")
-		if solName == stgFiles.StageAttributes[stageName]["enabledSol"] {
+		if solName == attrs["enabledSol"] {
 			tApp.RemoveSolFromConfig(solName)
+			state.Solution = solName
 		}
 
 		if changed {
@@ -548,11 +831,63 @@ func handleAppliedSolution(stageName string, tApp *app.App) {
 			}
 		}
 	}
+	return state
+}
+
+// saveSolutionState saves the enabled state of a solution changed by
+// releasing the deletion of the solution together with the latest backup of
+// the solution. Nothing is saved, if the state was not changed
+func saveSolutionState(stageName string, state solutionState) error {
+	if state.Solution == "" && len(state.Notes) == 0 {
+		return nil
+	}
+	backup, _, err := getLatestBackup(stageName)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(backup+solStateBackupSuffix, content, 0644)
+}
+
+// restoreSolutionState restores the enabled state of a solution saved by
+// saveSolutionState. The solution is added to TUNE_FOR_SOLUTIONS again and
+// its Notes are removed from TUNE_FOR_NOTES. Nothing is done, if no state
+// was saved
+func restoreSolutionState(backup string, tApp *app.App) error {
+	stateFile := backup + solStateBackupSuffix
+	content, err := os.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	state := solutionState{}
+	if err := json.Unmarshal(content, &state); err != nil {
+		return err
+	}
+	for _, note := range state.Notes {
+		if i := sort.SearchStrings(tApp.TuneForNotes, note); i < len(tApp.TuneForNotes) && tApp.TuneForNotes[i] == note {
+			tApp.TuneForNotes = append(tApp.TuneForNotes[0:i], tApp.TuneForNotes[i+1:]...)
+		}
+	}
+	if state.Solution != "" && !tApp.IsSolutionEnabled(state.Solution) {
+		tApp.TuneForSolutions = append(tApp.TuneForSolutions, state.Solution)
+		sort.Strings(tApp.TuneForSolutions)
+	}
+	if err := tApp.SaveConfig(); err != nil {
+		return err
+	}
+	system.NoticeLog("Enabled state of solution '%s' restored", strings.TrimSuffix(path.Base(path.Dir(backup)), ".sol"))
+	return os.Remove(stateFile)
 }
 
 // getStagingFromConf reads STAGING setting from /etc/sysconfig/saptune
 func getStagingFromConf() bool {
-	sconf, err := txtparser.ParseSysconfigFile(saptuneSysconfig, true)
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, true)
 	if err != nil {
 		system.ErrorExit("Unable to read file '%s': '%v'\n", saptuneSysconfig, err, 2)
 	}
@@ -564,7 +899,7 @@ func getStagingFromConf() bool {
 
 // writeStagingToConf writes STAGING setting to /etc/sysconfig/saptune
 func writeStagingToConf(staging string) error {
-	sconf, err := txtparser.ParseSysconfigFile(saptuneSysconfig, true)
+	sconf, err := ParseSaptuneConfig(saptuneSysconfig, true)
 	if err != nil {
 		return err
 	}
@@ -608,7 +943,7 @@ func collectStageFileInfo(tuneApp *app.App) stageFiles {
 
 		// check for override file
 		stageMap["override"] = "false"
-		if _, override := getovFile(stageName, OverrideTuningSheets); override {
+		if len(getOverrideFiles(stageName)) != 0 {
 			stageMap["override"] = "true"
 		}
 		// check if applied
@@ -0,0 +1,319 @@
+package actions
+
+import (
+	"bytes"
+	"github.com/SUSE/saptune/app"
+	"github.com/SUSE/saptune/system"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStagingRollback(t *testing.T) {
+	oldStagingBackup := StagingBackup
+	oldStagingSheets := StagingSheets
+	oldNoteTuningSheets := NoteTuningSheets
+	oldStagingHistory := StagingHistory
+	defer func() {
+		StagingHistory = oldStagingHistory
+		StagingBackup = oldStagingBackup
+		StagingSheets = oldStagingSheets
+		NoteTuningSheets = oldNoteTuningSheets
+	}()
+	tstDir := "/tmp/saptune_rollback_test/"
+	defer os.RemoveAll(tstDir)
+	StagingBackup = tstDir + "backup/"
+	StagingHistory = tstDir + "history"
+	StagingSheets = tstDir + "latest/"
+	NoteTuningSheets = tstDir + "notes/"
+	_ = os.MkdirAll(StagingSheets, 0755)
+	_ = os.MkdirAll(NoteTuningSheets, 0755)
+	rbApp := app.InitialiseApp(TstFilesInGOPATH, "", tuningOpts, AllTestSolutions)
+
+	oldVers := "[version]\nVERSION=1\nDATE=01.01.2024\n"
+	newVers := "[version]\nVERSION=2\nDATE=01.01.2025\n"
+	workFile := NoteTuningSheets + "4711"
+
+	// nothing to roll back
+	if objs := listRollbackObjects(); len(objs) != 0 {
+		t.Errorf("expected no rollback objects, got '%v'", objs)
+	}
+	if err := rollbackStageObj("4711", rbApp); err == nil {
+		t.Error("expected an error for missing backup")
+	}
+
+	// release of an updated Note
+	_ = os.WriteFile(workFile, []byte(oldVers), 0644)
+	if err := backupWorkFile("4711", workFile); err != nil {
+		t.Error(err)
+	}
+	_ = os.WriteFile(workFile, []byte(newVers), 0644)
+	// release of a new Note
+	if err := backupWorkFile("4712", NoteTuningSheets+"4712"); err != nil {
+		t.Error(err)
+	}
+	_ = os.WriteFile(NoteTuningSheets+"4712", []byte(newVers), 0644)
+
+	if objs := listRollbackObjects(); strings.Join(objs, " ") != "4711 4712" {
+		t.Errorf("got '%v', expected '4711 4712'", objs)
+	}
+	backup, isNew, err := getLatestBackup("4711")
+	if err != nil || isNew || path.Dir(backup) != StagingBackup+"4711" {
+		t.Errorf("got '%s', '%v', '%v'", backup, isNew, err)
+	}
+	if _, isNew, _ = getLatestBackup("4712"); !isNew {
+		t.Error("backup of new Note not marked as new")
+	}
+	// backups in the same second are unique and keep their order
+	now := time.Date(2026, 10, 19, 12, 0, 0, 5, time.Local)
+	stamp := backupStamp(StagingBackup+"4711", now)
+	if stamp != "20261019120000000000005" {
+		t.Errorf("got '%s', expected '20261019120000000000005'", stamp)
+	}
+	_ = os.WriteFile(path.Join(StagingBackup+"4711", stamp), []byte(oldVers), 0644)
+	if next := backupStamp(StagingBackup+"4711", now); next != "20261019120000000000006" {
+		t.Errorf("got '%s', expected '20261019120000000000006'", next)
+	}
+	_ = os.Remove(path.Join(StagingBackup+"4711", stamp))
+	if _, _, err = getLatestBackup("../4711"); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+
+	// rollback of the updated Note
+	if err := rollbackStageObj("4711", rbApp); err != nil {
+		t.Error(err)
+	}
+	if cont, _ := os.ReadFile(workFile); string(cont) != oldVers {
+		t.Errorf("working file not restored, got '%s'", string(cont))
+	}
+	if cont, _ := os.ReadFile(StagingSheets + "4711"); string(cont) != newVers {
+		t.Errorf("released version not moved back to staging, got '%s'", string(cont))
+	}
+	if _, _, err := getLatestBackup("4711"); err == nil {
+		t.Error("used backup not removed")
+	}
+
+	// rollback of the new Note
+	if err := rollbackStageObj("4712", rbApp); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(NoteTuningSheets + "4712"); !os.IsNotExist(err) {
+		t.Error("new Note not removed from working area")
+	}
+	if _, err := os.Stat(StagingSheets + "4712"); err != nil {
+		t.Error("new Note not moved back to staging area")
+	}
+
+	history, err := readStagingHistory([]string{})
+	if err != nil || len(history) != 2 {
+		t.Fatalf("got '%v', '%v'", history, err)
+	}
+	if history[0].Action != "rollback" || history[0].Object != "4711" || history[0].OldVers != "2" || history[0].NewVers != "1" {
+		t.Errorf("wrong rollback record: '%+v'", history[0])
+	}
+	if history[1].Object != "4712" || history[1].OldVers != "2" || history[1].NewVers != "" {
+		t.Errorf("wrong rollback record: '%+v'", history[1])
+	}
+}
+
+func TestStagingHistory(t *testing.T) {
+	oldStagingHistory := StagingHistory
+	defer func() { StagingHistory = oldStagingHistory }()
+	tstDir := "/tmp/saptune_history_test/"
+	defer os.RemoveAll(tstDir)
+	StagingHistory = tstDir + "history"
+
+	buffer := bytes.Buffer{}
+	stagingActionHistory(&buffer, []string{})
+	if buffer.String() != "No staging operations recorded.\n" {
+		t.Errorf("got '%s'", buffer.String())
+	}
+
+	recordStagingHistory("enable", "", "", "")
+	recordStagingHistory("release", "2382421", "45", "46")
+	recordStagingHistory("release", "HANA.sol", "", "5")
+	// a damaged line is skipped
+	histFile, _ := os.OpenFile(StagingHistory, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = histFile.WriteString("no json\n")
+	histFile.Close()
+	recordStagingHistory("rollback", "2382421", "46", "45")
+
+	history, err := readStagingHistory([]string{})
+	if err != nil || len(history) != 4 {
+		t.Fatalf("got '%v', '%v'", history, err)
+	}
+	if history[0].Action != "enable" || history[0].Object != "" || history[0].User == "" || history[0].Time == "" {
+		t.Errorf("wrong record: '%+v'", history[0])
+	}
+	history, _ = readStagingHistory([]string{"2382421"})
+	if len(history) != 2 || history[0].Action != "release" || history[0].OldVers != "45" || history[0].NewVers != "46" || history[1].Action != "rollback" {
+		t.Errorf("wrong filtered history: '%+v'", history)
+	}
+
+	buffer.Reset()
+	stagingActionHistory(&buffer, []string{"HANA.sol"})
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "Time") || !strings.Contains(lines[1], "release   HANA.sol") || !strings.Contains(lines[1], "- -> 5") {
+		t.Errorf("got '%s'", buffer.String())
+	}
+}
+
+func TestOverrideIssues(t *testing.T) {
+	oldOverrideTuningSheets := OverrideTuningSheets
+	oldStgFiles := stgFiles
+	defer func() {
+		OverrideTuningSheets = oldOverrideTuningSheets
+		stgFiles = oldStgFiles
+	}()
+	tstDir := "/tmp/saptune_reconcile_test/"
+	defer os.RemoveAll(tstDir)
+	OverrideTuningSheets = tstDir + "override/"
+	_ = os.MkdirAll(OverrideTuningSheets, 0755)
+	oldNote := tstDir + "old_4711"
+	newNote := tstDir + "new_4711"
+	ovFile := OverrideTuningSheets + "4711"
+	_ = os.WriteFile(oldNote, []byte("[version]\nVERSION=1\n\n[sysctl]\nvm.swappiness=10\nvm.dirty_ratio=10\nkernel.shmmni=4096\n\n[vm]\nTHP=never\n\n[cpu]\ngovernor=performance\n"), 0644)
+	_ = os.WriteFile(newNote, []byte("[version]\nVERSION=2\n\n[sysctl]\nvm.swappiness=20\nvm.dirty_ratio=15\n\n[vm]\nTHP=never\n\n[sys]\ngovernor=performance\n"), 0644)
+	ovContent := "# my overrides\n[sysctl]\nvm.swappiness=30\nvm.dirty_ratio=15\nkernel.shmmni=8192\n\n[vm]\nTHP=\n\n[cpu]\ngovernor=powersave\n"
+	_ = os.WriteFile(ovFile, []byte(ovContent), 0644)
+	stgFiles = stageFiles{
+		AllStageFiles:   []string{"4711"},
+		StageAttributes: map[string]map[string]string{"4711": {"wfilename": oldNote, "sfilename": newNote, "updated": "true"}},
+	}
+
+	dropIn := OverrideTuningSheets + "4711.d/50-tst.conf"
+	_ = os.MkdirAll(path.Dir(dropIn), 0755)
+	_ = os.WriteFile(dropIn, []byte("[sysctl]\nvm.dirty_ratio=15\n"), 0644)
+	fileIssues := getOverrideIssues("4711")
+	if len(fileIssues) != 2 || fileIssues[0].File != ovFile || fileIssues[1].File != dropIn {
+		t.Fatalf("wrong override files with issues: '%+v'", fileIssues)
+	}
+	if len(fileIssues[1].Issues) != 1 || fileIssues[1].Issues[0].Action != "remove" {
+		t.Errorf("wrong issues of the drop-in file: '%+v'", fileIssues[1].Issues)
+	}
+	issues := fileIssues[0].Issues
+	exp := []string{
+		"[sysctl] vm.swappiness: default value changed from '10' to '20' in the new Note version, the override value '30' is still used",
+		"[sysctl] vm.dirty_ratio: override value '15' is the default value of the new Note version, so the override is no longer needed (proposal: remove the parameter from the override file)",
+		"[sysctl] kernel.shmmni: parameter removed or renamed in the new Note version, so the override is ignored (proposal: remove the parameter from the override file)",
+		"[cpu] governor: parameter moved to section [sys] in the new Note version, so the override is ignored (proposal: move the parameter to section [sys])",
+	}
+	if len(issues) != len(exp) {
+		t.Fatalf("got %d issues, expected %d: '%+v'", len(issues), len(exp), issues)
+	}
+	for idx, issue := range issues {
+		if issue.String() != exp[idx] {
+			t.Errorf("got: '%s', expected: '%s'", issue.String(), exp[idx])
+		}
+	}
+
+	// deleted Notes are not checked
+	stgFiles.StageAttributes["4711"]["updated"] = "false"
+	if issues := getOverrideIssues("4711"); len(issues) != 0 {
+		t.Errorf("got issues for a not updated Note: '%+v'", issues)
+	}
+
+	// the changes are applied after the release, the changed files are
+	// saved with the backup of the Note and restored by the rollback
+	oldStagingBackup := StagingBackup
+	defer func() { StagingBackup = oldStagingBackup }()
+	StagingBackup = tstDir + "backup/"
+	if err := backupWorkFile("4711", oldNote); err != nil {
+		t.Fatal(err)
+	}
+	applyOverrideReconcile("4711", fileIssues)
+	expContent := "# my overrides\n[sysctl]\nvm.swappiness=30\n\n[vm]\nTHP=\n\n[sys]\ngovernor=powersave\n"
+	if cont, _ := os.ReadFile(ovFile); string(cont) != expContent {
+		t.Errorf("got: '%s', expected: '%s'", string(cont), expContent)
+	}
+	if _, err := os.Stat(dropIn); !os.IsNotExist(err) {
+		t.Error("drop-in file without parameters not removed")
+	}
+	backup, _, err := getLatestBackup("4711")
+	if err != nil || strings.HasSuffix(backup, overrideBackupSuffix) {
+		t.Fatalf("got '%s', '%v'", backup, err)
+	}
+	if err := restoreOverrideFiles(backup); err != nil {
+		t.Fatal(err)
+	}
+	if cont, _ := os.ReadFile(ovFile); string(cont) != ovContent {
+		t.Errorf("override file not restored, got: '%s'", string(cont))
+	}
+	if cont, _ := os.ReadFile(dropIn); string(cont) != "[sysctl]\nvm.dirty_ratio=15\n" {
+		t.Errorf("drop-in file not restored, got: '%s'", string(cont))
+	}
+	if _, err := os.Stat(backup + overrideBackupSuffix); !os.IsNotExist(err) {
+		t.Error("saved override files not removed")
+	}
+}
+
+func TestStagingRollbackDeletedSolution(t *testing.T) {
+	oldStagingBackup := StagingBackup
+	oldStagingSheets := StagingSheets
+	oldSolutionSheets := SolutionSheets
+	oldStagingHistory := StagingHistory
+	defer func() {
+		StagingHistory = oldStagingHistory
+		StagingBackup = oldStagingBackup
+		StagingSheets = oldStagingSheets
+		SolutionSheets = oldSolutionSheets
+	}()
+	tstDir := "/tmp/saptune_rollback_sol_test/"
+	defer os.RemoveAll(tstDir)
+	StagingBackup = tstDir + "backup/"
+	StagingHistory = tstDir + "history"
+	StagingSheets = tstDir + "latest/"
+	SolutionSheets = tstDir + "sols/"
+	_ = os.MkdirAll(SolutionSheets, 0755)
+	_ = os.MkdirAll(tstDir+"etc/sysconfig", 0755)
+	if err := system.CopyFile(path.Join(TstFilesInGOPATH, "etc/sysconfig/saptune"), tstDir+"etc/sysconfig/saptune"); err != nil {
+		t.Fatal(err)
+	}
+	rbApp := app.InitialiseApp(tstDir, "", tuningOpts, AllTestSolutions)
+	rbApp.TuneForSolutions = []string{"sol1"}
+	rbApp.TuneForNotes = []string{}
+	rbApp.NoteApplyOrder = []string{"simpleNote"}
+	if err := rbApp.SaveConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	// release of the deletion of the applied solution
+	workFile := SolutionSheets + "sol1.sol"
+	solVers := "[version]\nVERSION=1\nDATE=01.01.2024\n[ArchX86]\nsimpleNote\n"
+	_ = os.WriteFile(workFile, []byte(solVers), 0644)
+	if err := backupWorkFile("sol1.sol", workFile); err != nil {
+		t.Fatal(err)
+	}
+	state := handleAppliedSolution("sol1.sol", map[string]string{"applied": "true", "notes": "simpleNote", "enabledSol": "sol1"}, rbApp)
+	if state.Solution != "sol1" || strings.Join(state.Notes, " ") != "simpleNote" {
+		t.Errorf("unexpected solution state '%+v'", state)
+	}
+	if err := saveSolutionState("sol1.sol", state); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(workFile)
+	if len(rbApp.TuneForSolutions) != 0 || strings.Join(rbApp.TuneForNotes, " ") != "simpleNote" {
+		t.Errorf("got '%v', '%v'", rbApp.TuneForSolutions, rbApp.TuneForNotes)
+	}
+	backup, isNew, err := getLatestBackup("sol1.sol")
+	if err != nil || isNew || strings.HasSuffix(backup, solStateBackupSuffix) {
+		t.Fatalf("got '%s', '%v', '%v'", backup, isNew, err)
+	}
+
+	// the rollback restores the solution and its enabled state
+	if err := rollbackStageObj("sol1.sol", rbApp); err != nil {
+		t.Error(err)
+	}
+	if cont, _ := os.ReadFile(workFile); string(cont) != solVers {
+		t.Errorf("solution not restored, got '%s'", string(cont))
+	}
+	if _, err := os.Stat(backup + solStateBackupSuffix); !os.IsNotExist(err) {
+		t.Error("saved solution state not removed")
+	}
+	savedApp := app.InitialiseApp(tstDir, "", tuningOpts, AllTestSolutions)
+	if strings.Join(savedApp.TuneForSolutions, " ") != "sol1" || len(savedApp.TuneForNotes) != 0 || strings.Join(savedApp.NoteApplyOrder, " ") != "simpleNote" {
+		t.Errorf("enabled state not restored, got '%v', '%v', '%v'", savedApp.TuneForSolutions, savedApp.TuneForNotes, savedApp.NoteApplyOrder)
+	}
+}
@@ -0,0 +1,126 @@
+package actions
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"io"
+	"os"
+	"os/user"
+	"path"
+	"time"
+)
+
+// historyUser returns the name of the user running saptune. If saptune is
+// called by sudo, the name of the calling user is added
+func historyUser() string {
+	name := "unknown"
+	if usr, err := user.Current(); err == nil {
+		name = usr.Username
+	}
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && sudoUser != name {
+		name = fmt.Sprintf("%s (sudo by %s)", name, sudoUser)
+	}
+	return name
+}
+
+// recordStagingHistory appends a staging operation to the staging history.
+// oldVers and newVers are the versions of the Note or solution from the
+// [version] section before and after the operation
+func recordStagingHistory(action, object, oldVers, newVers string) {
+	entry := system.JStagingHistoryEntry{
+		Time:    time.Now().Format(time.RFC3339),
+		User:    historyUser(),
+		Action:  action,
+		Object:  object,
+		OldVers: oldVers,
+		NewVers: newVers,
+	}
+	record, err := json.Marshal(entry)
+	if err != nil {
+		system.WarningLog("Problems recording the staging action '%s' of '%s': %v", action, object, err)
+		return
+	}
+	if err := os.MkdirAll(path.Dir(StagingHistory), 0755); err != nil {
+		system.WarningLog("Problems recording the staging action '%s' of '%s': %v", action, object, err)
+		return
+	}
+	histFile, err := os.OpenFile(StagingHistory, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		system.WarningLog("Problems recording the staging action '%s' of '%s': %v", action, object, err)
+		return
+	}
+	defer histFile.Close()
+	if _, err := histFile.Write(append(record, '\n')); err != nil {
+		system.WarningLog("Problems recording the staging action '%s' of '%s': %v", action, object, err)
+	}
+}
+
+// readStagingHistory reads the staging history. If objects are given, only
+// the entries of these Notes or solutions are returned.
+// A missing history file results in an empty history, lines which can not
+// be read are reported and skipped
+func readStagingHistory(objects []string) ([]system.JStagingHistoryEntry, error) {
+	history := []system.JStagingHistoryEntry{}
+	histFile, err := os.Open(StagingHistory)
+	if os.IsNotExist(err) {
+		return history, nil
+	} else if err != nil {
+		return history, err
+	}
+	defer histFile.Close()
+	filter := make(map[string]bool)
+	for _, obj := range objects {
+		filter[obj] = true
+	}
+	scanner := bufio.NewScanner(histFile)
+	lnr := 0
+	for scanner.Scan() {
+		lnr++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		entry := system.JStagingHistoryEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			system.WarningLog("Skipping line %d of the staging history '%s' - %v", lnr, StagingHistory, err)
+			continue
+		}
+		if len(filter) != 0 && !filter[entry.Object] {
+			continue
+		}
+		history = append(history, entry)
+	}
+	return history, scanner.Err()
+}
+
+// stagingActionHistory prints the recorded staging operations in the order
+// they were done, optional restricted to the given Notes or solutions
+func stagingActionHistory(writer io.Writer, sObject []string) {
+	history, err := readStagingHistory(sObject)
+	if err != nil {
+		system.ErrorExit("Unable to read the staging history '%s' - %v", StagingHistory, err, 1)
+	}
+	system.Jcollect(system.JStagingHistory{History: history})
+	if len(history) == 0 {
+		fmt.Fprintf(writer, "No staging operations recorded.\n")
+		return
+	}
+	format := "%-25s %-9s %-20s %-21s %s\n"
+	fmt.Fprintf(writer, format, "Time", "Action", "Note/Solution", "Version", "User")
+	for _, entry := range history {
+		vers := ""
+		if entry.OldVers != "" || entry.NewVers != "" {
+			vers = fmt.Sprintf("%s -> %s", dashIfEmpty(entry.OldVers), dashIfEmpty(entry.NewVers))
+		}
+		fmt.Fprintf(writer, format, entry.Time, entry.Action, dashIfEmpty(entry.Object), vers, entry.User)
+	}
+}
+
+// dashIfEmpty returns '-' for an empty string
+func dashIfEmpty(str string) string {
+	if str == "" {
+		return "-"
+	}
+	return str
+}
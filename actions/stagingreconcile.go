@@ -0,0 +1,322 @@
+package actions
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// overrideBackupSuffix is the suffix of the directory, which contains the
+// override files saved together with the backup of a released Note
+const overrideBackupSuffix = ".override"
+
+// overrideIssue is a parameter of an override file, which needs attention
+// after the release of a new Note version
+type overrideIssue struct {
+	Header string // section header of the override file
+	Key    string // parameter name as used in the override file
+	Op     txtparser.Operator
+	Value  string
+	Reason string
+	// Action is the proposed change of the override file - 'remove',
+	// 'migrate' (move to NewHeader) or empty (nothing to change)
+	Action    string
+	NewHeader string
+}
+
+// String returns the issue in the format '[header] key: reason (proposal)'
+func (issue overrideIssue) String() string {
+	proposal := ""
+	switch issue.Action {
+	case "remove":
+		proposal = " (proposal: remove the parameter from the override file)"
+	case "migrate":
+		proposal = fmt.Sprintf(" (proposal: move the parameter to section [%s])", issue.NewHeader)
+	}
+	return fmt.Sprintf("[%s] %s: %s%s", issue.Header, issue.Key, issue.Reason, proposal)
+}
+
+// overrideFileIssues contains the issues of the override file or of an
+// override drop-in file of a Note
+type overrideFileIssues struct {
+	File   string
+	Issues []overrideIssue
+}
+
+// getOverrideIssues checks the override file and the override drop-in files
+// (NOTEID.d/*.conf) of a Note against the new Note version and returns the
+// issues of each file.
+// Only updated Notes are checked, the override files of a deleted Note are
+// reported by the analysis as a whole
+func getOverrideIssues(stageName string) []overrideFileIssues {
+	fileIssues := []overrideFileIssues{}
+	if strings.HasSuffix(stageName, ".sol") || stgFiles.StageAttributes[stageName]["updated"] != "true" {
+		return fileIssues
+	}
+	for _, ovFileName := range getOverrideFiles(stageName) {
+		if issues := getOverrideFileIssues(stageName, ovFileName); len(issues) != 0 {
+			fileIssues = append(fileIssues, overrideFileIssues{File: ovFileName, Issues: issues})
+		}
+	}
+	return fileIssues
+}
+
+// getOverrideFileIssues compares the Note version of the working area, the
+// new Note version of the staging area and an override file of the Note.
+// It returns the override parameters, which refer to parameters no longer
+// part of the new Note version, which duplicate the new default value or
+// whose default value changed.
+func getOverrideFileIssues(stageName, ovFileName string) []overrideIssue {
+	issues := []overrideIssue{}
+	ovRes, err := txtparser.LintINIFile(ovFileName, note.NoteSections)
+	if err != nil {
+		system.ErrorLog("Problems while reading the override file '%s' - %v", ovFileName, err)
+		return issues
+	}
+	oldEntries := getNoteEntries(stgFiles.StageAttributes[stageName]["wfilename"])
+	newEntries := getNoteEntries(stgFiles.StageAttributes[stageName]["sfilename"])
+	for _, ovEntry := range ovRes.Entries {
+		if ovEntry.Section == note.INISectionVersion || ovEntry.Section == note.INISectionRpm || ovEntry.Section == note.INISectionReminder {
+			continue
+		}
+		issue := overrideIssue{Header: ovEntry.Header, Key: strings.TrimPrefix(ovEntry.Key, overrideKeyPrefix[ovEntry.Section]), Op: ovEntry.Operator, Value: ovEntry.Value}
+		newEnts := lookupNoteEntries(newEntries, ovEntry.Section, ovEntry.Key)
+		if len(newEnts) == 0 {
+			if headers := findKeyHeaders(newEntries, issue.Key); len(headers) == 1 {
+				issue.Reason = fmt.Sprintf("parameter moved to section [%s] in the new Note version, so the override is ignored", headers[0])
+				issue.Action = "migrate"
+				issue.NewHeader = headers[0]
+			} else if len(lookupNoteEntries(oldEntries, ovEntry.Section, ovEntry.Key)) != 0 {
+				issue.Reason = "parameter removed or renamed in the new Note version, so the override is ignored"
+				issue.Action = "remove"
+			} else {
+				issue.Reason = "parameter is not part of the new Note version, so the override is ignored"
+				issue.Action = "remove"
+			}
+			issues = append(issues, issue)
+			continue
+		}
+		if ovEntry.Value == "" {
+			// untouched parameter, independent of the default value
+			continue
+		}
+		newVal, ok := defaultNoteValue(newEnts, ovEntry.Header)
+		if !ok {
+			continue
+		}
+		oldVal, oldOK := defaultNoteValue(lookupNoteEntries(oldEntries, ovEntry.Section, ovEntry.Key), ovEntry.Header)
+		if newVal == ovEntry.Value {
+			issue.Reason = fmt.Sprintf("override value '%s' is the default value of the new Note version, so the override is no longer needed", ovEntry.Value)
+			issue.Action = "remove"
+			issues = append(issues, issue)
+		} else if oldOK && oldVal != newVal {
+			issue.Reason = fmt.Sprintf("default value changed from '%s' to '%s' in the new Note version, the override value '%s' is still used", oldVal, newVal, ovEntry.Value)
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// getNoteEntries returns the parameter entries of a Note definition file.
+// A missing file results in an empty list
+func getNoteEntries(fileName string) []txtparser.LintEntry {
+	res, err := txtparser.LintINIFile(fileName, note.NoteSections)
+	if err != nil {
+		return []txtparser.LintEntry{}
+	}
+	return res.Entries
+}
+
+// lookupNoteEntries returns all entries of a parameter in the section of a
+// Note definition. A service can be named with or without the suffix
+// '.service'
+func lookupNoteEntries(entries []txtparser.LintEntry, section, key string) []txtparser.LintEntry {
+	found := []txtparser.LintEntry{}
+	for _, entry := range entries {
+		if entry.Section == section && (entry.Key == key || entry.Key == key+".service") {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// findKeyHeaders returns the section headers of a Note definition, which
+// contain the parameter (name without section prefix)
+func findKeyHeaders(entries []txtparser.LintEntry, key string) []string {
+	headers := []string{}
+	found := make(map[string]bool)
+	for _, entry := range entries {
+		if strings.TrimPrefix(entry.Key, overrideKeyPrefix[entry.Section]) == key && !found[entry.Header] {
+			found[entry.Header] = true
+			headers = append(headers, entry.Header)
+		}
+	}
+	return headers
+}
+
+// defaultNoteValue returns the value of a parameter of a Note definition.
+// The entry of the same section header is preferred. Otherwise the value is
+// only unique, if all entries of the parameter use the same value
+func defaultNoteValue(entries []txtparser.LintEntry, header string) (string, bool) {
+	if len(entries) == 0 {
+		return "", false
+	}
+	for _, entry := range entries {
+		if entry.Header == header {
+			return entry.Value, true
+		}
+	}
+	for _, entry := range entries {
+		if entry.Value != entries[0].Value {
+			return "", false
+		}
+	}
+	return entries[0].Value, true
+}
+
+// printOverrideIssues prints the issues of the override files of a Note
+func printOverrideIssues(writer io.Writer, txtPrefix string, fileIssues []overrideFileIssues) {
+	indent := strings.Repeat(" ", len(txtPrefix))
+	for _, fIssues := range fileIssues {
+		fmt.Fprintf(writer, "%s%s:\n", indent, fIssues.File)
+		for _, issue := range fIssues.Issues {
+			fmt.Fprintf(writer, "%s    %s\n", indent, issue.String())
+		}
+	}
+}
+
+// reconcileOverrides offers the proposed changes of the override files of a
+// Note before the new Note version is released and returns the accepted
+// changes. They are applied by applyOverrideReconcile after the Note was
+// released successfully. With '--force' the override files are not
+// changed, the issues are only reported
+func reconcileOverrides(reader io.Reader, writer io.Writer, stageName string) []overrideFileIssues {
+	changes := []overrideFileIssues{}
+	for _, fIssues := range getOverrideIssues(stageName) {
+		for _, issue := range fIssues.Issues {
+			if issue.Action != "" {
+				changes = append(changes, fIssues)
+				break
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	fmt.Fprintf(writer, "\nThe override files of Note %s do not match the new version of the Note:\n", stageName)
+	printOverrideIssues(writer, "", changes)
+	if system.IsFlagSet("force") {
+		system.WarningLog("Flag 'force' set, so the override files of Note '%s' are not changed. Please check the files", stageName)
+		return nil
+	}
+	if !readYesNo("Do you want to apply the proposed changes to the override files", reader, writer) {
+		system.NoticeLog("Override files of Note '%s' not changed. Please check the files", stageName)
+		return nil
+	}
+	return changes
+}
+
+// applyOverrideReconcile saves the override files, which are going to be
+// changed, together with the backup of the released Note, so that 'staging
+// rollback' can restore them, and applies the accepted changes afterwards
+func applyOverrideReconcile(stageName string, changes []overrideFileIssues) {
+	if len(changes) == 0 {
+		return
+	}
+	if err := backupOverrideFiles(stageName, changes); err != nil {
+		system.ErrorLog("Problems during backup of the override files of Note '%s', so they are not changed. Please check the files - %v", stageName, err)
+		return
+	}
+	for _, change := range changes {
+		if err := applyOverrideIssues(change.File, change.Issues); err != nil {
+			system.ErrorLog("Problems while changing the override file '%s' - %v", change.File, err)
+		}
+	}
+}
+
+// backupOverrideFiles copies the override files to the directory
+// <backup>.override next to the latest backup of the Note. The file names
+// are kept relative to the override directory
+func backupOverrideFiles(stageName string, changes []overrideFileIssues) error {
+	backup, _, err := getLatestBackup(stageName)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		rel, err := filepath.Rel(OverrideTuningSheets, change.File)
+		if err != nil {
+			return err
+		}
+		dest := path.Join(backup+overrideBackupSuffix, rel)
+		if err := os.MkdirAll(path.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := system.CopyFile(change.File, dest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreOverrideFiles copies the override files saved by
+// backupOverrideFiles back to the override directory and removes the saved
+// files. Nothing is done, if no override files were saved
+func restoreOverrideFiles(backup string) error {
+	ovBackup := backup + overrideBackupSuffix
+	if _, err := os.Stat(ovBackup); os.IsNotExist(err) {
+		return nil
+	}
+	err := filepath.WalkDir(ovBackup, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(ovBackup, file)
+		if err != nil {
+			return err
+		}
+		dest := path.Join(OverrideTuningSheets, rel)
+		if err := os.MkdirAll(path.Dir(dest), 0755); err != nil {
+			return err
+		}
+		system.NoticeLog("Override file '%s' restored", dest)
+		return system.CopyFile(file, dest)
+	})
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(ovBackup)
+}
+
+// applyOverrideIssues changes the override file as proposed by the issues.
+// The override file is removed, if no parameter is left
+func applyOverrideIssues(ovFileName string, issues []overrideIssue) error {
+	doc, err := txtparser.ReadINIDocument(ovFileName)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		switch issue.Action {
+		case "remove":
+			doc.Delete(issue.Header, issue.Key)
+		case "migrate":
+			doc.Delete(issue.Header, issue.Key)
+			doc.Set(issue.NewHeader, issue.Key, string(issue.Op), issue.Value)
+		}
+	}
+	if res := txtparser.LintINI(ovFileName, doc.String(), note.NoteSections); len(res.Entries) == 0 {
+		system.NoticeLog("No parameter left in the override file '%s', so the file is removed", ovFileName)
+		return os.Remove(ovFileName)
+	}
+	if _, err := doc.WriteFile(ovFileName, 0644); err != nil {
+		return err
+	}
+	system.NoticeLog("Override file '%s' adjusted to the new Note version", ovFileName)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/SUSE/saptune/system"
 	"github.com/SUSE/saptune/txtparser"
 	"io"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,8 +26,6 @@ var lineCnt = 0
 // PrintNoteFields Print mismatching fields in the note comparison result.
 func PrintNoteFields(writer io.Writer, header string, noteComparisons map[string]map[string]note.FieldComparison, printComparison bool, result *system.JPNotes) {
 	// initialise
-	colFormat := ""
-	colCompliant := ""
 	printHead := ""
 	noteField := ""
 	reminder := make(map[string]string)
@@ -46,6 +45,9 @@ func PrintNoteFields(writer io.Writer, header string, noteComparisons map[string
 	// setup table format values
 	fmtlen0, fmtlen1, fmtlen2, fmtlen3, fmtlen4, format := setupTableFormat(sortkeys, noteComparisons, printComparison)
 
+	// override file or drop-in file of each override value
+	ovSources := make(map[string]map[string]string)
+
 	// print
 	noteID := ""
 	for _, skey := range sortkeys {
@@ -89,27 +91,28 @@ func PrintNoteFields(writer io.Writer, header string, noteComparisons map[string
 		}
 		noteLine.ActValue = &pAct
 
-		tableColumns := make(map[string]string)
-		if printComparison {
-			// verify
-			if system.IsFlagSet("show-non-compliant") && (strings.Contains(compliant, "yes") || strings.Contains(compliant, "-")) {
-				// print only non-compliant rows, so skip the others
-				continue
-			}
-			colFormat, colCompliant = colorPrint(format, compliant, colorScheme)
-			tableColumns = map[string]string{"type": "verify", "colFormat": colFormat, "note": noteField, "parameter": comparison.ReflectMapKey, "expected": pExp, "override": override, "actual": pAct, "compliant": colCompliant}
-		} else {
-			// simulate
-			tableColumns = map[string]string{"type": "simulate", "colFormat": format, "parameter": comparison.ReflectMapKey, "actual": pAct, "expected": pExp, "override": override, "comment": comment}
+		if printComparison && system.IsFlagSet("show-non-compliant") && (strings.Contains(compliant, "yes") || strings.Contains(compliant, "-")) {
+			// verify - print only non-compliant rows, so skip the others
+			continue
 		}
-		printTableRow(writer, tableColumns)
+		// the table row on the screen and the machine readable output
+		// (json, yaml, csv) are based on the same result line
 		noteLine = collectMRO(noteLine, compliant, noteID, noteComparisons, comparison, pExp, override, printComparison, comment, footnote, pAct)
+		noteLine.OverSource = ""
+		if override != "" {
+			if _, ok := ovSources[noteID]; !ok {
+				ovSources[noteID] = getOverrideSources(noteID)
+			}
+			noteLine.OverSource = ovSources[noteID][key]
+		}
+		printTableRow(writer, tableRowFromLine(noteLine, noteField, pAct, compliant, format, colorScheme, printComparison))
 		noteList = append(noteList, noteLine)
 	}
 
 	// print footer
 	reminderList := []system.JPNotesRemind{}
 	printTableFooter(writer, header, footnote, reminder, &reminderList)
+	printOverrideSources(writer, noteList)
 	if result != nil {
 		if printComparison {
 			// verify
@@ -152,6 +155,7 @@ func collectMRO(stuff ...interface{}) system.JPNotesLine {
 		nLine.Compliant = nil
 	}
 	noteFNs := []system.JFootNotes{}
+	fnCodes := []string{}
 	fns := system.JFootNotes{}
 	for _, fn := range strings.Fields(stuff[8].(string)) {
 		indx := fn[1 : len(fn)-1]
@@ -159,10 +163,13 @@ func collectMRO(stuff ...interface{}) system.JPNotesLine {
 		if idx > 0 {
 			fns.FNoteNumber = idx
 			fns.FNoteTxt = stuff[9].([]string)[idx-1]
+			fns.FNoteCode, fns.FNoteCategory = getFootnoteCode(idx, fns.FNoteTxt)
+			fnCodes = append(fnCodes, fns.FNoteCode)
 		}
 		noteFNs = append(noteFNs, fns)
 	}
 	nLine.Footnotes = noteFNs
+	nLine.FootnoteCodes = fnCodes
 
 	if stuff[10].(string) == "NA" || stuff[10].(string) == "PNA" || stuff[10].(string) == "all:none" {
 		nLine.ActValue = nil
@@ -170,6 +177,20 @@ func collectMRO(stuff ...interface{}) system.JPNotesLine {
 	return nLine
 }
 
+// tableRowFromLine returns the columns of a 'verify' or 'simulate' table row
+// for the screen output from the machine readable result line.
+// The actual value is passed separately, as the result line does not contain
+// values like 'NA', which are displayed in the table
+func tableRowFromLine(nLine system.JPNotesLine, noteField, pAct, compliant, format, colorScheme string, printComp bool) map[string]string {
+	if !printComp {
+		// simulate
+		return map[string]string{"type": "simulate", "colFormat": format, "parameter": nLine.Parameter, "actual": pAct, "expected": nLine.ExpValue, "override": nLine.OverValue, "comment": nLine.Comment}
+	}
+	// verify
+	colFormat, colCompliant := colorPrint(format, compliant, colorScheme)
+	return map[string]string{"type": "verify", "colFormat": colFormat, "note": noteField, "parameter": nLine.Parameter, "expected": nLine.ExpValue, "override": nLine.OverValue, "actual": pAct, "compliant": colCompliant}
+}
+
 // sortNoteComparisonsOutput sorts the output of the Note comparison
 // the reminder section should be the last one
 func sortNoteComparisonsOutput(noteCompare map[string]map[string]note.FieldComparison) []string {
@@ -302,6 +323,37 @@ func printTableFooter(writer io.Writer, header string, footnote []string, remind
 	}
 }
 
+// getOverrideSources returns the override file or override drop-in file,
+// which supplied the override value, for all parameters of the overrides
+// of a Note
+func getOverrideSources(noteID string) map[string]string {
+	sources := make(map[string]string)
+	override, ow := txtparser.GetOverrides("ovw", noteID)
+	if !override {
+		return sources
+	}
+	for _, entry := range ow.AllValues {
+		sources[entry.Key] = entry.Source
+	}
+	return sources
+}
+
+// printOverrideSources prints the override drop-in files and the solution
+// definitions, which supplied the override values of the table
+func printOverrideSources(writer io.Writer, noteList []system.JPNotesLine) {
+	head := "Override values supplied by drop-in files or solutions:\n"
+	for _, line := range noteList {
+		if filepath.Base(filepath.Dir(line.OverSource)) != line.NoteID+".d" && !strings.HasSuffix(line.OverSource, ".sol") {
+			continue
+		}
+		fmt.Fprintf(writer, "%s   %s, %s: %s\n", head, line.NoteID, line.Parameter, line.OverSource)
+		head = ""
+	}
+	if head == "" {
+		fmt.Fprintf(writer, "\n")
+	}
+}
+
 // getNoteAndVersion sets printHead, noteID, noteField for the next table row
 func getNoteAndVersion(kField, nID, nField string, nComparisons map[string]map[string]note.FieldComparison) (string, string, string) {
 	pHead := ""
@@ -392,7 +444,7 @@ func getColorScheme() string {
 		system.InfoLog("color scheme defined by command line flag - %s", scheme)
 	} else {
 		// no flag, check sysconfig file
-		sconf, err := txtparser.ParseSysconfigFile(saptuneSysconfig, false)
+		sconf, err := ParseSaptuneConfig(saptuneSysconfig, false)
 		if err == nil {
 			scheme = sconf.GetString("COLOR_SCHEME", "")
 			if scheme != "" {
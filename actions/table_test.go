@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"github.com/SUSE/saptune/sap/note"
 	"github.com/SUSE/saptune/system"
+	"io"
 	"os"
 	"path"
+	"strings"
 	"testing"
 )
 
@@ -270,6 +272,34 @@ func TestPrintNoteFields(t *testing.T) {
 		checkCorrectMessage(t, txt, printMatchText4)
 	})
 
+	t.Run("verify footnote codes", func(t *testing.T) {
+		result := system.JPNotes{}
+		PrintNoteFields(io.Discard, "NONE", noteComp, true, &result)
+		fn1Code := footnote1Codes[footnote1]
+		expCodes := map[string]string{
+			"IO_SCHEDULER_sdb":           fn1Code + " unsupported-scheduler untouched",
+			"IO_SCHEDULER_sdd":           "untouched defined-twice",
+			"ShmFileSystemSizeMB":        "",
+			"force_latency":              fn1Code + " cpu-idle-state-differs",
+			"grub:intel_idle.max_cstate": "not-available check-only grub-covered",
+			"kernel.shmmni":              "parameter-not-available untouched",
+		}
+		for _, line := range result.Verifications {
+			exp, ok := expCodes[line.Parameter]
+			if !ok {
+				continue
+			}
+			if strings.Join(line.FootnoteCodes, " ") != exp {
+				t.Errorf("wrong footnote codes for '%s': '%v', expected '%s'", line.Parameter, line.FootnoteCodes, exp)
+			}
+			for _, fn := range line.Footnotes {
+				if fn.FNoteCode == "" || fn.FNoteCategory == "" {
+					t.Errorf("missing code or category for footnote '%+v' of '%s'", fn, line.Parameter)
+				}
+			}
+		}
+	})
+
 	t.Run("verify with header and show-non-compliant", func(t *testing.T) {
 		//os.Args = []string{"saptune", "--format", "json", "note", "list", "--colorscheme", "black", "--show-non-compliant", "--force", "--dryrun", "--help", "--version"}
 		os.Args = []string{"saptune", "note", "list", "--colorscheme", "black", "--show-non-compliant", "--force", "--dryrun", "--help", "--version"}
@@ -282,6 +312,25 @@ func TestPrintNoteFields(t *testing.T) {
 	})
 }
 
+func TestGetFootnoteCode(t *testing.T) {
+	code, cat := getFootnoteCode(13, footnote13)
+	if code != "nr-requests-multiqueue" || cat != fnCatEnvironment {
+		t.Errorf("wrong code '%s' or category '%s' for footnote [13]", code, cat)
+	}
+	code, cat = getFootnoteCode(1, footnote1AZR)
+	if code != "not-available-azure" || cat != fnCatNotApplicable {
+		t.Errorf("wrong code '%s' or category '%s' for footnote [1] on Azure", code, cat)
+	}
+	code, cat = getFootnoteCode(4, footnote4)
+	if code != "cpu-idle-state-differs" || cat != fnCatDeviation {
+		t.Errorf("wrong code '%s' or category '%s' for footnote [4]", code, cat)
+	}
+	code, cat = getFootnoteCode(17, "")
+	if code != "" || cat != "" {
+		t.Errorf("expected no code and category for an unknown footnote, got '%s' and '%s'", code, cat)
+	}
+}
+
 func TestGetColorScheme(t *testing.T) {
 	os.Args = []string{"saptune", "status"}
 	system.RereadArgs()
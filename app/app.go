@@ -54,6 +54,7 @@ func InitialiseApp(sysconfigPrefix, stateDirPrefix string, allNotes map[string]n
 	sort.Strings(app.TuneForSolutions)
 	sort.Strings(app.TuneForNotes)
 	// Never ever sort app.NoteApplyOrder !
+	app.setSolutionNoteOverrides()
 	return
 }
 
@@ -138,6 +139,38 @@ func (app *App) TuneAll() error {
 	return nil
 }
 
+// TuneConfiguration enables the given solutions and applies the given notes
+// in the given note apply order, e.g. to take over the configuration of
+// another host.
+// The notes of the solutions, which are not part of the note apply order,
+// are applied at the end.
+func (app *App) TuneConfiguration(solNames, noteIDs, applyOrder []string) error {
+	app.TuneForSolutions = append([]string{}, solNames...)
+	sort.Strings(app.TuneForSolutions)
+	if err := app.SaveConfig(); err != nil {
+		return err
+	}
+	app.setSolutionNoteOverrides()
+	for _, noteID := range applyOrder {
+		if err := app.TuneNote(noteID); err != nil {
+			return err
+		}
+	}
+	for _, noteID := range noteIDs {
+		if app.PositionInNoteApplyOrder(noteID) < 0 {
+			if err := app.TuneNote(noteID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, solName := range app.TuneForSolutions {
+		if _, err := app.TuneSolution(solName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RevertAll revert all tuned parameters (both solutions and additional notes),
 // and clear stored states, but NOT NoteApplyOrder.
 func (app *App) RevertAll(permanent bool) error {
@@ -167,6 +200,7 @@ func (app *App) RevertAll(permanent bool) error {
 		if err := app.SaveConfig(); err != nil {
 			allErrs = append(allErrs, err)
 		}
+		app.setSolutionNoteOverrides()
 	}
 	if len(allErrs) == 0 {
 		return nil
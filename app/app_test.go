@@ -263,3 +263,21 @@ func TestInitialiseApp(t *testing.T) {
 		t.Error(tstApp)
 	}
 }
+
+func TestTuneConfiguration(t *testing.T) {
+	os.RemoveAll(SampleNoteDataDir)
+	defer os.RemoveAll(SampleNoteDataDir)
+	tuneApp := InitialiseApp(path.Join(SampleNoteDataDir, "conf"), path.Join(SampleNoteDataDir, "data"), AllTestNotes, AllTestSolutions)
+	if err := tuneApp.TuneConfiguration([]string{"sol1"}, []string{"1002"}, []string{"1002", "1001"}); err != nil {
+		t.Fatal(err)
+	}
+	VerifyConfig(t, tuneApp, []string{"1002"}, []string{"sol1"})
+	if !reflect.DeepEqual(tuneApp.NoteApplyOrder, []string{"1002", "1001"}) {
+		t.Errorf("expected NoteApplyOrder '[1002 1001]', got '%v'", tuneApp.NoteApplyOrder)
+	}
+	VerifyFileContent(t, SampleParamFile, "optimised1", "config")
+	if err := tuneApp.RevertAll(true); err != nil {
+		t.Fatal(err)
+	}
+	VerifyConfig(t, tuneApp, []string{}, []string{})
+}
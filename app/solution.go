@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/SUSE/saptune/sap/note"
 	"github.com/SUSE/saptune/sap/solution"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"sort"
 )
 
@@ -58,6 +60,33 @@ func (app *App) AppliedSolution() (string, string) {
 	return solApplied, state
 }
 
+// setSolutionNoteOverrides passes the Note parameter overrides of the
+// enabled solutions to the Notes. Only the overrides of Notes, which are
+// part of the solution, are used, so they take effect only while the
+// solution is applied
+func (app *App) setSolutionNoteOverrides() {
+	defaults := make(map[string]*txtparser.INIFile)
+	overrides := make(map[string]*txtparser.INIFile)
+	for _, solName := range app.TuneForSolutions {
+		solDef, solOver := solution.GetSolutionNoteOverrides(solName)
+		for _, noteID := range app.AllSolutions[solName] {
+			if ow, ok := solDef[noteID]; ok {
+				defaults[noteID] = ow
+			}
+			if ow, ok := solOver[noteID]; ok {
+				overrides[noteID] = ow
+			}
+		}
+	}
+	for noteID, noteObj := range app.AllNotes {
+		if iniNote, ok := noteObj.(note.INISettings); ok {
+			iniNote.SolutionDefaults = defaults[noteID]
+			iniNote.SolutionOverrides = overrides[noteID]
+			app.AllNotes[noteID] = iniNote
+		}
+	}
+}
+
 // GetSolutionByName return the solution corresponding to the name,
 // or an error if it does not exist.
 func (app *App) GetSolutionByName(name string) (solution.Solution, error) {
@@ -90,6 +119,7 @@ func (app *App) TuneSolution(solName string) (removedExplicitNotes []string, err
 			return
 		}
 	}
+	app.setSolutionNoteOverrides()
 	for _, noteID := range sol {
 		// Remove solution's notes from additional notes list.
 		if i := sort.SearchStrings(app.TuneForNotes, noteID); i < len(app.TuneForNotes) && app.TuneForNotes[i] == noteID {
@@ -118,6 +148,7 @@ func (app *App) RemoveSolFromConfig(solName string) error {
 			return err
 		}
 	}
+	app.setSolutionNoteOverrides()
 	return nil
 }
 
@@ -172,6 +203,109 @@ func (app *App) RevertSolution(solName string) error {
 	return fmt.Errorf("Failed to revert one or more SAP notes that belong to the solution: %v", noteErrs)
 }
 
+// SolutionNoteChanges compares the Note list of the enabled solution, which
+// was stored during the last apply of the solution, with the Note list of
+// the current solution definition and returns the added and the removed
+// Notes
+func (app *App) SolutionNoteChanges(solName string) (added, removed []string, err error) {
+	added = make([]string, 0)
+	removed = make([]string, 0)
+	sol, err := app.GetSolutionByName(solName)
+	if err != nil {
+		return
+	}
+	active, err := solution.GetActiveSolNoteInfo(solName, false)
+	if err != nil {
+		// fallback, if runtime info is not available
+		// e.g. after a reboot or solution apply was from
+		// saptune version < 3.2
+		// the applied Notes, which are not enabled additionally,
+		// belong to the solution
+		system.InfoLog("No stored Note list of solution '%s' available, using the applied Notes instead", solName)
+		active = solution.Solution{}
+		for _, noteID := range app.NoteApplyOrder {
+			if i := sort.SearchStrings(app.TuneForNotes, noteID); !(i < len(app.TuneForNotes) && app.TuneForNotes[i] == noteID) {
+				active = append(active, noteID)
+			}
+		}
+		err = nil
+	}
+	inActive := make(map[string]bool)
+	for _, noteID := range active {
+		inActive[noteID] = true
+	}
+	inSol := make(map[string]bool)
+	for _, noteID := range sol {
+		inSol[noteID] = true
+		if !inActive[noteID] {
+			added = append(added, noteID)
+		}
+	}
+	for _, noteID := range active {
+		if !inSol[noteID] {
+			removed = append(removed, noteID)
+		}
+	}
+	return
+}
+
+// KeepNoteOfSolution returns true, if a Note removed from the solution
+// definition has to stay applied, because it is enabled additionally or
+// referenced by another enabled solution
+func (app *App) KeepNoteOfSolution(noteID, solName string) bool {
+	if i := sort.SearchStrings(app.TuneForNotes, noteID); i < len(app.TuneForNotes) && app.TuneForNotes[i] == noteID {
+		return true
+	}
+	for _, otherSolName := range app.TuneForSolutions {
+		if otherSolName == solName {
+			continue
+		}
+		for _, otherNoteID := range app.AllSolutions[otherSolName] {
+			if otherNoteID == noteID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UpgradeSolution adapts the applied Notes of the enabled solution to the
+// current solution definition in one operation.
+// The Notes removed from the solution definition are reverted (see
+// KeepNoteOfSolution for the exceptions), the added Notes are applied and
+// the current Note list is stored as active Note list of the solution.
+func (app *App) UpgradeSolution(solName string) (reverted, applied []string, err error) {
+	reverted = make([]string, 0)
+	applied = make([]string, 0)
+	added, removed, err := app.SolutionNoteChanges(solName)
+	if err != nil {
+		return
+	}
+	noteErrs := make([]error, 0)
+	for _, noteID := range removed {
+		if app.KeepNoteOfSolution(noteID, solName) {
+			continue
+		}
+		if err := app.RevertNote(noteID, true); err != nil {
+			noteErrs = append(noteErrs, err)
+			continue
+		}
+		reverted = append(reverted, noteID)
+	}
+	if len(noteErrs) != 0 {
+		err = fmt.Errorf("Failed to revert one or more SAP notes removed from the solution: %v", noteErrs)
+		return
+	}
+	for _, noteID := range added {
+		if _, ok := app.IsNoteApplied(noteID); !ok {
+			applied = append(applied, noteID)
+		}
+	}
+	// apply the added Notes and store the current Note list
+	_, err = app.TuneSolution(solName)
+	return
+}
+
 // VerifySolution inspect the system and verify that all parameters conform
 // to all of the notes associated to the solution.
 // The note comparison results will always contain all fields from all notes.
@@ -1,9 +1,11 @@
 package app
 
 import (
+	"github.com/SUSE/saptune/sap/solution"
 	"github.com/SUSE/saptune/system"
 	"os"
 	"path"
+	"reflect"
 	"testing"
 )
 
@@ -209,3 +211,49 @@ func TestAppliedSolution(t *testing.T) {
 		t.Errorf("got: %+v, expected: %+v\n", state, expState)
 	}
 }
+
+func TestUpgradeSolution(t *testing.T) {
+	os.RemoveAll(SampleNoteDataDir)
+	defer os.RemoveAll(SampleNoteDataDir)
+	allSols := map[string]solution.Solution{"sol1": {"1001"}}
+	tuneApp := InitialiseApp(path.Join(SampleNoteDataDir, "conf"), path.Join(SampleNoteDataDir, "data"), AllTestNotes, allSols)
+	if _, err := tuneApp.TuneSolution("sol1"); err != nil {
+		t.Fatal(err)
+	}
+	added, removed, err := tuneApp.SolutionNoteChanges("sol1")
+	if err != nil || len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected no changes, got '%v', '%v', '%v'", added, removed, err)
+	}
+	// the solution definition changes, Note 1001 is replaced by Note 1002
+	allSols["sol1"] = solution.Solution{"1002"}
+	added, removed, err = tuneApp.SolutionNoteChanges("sol1")
+	if err != nil || !reflect.DeepEqual(added, []string{"1002"}) || !reflect.DeepEqual(removed, []string{"1001"}) {
+		t.Errorf("expected added '[1002]' and removed '[1001]', got '%v', '%v', '%v'", added, removed, err)
+	}
+	if tuneApp.KeepNoteOfSolution("1001", "sol1") {
+		t.Error("Note 1001 should not be kept")
+	}
+	reverted, applied, err := tuneApp.UpgradeSolution("sol1")
+	if err != nil || !reflect.DeepEqual(reverted, []string{"1001"}) || !reflect.DeepEqual(applied, []string{"1002"}) {
+		t.Errorf("expected reverted '[1001]' and applied '[1002]', got '%v', '%v', '%v'", reverted, applied, err)
+	}
+	VerifyConfig(t, tuneApp, []string{}, []string{"sol1"})
+	VerifyFileContent(t, SampleParamFile, "optimised2", "upgrade")
+	if !reflect.DeepEqual(tuneApp.NoteApplyOrder, []string{"1002"}) {
+		t.Errorf("expected NoteApplyOrder '[1002]', got '%v'", tuneApp.NoteApplyOrder)
+	}
+	added, removed, err = tuneApp.SolutionNoteChanges("sol1")
+	if err != nil || len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected no changes after upgrade, got '%v', '%v', '%v'", added, removed, err)
+	}
+
+	// a removed Note, which is enabled additionally, is kept
+	tuneApp.TuneForNotes = []string{"1001"}
+	if !tuneApp.KeepNoteOfSolution("1001", "sol1") {
+		t.Error("Note 1001 should be kept")
+	}
+	tuneApp.TuneForNotes = []string{}
+	if err := tuneApp.RevertSolution("sol1"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -192,7 +192,7 @@ func checkForTuned() {
 func callSaptuneCheckScript(arg string) {
 	if arg == "check" {
 		var err error
-		if system.GetFlagVal("format") == "json" {
+		if system.IsStructuredOut() {
 			var cmdOut []byte
 			cmdOut, err = exec.Command(saptcheck, "--json").CombinedOutput()
 			system.Jcollect(cmdOut)
@@ -290,7 +290,7 @@ func checkSaptuneConfigFile(saptuneConf string) string {
 	}
 	missingKey := []string{}
 	keyList := actions.MandKeyList()
-	sconf, err := txtparser.ParseSysconfigFile(saptuneConf, false)
+	sconf, err := actions.ParseSaptuneConfig(saptuneConf, false)
 	if err != nil {
 		system.ErrorExit("Checking saptune configuration file - Unable to read file '%s': %v", saptuneConf, err, 128)
 	}
@@ -306,28 +306,29 @@ func checkSaptuneConfigFile(saptuneConf string) string {
 	}
 	// set internal 'excludeDirs' for later use during parsing Notes
 	txtparser.GetSysctlExcludes(sconf.GetString("SKIP_SYSCTL_FILES", ""))
-	stageVal := sconf.GetString("STAGING", "")
-	if stageVal != "true" && stageVal != "false" {
-		system.ErrorExit("Variable 'STAGING' from file '%s' contains a wrong value '%s'. Needs to be 'true' or 'false'", saptuneConf, stageVal, 128)
-	}
-
-	// check saptune-discovery-period of the Trento Agent
-	if sconf.IsKeyAvail("TrentoASDP") {
-		_ = system.CheckAndSetTrento("TrentoASDP", sconf.GetString("TrentoASDP", ""), false)
-	}
 
-	// set values read from the config file
-	saptuneVers := sconf.GetString("SAPTUNE_VERSION", "")
-	if saptuneVers != "1" && saptuneVers != "2" && saptuneVers != "3" {
-		system.ErrorExit("Wrong saptune version in file '%s': %s", saptuneConf, saptuneVers, 128)
+	// check the values of the variables needed to start saptune and run
+	// the start checks of the variables (e.g. saptune-discovery-period
+	// of the Trento Agent)
+	for _, key := range actions.ConfigKeys() {
+		if !sconf.IsKeyAvail(key.Name) {
+			continue
+		}
+		val := sconf.GetString(key.Name, "")
+		if key.StartCheck {
+			if _, err := key.CheckValue([]string{val}); err != nil {
+				system.ErrorExit("Variable '%s' from file '%s' contains a wrong value '%s'. Needs to be %s", key.Name, saptuneConf, val, key.ValuesText(), 128)
+			}
+		}
+		key.StartHook(val)
 	}
-	return saptuneVers
+	return sconf.GetString("SAPTUNE_VERSION", "")
 }
 
 // logSwitchFromConfig reads log switch settings from the saptune
 // config file
 func logSwitchFromConfig(saptuneConf string, lswitch map[string]string) {
-	sconf, err := txtparser.ParseSysconfigFile(saptuneConf, false)
+	sconf, err := actions.ParseSaptuneConfig(saptuneConf, false)
 	if err != nil {
 		system.ErrorExit("Checking saptune configuration file - Unable to read file '%s': %v", saptuneConf, err, 128)
 	}
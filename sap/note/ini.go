@@ -5,6 +5,7 @@ import (
 	"github.com/SUSE/saptune/sap/param"
 	"github.com/SUSE/saptune/system"
 	"github.com/SUSE/saptune/txtparser"
+	"os"
 	"path"
 	"regexp"
 	"strconv"
@@ -55,6 +56,10 @@ type INISettings struct {
 	ValuesToApply   map[string]string // values to apply
 	OverrideParams  map[string]string // parameter values from the override file
 	Inform          map[string]string // special information for parameter values
+	// Note parameter overrides of the solution definition and of the
+	// solution override file of the enabled solution
+	SolutionDefaults  *txtparser.INIFile `json:"-"`
+	SolutionOverrides *txtparser.INIFile `json:"-"`
 }
 
 // Initialise a BlockDeviceQueue
@@ -93,7 +98,7 @@ func (vend INISettings) Initialise() (Note, error) {
 	}
 
 	// looking for override file
-	override, ow := txtparser.GetOverrides("ovw", vend.ID)
+	override, ow := vend.getOverrides()
 
 	// Read current parameter values
 	vend.SysctlParams = make(map[string]string)
@@ -143,7 +148,8 @@ func (vend INISettings) Initialise() (Note, error) {
 		case INISectionPagecache:
 			// page cache is special, has it's own config file
 			// so adjust path to pagecache config file, if needed
-			if override {
+			// (not for the Note parameter overrides of a solution)
+			if _, err := os.Stat(path.Join(txtparser.OverrideTuningSheets, vend.ID)); override && err == nil {
 				pc.PagingConfig = path.Join(txtparser.OverrideTuningSheets, vend.ID)
 			} else {
 				pc.PagingConfig = vend.ConfFilePath
@@ -476,6 +482,30 @@ func (vend INISettings) handleID1805750(key, val string) (string, string) {
 	return key, val
 }
 
+// getOverrides returns the override information of the Note. The override
+// file and the drop-in override files are merged with the Note parameter
+// overrides of the enabled solution. The result is stored in the section
+// runtime file and read again only, if the Note parameter overrides of the
+// solution have changed
+func (vend INISettings) getOverrides() (bool, *txtparser.INIFile) {
+	txtparser.SolutionOverridesChanged(vend.ID, vend.SolutionDefaults, vend.SolutionOverrides)
+	ow, err := txtparser.GetSectionInfo("ovw", vend.ID, false)
+	if err == nil {
+		return true, ow
+	}
+	ow, err = txtparser.ParseOverrides(txtparser.OverrideTuningSheets, vend.ID)
+	if err != nil {
+		ow = nil
+	}
+	ow = txtparser.MergeSolutionOverrides(ow, vend.SolutionDefaults, vend.SolutionOverrides)
+	if ow == nil {
+		return false, ow
+	}
+	// write section data to section runtime file
+	_ = txtparser.StoreSectionInfo(ow, "ovw", vend.ID, true)
+	return true, ow
+}
+
 // handleInitOverride handles the override parameter settings
 func (vend INISettings) handleInitOverride(key, val, section string, op txtparser.Operator, over *txtparser.INIFile) (string, string, txtparser.Operator) {
 	chkKey := key
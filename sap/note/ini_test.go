@@ -3,6 +3,7 @@ package note
 import (
 	"fmt"
 	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"os"
 	"path"
 	"runtime"
@@ -87,6 +88,34 @@ func TestNoConfig(t *testing.T) {
 	}
 }
 
+func TestSolutionOverrideSettings(t *testing.T) {
+	cleanUp()
+	defer cleanUp()
+	iniPath := path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/testdata/ini_test.ini")
+	ini := INISettings{ConfFilePath: iniPath, ID: "471148"}
+	ini.SolutionDefaults = txtparser.ParseINI("[sysctl]\nvm.swappiness = 20\nvm.dirty_ratio = 15\n")
+	ini.SolutionOverrides = txtparser.ParseINI("[sysctl]\nvm.swappiness = 30\n")
+	initialised, err := ini.Initialise()
+	if err != nil {
+		t.Error(err)
+	}
+	initialisedINI := initialised.(INISettings)
+	if initialisedINI.OverrideParams["vm.swappiness"] != "30" || initialisedINI.OverrideParams["vm.dirty_ratio"] != "15" {
+		t.Errorf("unexpected override values '%+v'", initialisedINI.OverrideParams)
+	}
+	// solution no longer enabled
+	ini.SolutionDefaults = nil
+	ini.SolutionOverrides = nil
+	initialised, err = ini.Initialise()
+	if err != nil {
+		t.Error(err)
+	}
+	initialisedINI = initialised.(INISettings)
+	if len(initialisedINI.OverrideParams) != 0 {
+		t.Errorf("unexpected override values '%+v'", initialisedINI.OverrideParams)
+	}
+}
+
 func TestAllSettings(t *testing.T) {
 	cleanUp()
 	testString := []string{"vm.nr_hugepages", "THP", "KSM", "systemd:sysstat"}
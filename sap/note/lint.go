@@ -0,0 +1,233 @@
+package note
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/sap/param"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NoteSections are the valid sections of a Note definition file
+var NoteSections = []string{INISectionVersion, INISectionSysctl, INISectionSys, INISectionVM, INISectionFS, INISectionCPU, INISectionMEM, INISectionBlock, INISectionService, INISectionLimits, INISectionLogin, INISectionPagecache, INISectionRpm, INISectionGrub, INISectionReminder}
+
+// sectionKeys are the valid parameter names of the sections with a fixed
+// set of parameters
+var sectionKeys = map[string][]string{
+	INISectionVM:        {"THP", "KSM"},
+	INISectionFS:        {"xfs_options"},
+	INISectionCPU:       {"energy_perf_bias", "governor", "force_latency"},
+	INISectionMEM:       {"ShmFileSystemSizeMB", "VSZ_TMPFS_PERCENT"},
+	INISectionLimits:    {"LIMITS"},
+	INISectionLogin:     {"UserTasksMax"},
+	INISectionPagecache: {"ENABLE_PAGECACHE_LIMIT", system.SysctlPagecacheLimitIgnoreDirty, "OVERRIDE_PAGECACHE_LIMIT_MB"},
+}
+
+// sectionValues are the valid values of parameters with a fixed set of
+// values
+var sectionValues = map[string][]string{
+	"THP":                                  {"always", "madvise", "never"},
+	"KSM":                                  {"0", "1", "2"},
+	"energy_perf_bias":                     {"performance", "normal", "powersave"},
+	"ENABLE_PAGECACHE_LIMIT":               {"yes", "no"},
+	system.SysctlPagecacheLimitIgnoreDirty: {"0", "1", "2"},
+}
+
+// numericKeys are the parameters, which need a number as value
+var numericKeys = []string{"ShmFileSystemSizeMB", "VSZ_TMPFS_PERCENT", "OVERRIDE_PAGECACHE_LIMIT_MB"}
+
+// blockKey matches the parameter names of the block section
+var blockKey = regexp.MustCompile(`^(IO_SCHEDULER|NRREQ|READ_AHEAD_KB|MAX_SECTORS_KB)$`)
+
+// availServices caches the services available on the system
+var availServices map[string]string
+
+// LintNoteFile checks a Note definition file in strict mode and returns
+// all problems found with file name and line number.
+// Beside the syntax checks of txtparser.LintINI the parameters are
+// checked against the running system (e.g. sysctl key not available) and
+// the values against the rules of their section (e.g. scheduler not
+// supported by the block devices)
+func LintNoteFile(fileName string) ([]txtparser.LintProblem, error) {
+	res, err := txtparser.LintINIFile(fileName, NoteSections)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range res.Entries {
+		for _, reason := range lintNoteEntry(entry, true) {
+			res.Problems = append(res.Problems, txtparser.LintProblem{File: fileName, Line: entry.Line, Section: entry.Section, Reason: reason})
+		}
+	}
+	return sortLintProblems(res.Problems), nil
+}
+
+// LintOverrideFile checks an override file of a Note against the Note
+// definition file noteFile. Beside the checks of LintNoteFile all sections
+// and parameters of the override file need to be part of the Note, because
+// otherwise the override is silently ignored.
+// The [version] section of an override file is not evaluated, so it is
+// not checked.
+// The parameters are not checked against the running system, as the Note
+// definition is responsible for the parameters (e.g. a sysctl key of the
+// Note not available on this system), so an override file is not rejected
+// because of the host
+func LintOverrideFile(fileName, noteFile string) ([]txtparser.LintProblem, error) {
+	base, err := txtparser.LintINIFile(noteFile, NoteSections)
+	if err != nil {
+		return nil, err
+	}
+	res, err := txtparser.LintINIFile(fileName, NoteSections)
+	if err != nil {
+		return nil, err
+	}
+	baseSections := make(map[string]bool)
+	baseKeys := make(map[string]bool)
+	for _, entry := range base.Entries {
+		baseSections[entry.Section] = true
+		baseKeys[entry.Section+":"+entry.Key] = true
+	}
+	problems := []txtparser.LintProblem{}
+	for _, prob := range res.Problems {
+		if prob.Section != INISectionVersion {
+			problems = append(problems, prob)
+		}
+	}
+	for _, entry := range res.Entries {
+		reasons := []string{}
+		if !baseSections[entry.Section] {
+			reasons = append(reasons, fmt.Sprintf("section [%s] is not part of the Note definition '%s', so the parameter '%s' is ignored", entry.Section, noteFile, entry.Key))
+		} else if !baseKeys[entry.Section+":"+entry.Key] && !baseKeys[entry.Section+":"+entry.Key+".service"] {
+			reasons = append(reasons, fmt.Sprintf("parameter '%s' is not part of section [%s] of the Note definition '%s', so it is ignored", entry.Key, entry.Section, noteFile))
+		} else {
+			reasons = lintNoteEntry(entry, false)
+		}
+		for _, reason := range reasons {
+			problems = append(problems, txtparser.LintProblem{File: fileName, Line: entry.Line, Section: entry.Section, Reason: reason})
+		}
+	}
+	return sortLintProblems(problems), nil
+}
+
+// lintNoteEntry checks a single parameter entry of a Note definition file
+// and returns the reasons, why the entry is not valid.
+// Checks against the running system are only done, if hostChecks is set,
+// and only for entries of sections, which are valid for the running system
+func lintNoteEntry(entry txtparser.LintEntry, hostChecks bool) []string {
+	reasons := []string{}
+	if entry.Section != INISectionSysctl && entry.Section != INISectionSys && entry.Section != INISectionRpm && entry.Operator != txtparser.OperatorEqual {
+		reasons = append(reasons, fmt.Sprintf("operator '%s' is not supported for parameter '%s', section [%s] only supports '='", entry.Operator, entry.Key, entry.Section))
+	}
+	if keys, ok := sectionKeys[entry.Section]; ok && !txtparser.IsInList(entry.Key, keys) {
+		return append(reasons, fmt.Sprintf("unknown parameter '%s', valid parameters are: %s", entry.Key, strings.Join(keys, ", ")))
+	}
+	if entry.Value == "" {
+		// empty value - parameter is untouched
+		return reasons
+	}
+	if vals, ok := sectionValues[entry.Key]; ok && !txtparser.IsInList(strings.ToLower(entry.Value), vals) {
+		reasons = append(reasons, fmt.Sprintf("invalid value '%s' for parameter '%s', valid values are: %s", entry.Value, entry.Key, strings.Join(vals, ", ")))
+	}
+	if txtparser.IsInList(entry.Key, numericKeys) {
+		if _, err := strconv.ParseUint(entry.Value, 10, 64); err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid value '%s' for parameter '%s', a positive number is needed", entry.Value, entry.Key))
+		}
+	}
+	hostChecks = hostChecks && entry.Active
+	switch entry.Section {
+	case INISectionSysctl:
+		if hostChecks {
+			reasons = append(reasons, lintKeyPath("/proc/sys", entry.Key)...)
+		}
+	case INISectionSys:
+		if hostChecks {
+			reasons = append(reasons, lintKeyPath("/sys", strings.TrimPrefix(entry.Key, "sys:"))...)
+		}
+	case INISectionBlock:
+		reasons = append(reasons, lintBlockEntry(entry, hostChecks)...)
+	case INISectionService:
+		reasons = append(reasons, lintServiceEntry(entry, hostChecks)...)
+	case INISectionLimits:
+		for _, limit := range strings.Split(entry.Value, ",") {
+			if len(strings.Fields(limit)) != 4 {
+				reasons = append(reasons, fmt.Sprintf("invalid limit '%s', expected 'domain type item value'", strings.TrimSpace(limit)))
+			}
+		}
+	case INISectionCPU:
+		if entry.Key == "force_latency" && hostChecks && !system.IsValidFL(entry.Value) {
+			reasons = append(reasons, fmt.Sprintf("invalid value '%s' for parameter 'force_latency', neither a latency nor a C state name available on this system", entry.Value))
+		}
+	}
+	return reasons
+}
+
+// lintKeyPath checks, if the sysctl or sys parameter is available on the
+// running system
+func lintKeyPath(basePath, key string) []string {
+	if _, err := os.Stat(path.Join(basePath, strings.Replace(key, ".", "/", -1))); err != nil {
+		return []string{fmt.Sprintf("parameter '%s' does not exist on this system (no such file in '%s')", key, basePath)}
+	}
+	return nil
+}
+
+// lintBlockEntry checks the parameter name and the value of a block device
+// parameter. The scheduler needs to be supported by at least one of the
+// block devices valid for the section (only checked with hostChecks)
+func lintBlockEntry(entry txtparser.LintEntry, hostChecks bool) []string {
+	if !blockKey.MatchString(entry.Key) {
+		return []string{fmt.Sprintf("unknown parameter '%s', valid parameters are: IO_SCHEDULER, NRREQ, READ_AHEAD_KB, MAX_SECTORS_KB", entry.Key)}
+	}
+	if entry.Key != "IO_SCHEDULER" {
+		if _, err := strconv.ParseUint(entry.Value, 10, 64); err != nil {
+			return []string{fmt.Sprintf("invalid value '%s' for parameter '%s', a positive number is needed", entry.Value, entry.Key)}
+		}
+		return nil
+	}
+	if !hostChecks || len(entry.BlockDevs) == 0 {
+		return nil
+	}
+	for _, bdev := range entry.BlockDevs {
+		for _, sched := range strings.Split(entry.Value, ",") {
+			if param.IsValidScheduler(bdev, strings.ToLower(strings.TrimSpace(sched))) {
+				return nil
+			}
+		}
+	}
+	return []string{fmt.Sprintf("none of the schedulers '%s' is supported by the block devices of this system", entry.Value)}
+}
+
+// lintServiceEntry checks, if the service states are valid and, with
+// hostChecks, if the service is available on the running system
+func lintServiceEntry(entry txtparser.LintEntry, hostChecks bool) []string {
+	reasons := []string{}
+	service := strings.TrimPrefix(entry.Key, "systemd:")
+	for _, state := range strings.Split(entry.Value, ",") {
+		sval := strings.ToLower(strings.TrimSpace(state))
+		if sval != "start" && sval != "stop" && sval != "enable" && sval != "disable" {
+			reasons = append(reasons, fmt.Sprintf("invalid service state '%s' for service '%s', valid states are: start, stop, enable, disable", strings.TrimSpace(state), service))
+		}
+	}
+	if !hostChecks {
+		return reasons
+	}
+	if availServices == nil {
+		availServices = system.GetAvailServices()
+	}
+	if _, ok := availServices[service]; !ok {
+		if _, ok := availServices[service+".service"]; !ok {
+			reasons = append(reasons, fmt.Sprintf("service '%s' does not exist on this system", service))
+		}
+	}
+	return reasons
+}
+
+// sortLintProblems sorts the problems by line number. The order of
+// problems of the same line is preserved
+func sortLintProblems(problems []txtparser.LintProblem) []txtparser.LintProblem {
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+	return problems
+}
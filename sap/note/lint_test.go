@@ -0,0 +1,76 @@
+package note
+
+import (
+	"github.com/SUSE/saptune/txtparser"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLintNoteEntry(t *testing.T) {
+	tests := []struct {
+		entry  txtparser.LintEntry
+		reason string
+	}{
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionVM, Key: "THP", Operator: txtparser.OperatorEqual, Value: "never"}}, ""},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionVM, Key: "THP", Operator: txtparser.OperatorEqual, Value: "sometimes"}}, "invalid value 'sometimes' for parameter 'THP'"},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionVM, Key: "HUGE", Operator: txtparser.OperatorEqual, Value: "never"}}, "unknown parameter 'HUGE'"},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionVM, Key: "KSM", Operator: txtparser.OperatorLessThan, Value: "1"}}, "operator '<' is not supported"},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionMEM, Key: "VSZ_TMPFS_PERCENT", Operator: txtparser.OperatorEqual, Value: "abc"}}, "a positive number is needed"},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionMEM, Key: "VSZ_TMPFS_PERCENT", Operator: txtparser.OperatorEqual, Value: ""}}, ""},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionBlock, Key: "NRREQ", Operator: txtparser.OperatorEqual, Value: "-1"}}, "a positive number is needed"},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionBlock, Key: "SCHED", Operator: txtparser.OperatorEqual, Value: "noop"}}, "unknown parameter 'SCHED'"},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionService, Key: "systemd:uuidd.socket", Operator: txtparser.OperatorEqual, Value: "start,run"}}, "invalid service state 'run'"},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionLimits, Key: "LIMITS", Operator: txtparser.OperatorEqual, Value: "@sapsys soft nofile"}}, "invalid limit '@sapsys soft nofile'"},
+		{txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionSysctl, Key: "vm.not_available", Operator: txtparser.OperatorEqual, Value: "1"}}, ""},
+	}
+	for _, tst := range tests {
+		reasons := strings.Join(lintNoteEntry(tst.entry, true), "\n")
+		if tst.reason == "" && reasons != "" {
+			t.Errorf("entry '%+v': expected no problems, got '%s'", tst.entry, reasons)
+		}
+		if tst.reason != "" && !strings.Contains(reasons, tst.reason) {
+			t.Errorf("entry '%+v': expected '%s', got '%s'", tst.entry, tst.reason, reasons)
+		}
+	}
+	// checks against the system only for active sections
+	entry := txtparser.LintEntry{INIEntry: txtparser.INIEntry{Section: INISectionSysctl, Key: "vm.not_available", Operator: txtparser.OperatorEqual, Value: "1"}, Active: true}
+	if reasons := strings.Join(lintNoteEntry(entry, true), "\n"); !strings.Contains(reasons, "does not exist on this system") {
+		t.Errorf("expected 'does not exist on this system', got '%s'", reasons)
+	}
+	// no checks against the system for override files
+	if reasons := lintNoteEntry(entry, false); len(reasons) != 0 {
+		t.Errorf("expected no problems without host checks, got '%v'", reasons)
+	}
+}
+
+func TestLintOverrideFile(t *testing.T) {
+	noteFile := "/tmp/saptune_lint_note"
+	ovFile := "/tmp/saptune_lint_override"
+	defer os.Remove(noteFile)
+	defer os.Remove(ovFile)
+	noteCont := "[version]\nVERSION=1\nDATE=01.01.2024\nDESCRIPTION=lint\nREFERENCES=https://me.sap.com\n[vm]\nTHP=never\nKSM=0\n"
+	if err := os.WriteFile(noteFile, []byte(noteCont), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ovCont := "[vm]\nTHP=madvise\nKSM=3\n[mem]\nVSZ_TMPFS_PERCENT=60\n"
+	if err := os.WriteFile(ovFile, []byte(ovCont), 0644); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := LintOverrideFile(ovFile, noteFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got: %v", problems)
+	}
+	if problems[0].Line != 3 || !strings.Contains(problems[0].Reason, "invalid value '3' for parameter 'KSM'") {
+		t.Errorf("unexpected problem '%v'", problems[0])
+	}
+	if problems[1].Line != 5 || !strings.Contains(problems[1].Reason, "section [mem] is not part of the Note definition") {
+		t.Errorf("unexpected problem '%v'", problems[1])
+	}
+	if _, err := LintOverrideFile(ovFile, "/file_does_not_exist"); err == nil {
+		t.Error("expected an error for a not existing Note definition file")
+	}
+}
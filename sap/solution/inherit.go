@@ -0,0 +1,192 @@
+package solution
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
+	"os"
+	"path"
+	"strings"
+)
+
+// keywords of the architecture sections to derive a Solution from a
+// parent Solution
+const (
+	SolParent = "PARENT"
+	SolAdd    = "ADD"
+	SolRemove = "REMOVE"
+)
+
+// solInherit collects the inheritance settings of an architecture section
+type solInherit struct {
+	parent string
+	add    []string
+	remove []string
+	plain  bool
+}
+
+// SolDirective splits a line of an architecture section into the
+// inheritance keyword (PARENT, ADD, REMOVE) and its values.
+// The last return value is false, if the line is a plain Note list
+func SolDirective(line string) (string, []string, bool) {
+	kv := strings.SplitN(strings.Join(strings.Fields(line), " "), "=", 2)
+	if len(kv) != 2 {
+		return "", nil, false
+	}
+	return strings.TrimSpace(kv[0]), strings.Fields(kv[1]), true
+}
+
+// resolveSolutionInheritance replaces the inheritance lines (PARENT, ADD,
+// REMOVE) of the architecture sections of a Solution definition by the
+// resulting Note list. The Note list of the parent Solution is read from
+// the directory of the Solution or from the working area, so a derived
+// Solution follows the changes of the parent automatically.
+// chain contains the files of the Solution and its descendants and is used
+// to detect inheritance loops.
+// Sections without inheritance lines are returned unchanged
+func resolveSolutionInheritance(entries []txtparser.INIEntry, solName, solsDir string, chain []string) ([]txtparser.INIEntry, error) {
+	sections := make(map[string]*solInherit)
+	for _, entry := range entries {
+		if entry.Section != "ArchX86" && entry.Section != "ArchPPC64LE" {
+			continue
+		}
+		if sections[entry.Section] == nil {
+			sections[entry.Section] = &solInherit{}
+		}
+		inh := sections[entry.Section]
+		key, vals, ok := SolDirective(entry.Value)
+		if !ok {
+			inh.plain = true
+			continue
+		}
+		switch key {
+		case SolParent:
+			if len(vals) != 1 {
+				return nil, fmt.Errorf("section [%s]: '%s' needs exactly one parent Solution", entry.Section, SolParent)
+			}
+			if inh.parent != "" {
+				return nil, fmt.Errorf("section [%s]: '%s' is defined more than once", entry.Section, SolParent)
+			}
+			inh.parent = vals[0]
+		case SolAdd:
+			inh.add = append(inh.add, vals...)
+		case SolRemove:
+			inh.remove = append(inh.remove, vals...)
+		default:
+			return nil, fmt.Errorf("section [%s]: unknown keyword '%s', supported are '%s', '%s' and '%s'", entry.Section, key, SolParent, SolAdd, SolRemove)
+		}
+	}
+
+	resolved := make([]txtparser.INIEntry, 0, len(entries))
+	done := make(map[string]bool)
+	for _, entry := range entries {
+		inh := sections[entry.Section]
+		if inh == nil || (inh.parent == "" && len(inh.add) == 0 && len(inh.remove) == 0) {
+			resolved = append(resolved, entry)
+			continue
+		}
+		if done[entry.Section] {
+			continue
+		}
+		done[entry.Section] = true
+		if inh.parent == "" {
+			return nil, fmt.Errorf("section [%s]: '%s' and '%s' need a '%s' Solution", entry.Section, SolAdd, SolRemove, SolParent)
+		}
+		if inh.plain {
+			return nil, fmt.Errorf("section [%s]: a Note list can not be combined with '%s'", entry.Section, SolParent)
+		}
+		notes, err := parentSolutionNotes(inh.parent, entry.Section, solName, solsDir, chain)
+		if err != nil {
+			return nil, err
+		}
+		notes = applySolutionChanges(notes, inh, entry.Section)
+		if len(notes) == 0 {
+			return nil, fmt.Errorf("section [%s]: the resulting Note list is empty", entry.Section)
+		}
+		entry.Value = strings.Join(notes, "\t")
+		resolved = append(resolved, entry)
+	}
+	return resolved, nil
+}
+
+// parentSolutionNotes returns the Note list of the architecture section of
+// the parent Solution. The parent is searched in the directory of the
+// derived Solution (except for a Solution, which derives from the Solution
+// with the same name - an override) and in the working area
+func parentSolutionNotes(parent, section, solName, solsDir string, chain []string) ([]string, error) {
+	dirs := []string{}
+	if parent != solName || solsDir == SolutionSheets {
+		dirs = append(dirs, solsDir)
+	}
+	if solsDir != SolutionSheets {
+		dirs = append(dirs, SolutionSheets)
+	}
+	fileName := ""
+	parentDir := ""
+	for _, dir := range dirs {
+		if _, err := os.Stat(fmt.Sprintf("%s%s.sol", dir, parent)); err == nil {
+			fileName = fmt.Sprintf("%s%s.sol", dir, parent)
+			parentDir = dir
+			break
+		}
+	}
+	if fileName == "" {
+		return nil, fmt.Errorf("section [%s]: parent Solution '%s' not found in '%s'", section, parent, strings.Join(dirs, "' or '"))
+	}
+	for _, visited := range chain {
+		if path.Clean(visited) == path.Clean(fileName) {
+			return nil, fmt.Errorf("section [%s]: inheritance loop detected, '%s' is its own ancestor", section, fileName)
+		}
+	}
+	content, err := parseSolutionFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("section [%s]: failed to read parent Solution '%s' - %v", section, fileName, err)
+	}
+	entries, err := resolveSolutionInheritance(content.AllValues, parent, parentDir, append(chain, fileName))
+	if err != nil {
+		return nil, fmt.Errorf("parent Solution '%s': %v", fileName, err)
+	}
+	noteList := ""
+	found := false
+	for _, entry := range entries {
+		if entry.Section == section {
+			// only the last Note list of a section is used
+			noteList = entry.Value
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("section [%s]: parent Solution '%s' has no section [%s]", section, parent, section)
+	}
+	return strings.Fields(noteList), nil
+}
+
+// applySolutionChanges removes the Notes of REMOVE from the Note list of the
+// parent Solution and appends the Notes of ADD, which are not already part
+// of the list
+func applySolutionChanges(notes []string, inh *solInherit, section string) []string {
+	drop := make(map[string]bool)
+	for _, noteID := range inh.remove {
+		drop[noteID] = true
+	}
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, noteID := range notes {
+		seen[noteID] = true
+		if !drop[noteID] {
+			result = append(result, noteID)
+		}
+	}
+	for _, noteID := range inh.remove {
+		if !seen[noteID] {
+			system.WarningLog("Note '%s' to remove is not part of the Note list of section [%s] of the parent Solution '%s'", noteID, section, inh.parent)
+		}
+	}
+	for _, noteID := range inh.add {
+		if !seen[noteID] {
+			seen[noteID] = true
+			result = append(result, noteID)
+		}
+	}
+	return result
+}
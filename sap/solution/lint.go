@@ -0,0 +1,282 @@
+package solution
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/sap/note"
+	"github.com/SUSE/saptune/txtparser"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// SolutionSections are the valid sections of a Solution definition file
+var SolutionSections = []string{"version", "ArchX86", "ArchPPC64LE", "reminder"}
+
+// LintSolutionFile checks a Solution definition file in strict mode and
+// returns all problems found with file name and line number.
+// Beside the syntax checks of txtparser.LintINI it checks, if all Notes
+// of the Solution are available in the working area (noteFiles) or as
+// custom Note in extraFiles and if a Note or an architecture section is
+// defined more than once or if a section without tags hides a previous
+// section with tags (e.g. os=15-*) of the same architecture.
+// The Notes of sections, which do not match the running system, are not
+// checked. For a Solution derived from a parent Solution the Notes to add
+// are checked and the inheritance is resolved to find missing parents or loops.
+// Note parameter overrides need to refer to a Note of the Solution and to a
+// parameter of this Note
+func LintSolutionFile(fileName, noteFiles, extraFiles string) ([]txtparser.LintProblem, error) {
+	return lintSolution(fileName, noteFiles, extraFiles, nil)
+}
+
+// lintSolution checks a Solution definition or override file. baseNotes
+// are the Notes of the Solution definition, if an override file is checked
+func lintSolution(fileName, noteFiles, extraFiles string, baseNotes map[string]bool) ([]txtparser.LintProblem, error) {
+	res, err := txtparser.LintINIFile(fileName, solutionLintSections())
+	if err != nil {
+		return nil, err
+	}
+	archLine := make(map[string]int)
+	// first line of the last section with tags per architecture
+	taggedLine := make(map[string]txtparser.LintEntry)
+	lastHeader := make(map[string]string)
+	inheritLine := 0
+	for _, entry := range res.Entries {
+		if entry.Section != "ArchX86" && entry.Section != "ArchPPC64LE" {
+			continue
+		}
+		if entry.Header != lastHeader[entry.Section] {
+			lastHeader[entry.Section] = entry.Header
+			if strings.Contains(entry.Header, ":") {
+				taggedLine[entry.Section] = entry
+			} else if tagged, ok := taggedLine[entry.Section]; ok {
+				res.Problems = append(res.Problems, txtparser.LintProblem{File: fileName, Line: entry.Line, Section: entry.Section, Reason: fmt.Sprintf("section replaces the Note list of section [%s] in line %d, so the section with tags is never used. Please place the section without tags first", tagged.Header, tagged.Line)})
+			}
+		}
+		if key, vals, ok := SolDirective(entry.Value); ok {
+			if inheritLine == 0 {
+				inheritLine = entry.Line
+			}
+			if key == SolAdd && entry.Active {
+				for _, reason := range lintSolutionNotes(strings.Join(vals, "\t"), noteFiles, extraFiles) {
+					res.Problems = append(res.Problems, txtparser.LintProblem{File: fileName, Line: entry.Line, Section: entry.Section, Reason: reason})
+				}
+			}
+			continue
+		}
+		if first, ok := archLine[entry.Header]; ok {
+			res.Problems = append(res.Problems, txtparser.LintProblem{File: fileName, Line: entry.Line, Section: entry.Section, Reason: fmt.Sprintf("section already contains a Note list in line %d, only the last one is used", first)})
+		} else {
+			archLine[entry.Header] = entry.Line
+		}
+		if !entry.Active {
+			// the Notes of other os releases are not available
+			// on this system
+			continue
+		}
+		for _, reason := range lintSolutionNotes(entry.Value, noteFiles, extraFiles) {
+			res.Problems = append(res.Problems, txtparser.LintProblem{File: fileName, Line: entry.Line, Section: entry.Section, Reason: reason})
+		}
+	}
+	if inheritLine != 0 {
+		if reason := lintSolutionInheritance(fileName); reason != "" {
+			res.Problems = append(res.Problems, txtparser.LintProblem{File: fileName, Line: inheritLine, Reason: reason})
+		}
+	}
+	solNotes := solutionFileNotes(fileName, res)
+	for noteID := range baseNotes {
+		solNotes[noteID] = true
+	}
+	res.Problems = append(res.Problems, lintNoteOverrides(fileName, res, solNotes, noteFiles, extraFiles)...)
+	sort.SliceStable(res.Problems, func(i, j int) bool { return res.Problems[i].Line < res.Problems[j].Line })
+	return res.Problems, nil
+}
+
+// LintSolutionOverrideFile checks an override file of a Solution against
+// the Solution definition file solFile. Beside the checks of
+// LintSolutionFile all architecture sections of the override file need to
+// be part of the Solution, because otherwise the override is silently
+// ignored.
+// The [version] section of an override file is not evaluated, so it is
+// not checked
+func LintSolutionOverrideFile(fileName, solFile, noteFiles, extraFiles string) ([]txtparser.LintProblem, error) {
+	base, err := txtparser.LintINIFile(solFile, solutionLintSections())
+	if err != nil {
+		return nil, err
+	}
+	lint, err := lintSolution(fileName, noteFiles, extraFiles, solutionFileNotes(solFile, base))
+	if err != nil {
+		return nil, err
+	}
+	problems := []txtparser.LintProblem{}
+	for _, prob := range lint {
+		if prob.Section != "version" {
+			problems = append(problems, prob)
+		}
+	}
+	baseSections := make(map[string]bool)
+	for _, entry := range base.Entries {
+		baseSections[entry.Section] = true
+	}
+	res, _ := txtparser.LintINIFile(fileName, solutionLintSections())
+	for _, entry := range res.Entries {
+		if (entry.Section == "ArchX86" || entry.Section == "ArchPPC64LE") && !baseSections[entry.Section] {
+			problems = append(problems, txtparser.LintProblem{File: fileName, Line: entry.Line, Section: entry.Section, Reason: fmt.Sprintf("section [%s] is not part of the Solution definition '%s', so the Note list is ignored", entry.Section, solFile)})
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+	return problems, nil
+}
+
+// lintSolutionNotes checks the Note list of an architecture section
+func lintSolutionNotes(noteList, noteFiles, extraFiles string) []string {
+	reasons := []string{}
+	found := make(map[string]bool)
+	for _, noteID := range strings.Split(noteList, "\t") {
+		if found[noteID] {
+			reasons = append(reasons, fmt.Sprintf("Note '%s' is listed more than once", noteID))
+			continue
+		}
+		found[noteID] = true
+		if _, err := os.Stat(fmt.Sprintf("%s%s", noteFiles, noteID)); err == nil {
+			continue
+		}
+		if _, err := os.Stat(fmt.Sprintf("%s%s.conf", extraFiles, noteID)); err == nil {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("definition of Note '%s' not found in '%s' or '%s'", noteID, noteFiles, extraFiles))
+	}
+	return reasons
+}
+
+// lintSolutionInheritance resolves the parent Solutions of a Solution
+// definition file and returns the reason, if this fails
+func lintSolutionInheritance(fileName string) string {
+	content, err := parseSolutionFile(fileName)
+	if err != nil {
+		return fmt.Sprintf("failed to read the Solution definition - %v", err)
+	}
+	solName := strings.TrimSuffix(path.Base(fileName), ".sol")
+	if _, err := resolveSolutionInheritance(content.AllValues, solName, path.Dir(fileName)+"/", []string{fileName}); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// solutionLintSections returns the valid sections of a Solution definition
+// file including the sections of the Note parameter overrides
+func solutionLintSections() []string {
+	sections := append([]string{}, SolutionSections...)
+	for _, sect := range note.NoteSections {
+		if sect != note.INISectionVersion && sect != note.INISectionReminder {
+			sections = append(sections, sect)
+		}
+	}
+	return sections
+}
+
+// solutionFileNotes returns the Notes of all architecture sections of a
+// Solution definition file regardless of the section tags
+func solutionFileNotes(fileName string, res *txtparser.LintResult) map[string]bool {
+	notes := make(map[string]bool)
+	inherit := false
+	for _, entry := range res.Entries {
+		if entry.Section != "ArchX86" && entry.Section != "ArchPPC64LE" {
+			continue
+		}
+		key, vals, ok := SolDirective(entry.Value)
+		if !ok {
+			vals = strings.Fields(entry.Value)
+		} else if key != SolAdd {
+			inherit = inherit || key == SolParent
+			continue
+		}
+		for _, noteID := range vals {
+			notes[noteID] = true
+		}
+	}
+	if inherit {
+		if content, err := parseSolutionFile(fileName); err == nil {
+			solName := strings.TrimSuffix(path.Base(fileName), ".sol")
+			if entries, err := resolveSolutionInheritance(content.AllValues, solName, path.Dir(fileName)+"/", []string{fileName}); err == nil {
+				for _, entry := range entries {
+					for _, noteID := range strings.Fields(entry.Value) {
+						notes[noteID] = true
+					}
+				}
+			}
+		}
+	}
+	return notes
+}
+
+// lintNoteOverrides checks the Note parameter overrides ([section:note=NoteID])
+// of a Solution definition file. The Note needs to be part of the Solution
+// and the parameter needs to be part of the Note definition, because
+// otherwise the override is silently ignored
+func lintNoteOverrides(fileName string, res *txtparser.LintResult, solNotes map[string]bool, noteFiles, extraFiles string) []txtparser.LintProblem {
+	problems := []txtparser.LintProblem{}
+	noteKeys := make(map[string]map[string]bool)
+	for _, entry := range res.Entries {
+		if lintIsSolutionSection(entry.Section) {
+			continue
+		}
+		reason := ""
+		noteID := noteTag(entry.Header)
+		if noteID == "" {
+			reason = fmt.Sprintf("section [%s] needs the tag 'note=<NoteID>' to define the Note, whose parameter is overridden", entry.Header)
+		} else if !solNotes[noteID] {
+			reason = fmt.Sprintf("Note '%s' is not part of the Solution, so the override of parameter '%s' is ignored", noteID, entry.Key)
+		} else {
+			if _, ok := noteKeys[noteID]; !ok {
+				noteKeys[noteID] = noteDefinitionKeys(noteID, noteFiles, extraFiles)
+			}
+			if keys := noteKeys[noteID]; keys != nil && !keys[entry.Section+":"+entry.Key] && !keys[entry.Section+":"+entry.Key+".service"] {
+				reason = fmt.Sprintf("parameter '%s' is not part of section [%s] of Note '%s', so it is ignored", entry.Key, entry.Section, noteID)
+			}
+		}
+		if reason != "" {
+			problems = append(problems, txtparser.LintProblem{File: fileName, Line: entry.Line, Section: entry.Section, Reason: reason})
+		}
+	}
+	return problems
+}
+
+// lintIsSolutionSection returns true for the sections of the Solution
+// definition itself
+func lintIsSolutionSection(section string) bool {
+	for _, sect := range SolutionSections {
+		if sect == section {
+			return true
+		}
+	}
+	return false
+}
+
+// noteTag returns the NoteID of the 'note=' tag of a section header
+func noteTag(header string) string {
+	for _, field := range strings.Split(header, ":")[1:] {
+		if strings.HasPrefix(field, "note=") {
+			return strings.TrimPrefix(field, "note=")
+		}
+	}
+	return ""
+}
+
+// noteDefinitionKeys returns the parameters ('section:key') of a Note
+// definition file or nil, if the Note definition is not available
+func noteDefinitionKeys(noteID, noteFiles, extraFiles string) map[string]bool {
+	fileName := fmt.Sprintf("%s%s", noteFiles, noteID)
+	if _, err := os.Stat(fileName); err != nil {
+		fileName = fmt.Sprintf("%s%s.conf", extraFiles, noteID)
+	}
+	res, err := txtparser.LintINIFile(fileName, note.NoteSections)
+	if err != nil {
+		return nil
+	}
+	keys := make(map[string]bool)
+	for _, entry := range res.Entries {
+		keys[entry.Section+":"+entry.Key] = true
+	}
+	return keys
+}
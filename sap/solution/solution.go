@@ -232,7 +232,8 @@ func GetSortedSolutionNames(archName string) (ret []string) {
 }
 
 // getAllSolsFromDir retrieves all defined solutions from the solution files found in the given
-// directory
+// directory. Solutions derived from a parent solution are resolved to the
+// resulting note list
 func getAllSolsFromDir(solsDir, noteFiles, extraFiles string) []txtparser.INIEntry {
 	extra := false
 	if solsDir == ExtraTuningSheets {
@@ -260,14 +261,19 @@ func getAllSolsFromDir(solsDir, noteFiles, extraFiles string) []txtparser.INIEnt
 			continue
 		}
 		fileName := fmt.Sprintf("%s%s", solsDir, fName)
-		content, err := txtparser.ParseINIFile(fileName, false)
+		content, err := parseSolutionFile(fileName)
 		if err != nil {
 			system.ErrorLog("Failed to read solution definition from file '%s'\n", fileName)
 			continue
 		}
+		allValues, err := resolveSolutionInheritance(content.AllValues, solName, solsDir, []string{fileName})
+		if err != nil {
+			system.ErrorLog("Failed to resolve the parent Solution of the solution definition from file '%s' - %v\n", fileName, err)
+			continue
+		}
 
 		notesOK := true
-		for _, param := range content.AllValues {
+		for _, param := range allValues {
 			param.Key = solName
 			if noteFiles != "" {
 				// check, if all note files used in the override or custom
@@ -291,6 +297,73 @@ func getAllSolsFromDir(solsDir, noteFiles, extraFiles string) []txtparser.INIEnt
 	return solAllVals
 }
 
+// parseSolutionFile reads a solution definition file. The last valid
+// architecture section of the file wins, so a section with tags (e.g.
+// os=15-*), which matches the running system, replaces the section of the
+// same architecture without tags before
+func parseSolutionFile(fileName string) (*txtparser.INIFile, error) {
+	content, err := txtparser.ParseINIFile(fileName, false)
+	if err != nil {
+		return content, err
+	}
+	cont, err := os.ReadFile(fileName)
+	if err != nil {
+		return content, err
+	}
+	// parse each architecture section on its own to find the last one,
+	// whose tags match the running system
+	archEntries := make(map[string][]txtparser.INIEntry)
+	for _, sect := range splitSections(string(cont)) {
+		arch := strings.Split(strings.Trim(sect[0], "[]"), ":")[0]
+		if arch != "ArchX86" && arch != "ArchPPC64LE" {
+			continue
+		}
+		sectINI := txtparser.ParseINI(strings.Join(sect, "\n"))
+		if _, ok := sectINI.KeyValue[arch]; !ok {
+			// tags do not match the running system
+			continue
+		}
+		archEntries[arch] = []txtparser.INIEntry{}
+		for _, entry := range sectINI.AllValues {
+			if entry.Section == arch {
+				archEntries[arch] = append(archEntries[arch], entry)
+			}
+		}
+	}
+	allValues := make([]txtparser.INIEntry, 0, len(content.AllValues))
+	done := make(map[string]bool)
+	for _, entry := range content.AllValues {
+		entries, ok := archEntries[entry.Section]
+		if !ok {
+			allValues = append(allValues, entry)
+			continue
+		}
+		if !done[entry.Section] {
+			allValues = append(allValues, entries...)
+			done[entry.Section] = true
+		}
+	}
+	content.AllValues = allValues
+	return content, nil
+}
+
+// splitSections splits the content of a definition file into its sections.
+// The first line of each section is the section header
+func splitSections(content string) [][]string {
+	sects := [][]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sects = append(sects, []string{line})
+			continue
+		}
+		if len(sects) > 0 {
+			sects[len(sects)-1] = append(sects[len(sects)-1], line)
+		}
+	}
+	return sects
+}
+
 // IsAvailableSolution returns true, if the solution name already exists
 func IsAvailableSolution(sol, arch string) bool {
 	found := false
@@ -313,6 +386,35 @@ func IsShippedSolution(sol string) bool {
 	return false
 }
 
+// GetSolutionNoteOverrides returns the Note parameter overrides
+// ([section:note=NoteID]) of the solution definition file and of the
+// solution override file by NoteID
+func GetSolutionNoteOverrides(solName string) (map[string]*txtparser.INIFile, map[string]*txtparser.INIFile) {
+	defaults := make(map[string]*txtparser.INIFile)
+	overrides := make(map[string]*txtparser.INIFile)
+	for _, solsDir := range []string{SolutionSheets, ExtraTuningSheets} {
+		fileName := fmt.Sprintf("%s%s.sol", solsDir, solName)
+		if _, err := os.Stat(fileName); err != nil {
+			continue
+		}
+		if ow, err := txtparser.ParseSolutionNoteOverrides(fileName); err == nil {
+			defaults = ow
+		} else {
+			system.ErrorLog("Failed to read the Note parameter overrides from file '%s' - %v", fileName, err)
+		}
+		break
+	}
+	fileName := fmt.Sprintf("%s%s.sol", OverrideSolutionSheets, solName)
+	if _, err := os.Stat(fileName); err == nil {
+		if ow, err := txtparser.ParseSolutionNoteOverrides(fileName); err == nil {
+			overrides = ow
+		} else {
+			system.ErrorLog("Failed to read the Note parameter overrides from file '%s' - %v", fileName, err)
+		}
+	}
+	return defaults, overrides
+}
+
 // Refresh refreshes the solution related variables
 func Refresh() {
 	CustomSolutions = GetOtherSolution(ExtraTuningSheets, NoteTuningSheets, ExtraTuningSheets)
@@ -2,6 +2,7 @@ package solution
 
 import (
 	"github.com/SUSE/saptune/system"
+	"github.com/SUSE/saptune/txtparser"
 	"os"
 	"path"
 	"reflect"
@@ -203,3 +204,195 @@ func TestRefresh(t *testing.T) {
 		t.Errorf("got: %+v, expected: %+v\n", AllSolutions, allSols)
 	}
 }
+
+func TestSolutionInheritance(t *testing.T) {
+	solsDir, err := os.MkdirTemp("", "saptune_inherit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(solsDir)
+	solsDir = solsDir + "/"
+	writeSol := func(name, content string) {
+		content = "[version]\nVERSION=1\nDATE=19.10.2026\nDESCRIPTION=" + name + "\nREFERENCES=\n" + content
+		if err := os.WriteFile(path.Join(solsDir, name+".sol"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSol("BASE", "[ArchX86]\nN1 N2 N3\n[ArchPPC64LE]\nN1 N2\n")
+	writeSol("CHILD", "[ArchX86]\nPARENT=BASE\nADD=N4 N5\nREMOVE=N2\n[ArchPPC64LE]\nPARENT = BASE\n")
+	writeSol("GRANDCHILD", "[ArchX86]\nPARENT=CHILD\nADD=N6 N1\n")
+	writeSol("LOOP1", "[ArchX86]\nPARENT=LOOP2\n")
+	writeSol("LOOP2", "[ArchX86]\nPARENT=LOOP1\n")
+	writeSol("MISSING", "[ArchX86]\nPARENT=NOSUCHSOL\n")
+	writeSol("NOPARENT", "[ArchX86]\nADD=N4\n")
+	writeSol("MIXED", "[ArchX86]\nPARENT=BASE\nN7 N8\n")
+	writeSol("NOSECT", "[ArchPPC64LE]\nPARENT=GRANDCHILD\n")
+
+	vals := getAllSolsFromDir(solsDir, "", "")
+	got := make(map[string]string)
+	for _, param := range vals {
+		if param.Section == "version" {
+			continue
+		}
+		got[param.Key+" "+param.Section] = strings.Replace(param.Value, "\t", " ", -1)
+	}
+	exp := map[string]string{
+		"BASE ArchX86":       "N1 N2 N3",
+		"BASE ArchPPC64LE":   "N1 N2",
+		"CHILD ArchX86":      "N1 N3 N4 N5",
+		"CHILD ArchPPC64LE":  "N1 N2",
+		"GRANDCHILD ArchX86": "N1 N3 N4 N5 N6",
+	}
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("got: %+v, expected: %+v\n", got, exp)
+	}
+
+	// a change of the parent is inherited
+	writeSol("BASE", "[ArchX86]\nN1 N2 N3 N9\n[ArchPPC64LE]\nN1 N2\n")
+	for _, param := range getAllSolsFromDir(solsDir, "", "") {
+		if param.Key == "GRANDCHILD" && param.Section == "ArchX86" && strings.Replace(param.Value, "\t", " ", -1) != "N1 N3 N9 N4 N5 N6" {
+			t.Errorf("got: '%s', expected: 'N1 N3 N9 N4 N5 N6'\n", param.Value)
+		}
+	}
+
+	// lint reports the inheritance problems
+	for _, noteID := range []string{"N1", "N2", "N3", "N4", "N5", "N6", "N7", "N8", "N9"} {
+		if err := os.WriteFile(path.Join(solsDir, noteID), []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, sol := range []string{"LOOP1", "MISSING", "NOPARENT", "MIXED"} {
+		problems, err := LintSolutionFile(path.Join(solsDir, sol+".sol"), solsDir, "")
+		if err != nil {
+			t.Error(err)
+		}
+		if len(problems) != 1 || problems[0].Line != 7 {
+			t.Errorf("%s: unexpected lint result '%+v'\n", sol, problems)
+		}
+	}
+	problems, err := LintSolutionFile(path.Join(solsDir, "CHILD.sol"), solsDir, "")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("unexpected lint result '%+v' - %v\n", problems, err)
+	}
+	writeSol("BADADD", "[ArchX86]\nPARENT=BASE\nADD=N10\n")
+	problems, err = LintSolutionFile(path.Join(solsDir, "BADADD.sol"), solsDir, "")
+	if err != nil || len(problems) != 1 || problems[0].Line != 8 {
+		t.Errorf("unexpected lint result '%+v' - %v\n", problems, err)
+	}
+}
+
+func TestLintSolutionOsTags(t *testing.T) {
+	osRelease, _ := os.ReadFile("/etc/os-release")
+	defer func() { _ = os.WriteFile("/etc/os-release", osRelease, 0644) }()
+	_ = system.CopyFile(path.Join(TstFilesInGOPATH, "osr15"), "/etc/os-release")
+
+	solsDir, err := os.MkdirTemp("", "saptune_ostags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(solsDir)
+	solsDir = solsDir + "/"
+	for _, noteID := range []string{"N1", "N2", "N3"} {
+		if err := os.WriteFile(path.Join(solsDir, noteID), []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	version := "[version]\nVERSION=1\nDATE=19.10.2026\nDESCRIPTION=os tags\nREFERENCES=\n"
+	// Note N16 is only available on SLE16
+	good := path.Join(solsDir, "GOOD.sol")
+	if err := os.WriteFile(good, []byte(version+"[ArchX86]\nN1 N2\n[ArchX86:os=15-*]\nN1 N3\n[ArchX86:os=16]\nN1 N16\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := LintSolutionFile(good, solsDir, "")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("unexpected lint result '%+v' - %v\n", problems, err)
+	}
+	hidden := path.Join(solsDir, "HIDDEN.sol")
+	if err := os.WriteFile(hidden, []byte(version+"[ArchX86:os=15-*]\nN1 N3\n[ArchX86]\nN1 N2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	problems, err = LintSolutionFile(hidden, solsDir, "")
+	if err != nil || len(problems) != 1 || problems[0].Line != 9 {
+		t.Errorf("unexpected lint result '%+v' - %v\n", problems, err)
+	}
+
+	solutions := GetOtherSolution(solsDir, solsDir, "")
+	if strings.Join(solutions[ArchX86]["GOOD"], " ") != "N1 N3" {
+		t.Errorf("unexpected solution '%+v'\n", solutions[ArchX86]["GOOD"])
+	}
+}
+
+func TestLintSolutionNoteOverrides(t *testing.T) {
+	solsDir, err := os.MkdirTemp("", "saptune_noteov")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(solsDir)
+	solsDir = solsDir + "/"
+	if err := os.WriteFile(path.Join(solsDir, "N1"), []byte("[version]\nVERSION=1\nDATE=19.10.2026\nDESCRIPTION=N1\nREFERENCES=\n[sysctl]\nvm.swappiness = 10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	version := "[version]\nVERSION=1\nDATE=19.10.2026\nDESCRIPTION=note overrides\nREFERENCES=\n"
+	solFile := path.Join(solsDir, "OVSOL.sol")
+	content := version + "[ArchX86]\nN1\n[sysctl:note=N1]\nvm.swappiness = 20\nvm.dirty_bytes = 10\n[sysctl:note=N2]\nvm.swappiness = 30\n[sysctl]\nvm.swappiness = 40\n"
+	if err := os.WriteFile(solFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := LintSolutionFile(solFile, solsDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// parameter not part of the Note, Note not part of the solution and
+	// section without note tag
+	lines := []int{}
+	for _, prob := range problems {
+		lines = append(lines, prob.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{10, 12, 14}) {
+		t.Errorf("unexpected lint result '%+v'\n", problems)
+	}
+
+	// override file without architecture section
+	ovFile := path.Join(solsDir, "override.sol")
+	if err := os.WriteFile(ovFile, []byte("[sysctl:note=N1]\nvm.swappiness = 20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	problems, err = LintSolutionOverrideFile(ovFile, solFile, solsDir, "")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("unexpected lint result '%+v' - %v\n", problems, err)
+	}
+}
+
+func TestParseSolutionFileOsTags(t *testing.T) {
+	osRelease, _ := os.ReadFile("/etc/os-release")
+	defer func() { _ = os.WriteFile("/etc/os-release", osRelease, 0644) }()
+	_ = system.CopyFile(path.Join(TstFilesInGOPATH, "osr15"), "/etc/os-release")
+
+	solFile, err := os.CreateTemp("", "saptune_ostags*.sol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(solFile.Name())
+	_, _ = solFile.WriteString("[version]\nVERSION=1\n[ArchX86]\nN1 N2\n[ArchPPC64LE]\nP1\n[ArchX86:os=15-*]\nN1 N3\n[ArchX86:os=12-*]\nN9\n")
+	solFile.Close()
+
+	expected := []txtparser.INIEntry{
+		{Section: "version", Key: "VERSION", Operator: "=", Value: "1"},
+		{Section: "ArchX86", Value: "N1\tN3"},
+		{Section: "ArchPPC64LE", Value: "P1"},
+	}
+	content, err := parseSolutionFile(solFile.Name())
+	if err != nil || !reflect.DeepEqual(content.AllValues, expected) {
+		t.Errorf("got: %+v, expected: %+v - %v\n", content.AllValues, expected, err)
+	}
+
+	_ = system.CopyFile(path.Join(TstFilesInGOPATH, "osr12"), "/etc/os-release")
+	expected[1].Value = "N9"
+	content, err = parseSolutionFile(solFile.Name())
+	if err != nil || !reflect.DeepEqual(content.AllValues, expected) {
+		t.Errorf("got: %+v, expected: %+v - %v\n", content.AllValues, expected, err)
+	}
+	if _, err := parseSolutionFile("/no_solution_file.sol"); err == nil {
+		t.Error("expected an error for a missing solution file")
+	}
+}
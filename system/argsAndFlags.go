@@ -50,13 +50,13 @@ func GetFlagVal(flag string) string {
 // returns a map of Flags (set/not set or value) and a slice containing the
 // remaining arguments
 // possible Flags - force, dryrun, help, version, show-non-compliant, format,
-// colorscheme, non-compliance-check
-// on command line - --force, --dry-run or --dryrun, --help, --version, --color-scheme, --format
+// colorscheme, non-compliance-check, output, apply
+// on command line - --force, --dry-run or --dryrun, --help, --version, --color-scheme, --format, --output, --apply
 // Some Flags (like 'format') can have a value (--format json or --format csv)
 func ParseCliArgs() ([]string, map[string]string) {
 	stArgs := []string{}
 	// supported flags
-	stFlags := map[string]string{"force": "false", "dryrun": "false", "help": "false", "version": "false", "show-non-compliant": "false", "format": "", "colorscheme": "", "non-compliance-check": "false", "notSupported": "", "force-color": "false", "fun": "false"}
+	stFlags := map[string]string{"force": "false", "dryrun": "false", "help": "false", "version": "false", "show-non-compliant": "false", "format": "", "colorscheme": "", "non-compliance-check": "false", "notSupported": "", "force-color": "false", "fun": "false", "output": "", "apply": "false"}
 	skip := false
 	for i, arg := range os.Args {
 		if skip {
@@ -106,6 +106,11 @@ func handleValueFlags(arg, farg string, flags map[string]string) bool {
 		flags["colorscheme"] = farg
 		skip = true
 	}
+	if arg == "--output" || arg == "-output" {
+		// --output /tmp/report.html
+		flags["output"] = farg
+		skip = true
+	}
 	return skip
 }
 
@@ -129,6 +134,8 @@ func handleSimpleFlags(arg string, flags map[string]string) {
 		flags["force-color"] = "true"
 	case "--fun", "-fun":
 		flags["fun"] = "true"
+	case "--apply", "-apply":
+		flags["apply"] = "true"
 	default:
 		setUnsupportedFlag(arg, flags)
 	}
@@ -230,7 +237,7 @@ func chkGlobalOpts(cmdLinePos map[string]int) bool {
 		ret = false
 	}
 	if IsFlagSet("format") {
-		if GetFlagVal("format") != "json" {
+		if !supportedFormats[GetFlagVal("format")] {
 			DebugLog("chkGlobalOpts failed - wrong 'format' value '%+v'", GetFlagVal("format"))
 			ret = false
 		}
@@ -288,7 +295,8 @@ func chkGlobalFlag(globalFlags []string, result bool) (bool, bool, int) {
 }
 
 // chkRealmOpts checks for realm options
-// at the moment only 'saptune status' has an option (--non-compliance-check)
+// at the moment only 'saptune status' (--non-compliance-check) and
+// 'saptune converge' (--dry-run) have an option
 func chkRealmOpts(cmdLinePos map[string]int) bool {
 	DebugLog("chkRealmOpts - cmdLinePos is '%+v'", cmdLinePos)
 	stArgs := os.Args
@@ -316,6 +324,22 @@ func chkRealmOpts(cmdLinePos map[string]int) bool {
 			}
 		}
 	}
+	if IsFlagSet("dryrun") && stArgs[cmdLinePos["realm"]] == "converge" {
+		// realm option set
+		// check minimum of values items in cmd line
+		// (saptune + realm + option)
+		if len(stArgs) < cmdLinePos["realmOpt"]+1 {
+			// too few arguments
+			DebugLog("chkRealmOpts failed - too few arguments for realm 'converge'")
+			ret = false
+		} else if stArgs[cmdLinePos["realmOpt"]] != "--dry-run" {
+			DebugLog("chkRealmOpts failed - 'dry-run' flag on wrong position in command line")
+			ret = false
+		} else {
+			cmdLinePos["cmd"] = cmdLinePos["cmd"] + 1
+			cmdLinePos["cmdOpt"] = cmdLinePos["cmdOpt"] + 1
+		}
+	}
 	return ret
 }
 
@@ -325,12 +349,12 @@ func chkCmdOpts(cmdLinePos map[string]int) bool {
 	ret := true
 	// check minimum of arguments for command options
 	// saptune realm cmd
-	if len(saptArgs) < 3 && (IsFlagSet("force") || IsFlagSet("dryrun") || IsFlagSet("colorscheme") || IsFlagSet("show-non-compliant")) {
+	if len(saptArgs) < 3 && (IsFlagSet("force") || IsFlagSet("dryrun") || IsFlagSet("colorscheme") || IsFlagSet("show-non-compliant") || IsFlagSet("output") || IsFlagSet("apply")) {
 		// too few arguments for the active flags
-		DebugLog("chkCmdOpts failed - too few arguments for flags 'force' or 'dryrun' or 'colorscheme' or 'show-non-compliant'")
+		DebugLog("chkCmdOpts failed - too few arguments for flags 'force' or 'dryrun' or 'colorscheme' or 'show-non-compliant' or 'output' or 'apply'")
 		return false
 	}
-	if len(os.Args) < cmdLinePos["cmdOpt"]+1 || (!IsFlagSet("force") && !IsFlagSet("dryrun") && !IsFlagSet("colorscheme") && !IsFlagSet("show-non-compliant") && !IsFlagSet("non-compliance-check")) {
+	if len(os.Args) < cmdLinePos["cmdOpt"]+1 || (!IsFlagSet("force") && !IsFlagSet("dryrun") && !IsFlagSet("colorscheme") && !IsFlagSet("show-non-compliant") && !IsFlagSet("non-compliance-check") && !IsFlagSet("output") && !IsFlagSet("apply")) {
 		// no command options set or too few options
 		// and/or non of the flags set, which need further checks
 		// so let the 'old' default checks (in main and/or actions) set
@@ -343,14 +367,23 @@ func chkCmdOpts(cmdLinePos map[string]int) bool {
 		// saptune solution change [--force] SOLUTIONNAME
 		// saptune solution apply [--force] SOLUTIONNAME
 		// saptune staging release [--force|--dry-run] [NOTE...|SOLUTION...|all]
+		// saptune staging rollback [--force|--dry-run] [NOTE...|SOLUTION...|all]
 		"chkForceFlag",
 		// saptune staging release [--force|--dry-run] [NOTE...|SOLUTION...|all]
+		// saptune staging rollback [--force|--dry-run] [NOTE...|SOLUTION...|all]
+		// saptune config import [--dry-run|--apply] FILE
 		"chkDryrunFlag",
 		// saptune note verify [--colorscheme <color scheme>] [--show-non-compliant] [NOTEID]
 		// saptune solution verify [--colorscheme <color scheme>] [--show-non-compliant] [SOLUTIONNAME]
 		"chkVerifySyntax",
 		// saptune (service) status  [--non-compliance-check]
 		"chkServiceStatusSyntax",
+		// saptune report html [--output FILE]
+		"chkOutputFlag",
+		// saptune solution recommend [--apply]
+		// saptune solution upgrade-check [--apply]
+		// saptune config import [--dry-run|--apply] FILE
+		"chkApplyFlag",
 	}
 
 	for _, flag := range flagToCheck {
@@ -396,8 +429,8 @@ func checkFlag(cmdLinePos map[string]int, flagValue string) bool {
 	// os.Args = []string{"saptune", "solution", "change", "--force"}
 	switch flagValue {
 	case "chkForceFlag":
-		// Checks the syntax of 'saptune solution change' and 'saptune staging release' regarding the 'force' flag
-		notInRealm := syntaxCheckNotRealm([][]string{{"solution", "change"}, {"solution", "apply"}, {"staging", "release"}})
+		// Checks the syntax of 'saptune solution change' and 'saptune staging release|rollback' regarding the 'force' flag
+		notInRealm := syntaxCheckNotRealm([][]string{{"solution", "change"}, {"solution", "apply"}, {"staging", "release"}, {"staging", "rollback"}})
 		isWrongPosition := stArgs[cmdLinePos["cmdOpt"]] != "--force"
 		result = runChecks("chkForceFlag", "force", "force", notInRealm, isWrongPosition)
 
@@ -409,13 +442,25 @@ func checkFlag(cmdLinePos map[string]int, flagValue string) bool {
 		result = runChecks("chkServiceStatusSyntax", "non-compliance-check", "non-compliance-check", notInRealm, isWrongPosition)
 
 	case "chkDryrunFlag":
-		// Checks the syntax of 'saptune staging release' regarding the use of the 'dry-run' flag
-		notInRealm := syntaxCheckNotRealm([][]string{{"staging", "release"}})
+		// Checks the syntax of 'saptune staging release|rollback' and 'saptune config import' regarding the use of the 'dry-run' flag
+		notInRealm := syntaxCheckNotRealm([][]string{{"staging", "release"}, {"staging", "rollback"}, {"config", "import"}})
 		isWrongPosition := stArgs[cmdLinePos["cmdOpt"]] != "--dry-run"
 		result = runChecks("chkDryrunFlag", "dry-run", "dryrun", notInRealm, isWrongPosition)
 
 	case "chkVerifySyntax":
 		result = chkVerifySyntax(stArgs, cmdLinePos, result)
+
+	case "chkOutputFlag":
+		// Checks the syntax of 'saptune report html' regarding the use of the 'output' flag
+		notInRealm := syntaxCheckNotRealm([][]string{{"report", "html"}})
+		isWrongPosition := stArgs[cmdLinePos["cmdOpt"]] != "--output"
+		result = runChecks("chkOutputFlag", "output", "output", notInRealm, isWrongPosition)
+
+	case "chkApplyFlag":
+		// Checks the syntax of 'saptune solution recommend|upgrade-check' and 'saptune config import' regarding the use of the 'apply' flag
+		notInRealm := syntaxCheckNotRealm([][]string{{"solution", "recommend"}, {"solution", "upgrade-check"}, {"config", "import"}})
+		isWrongPosition := stArgs[cmdLinePos["cmdOpt"]] != "--apply"
+		result = runChecks("chkApplyFlag", "apply", "apply", notInRealm, isWrongPosition)
 	}
 
 	return result
@@ -324,6 +324,76 @@ func TestChkCliSyntax(t *testing.T) {
 		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
 	}
 
+	// {"saptune", "staging", "rollback", "--dry-run", "all"} -> ok
+	os.Args = []string{"saptune", "staging", "rollback", "--dry-run", "all"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "solution", "recommend", "--apply"} -> ok
+	os.Args = []string{"saptune", "solution", "recommend", "--apply"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "solution", "upgrade-check", "--apply"} -> ok
+	os.Args = []string{"saptune", "solution", "upgrade-check", "--apply"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "config", "import", "--dry-run", "FILE"} -> ok
+	os.Args = []string{"saptune", "config", "import", "--dry-run", "/tmp/config.tar.gz"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "converge", "--dry-run", "FILE"} -> ok
+	os.Args = []string{"saptune", "converge", "--dry-run", "/tmp/state.json"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "--format", "json", "converge", "--dry-run", "FILE"} -> ok
+	os.Args = []string{"saptune", "--format", "json", "converge", "--dry-run", "/tmp/state.json"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "converge", "FILE", "--dry-run"} -> wrong
+	os.Args = []string{"saptune", "converge", "/tmp/state.json", "--dry-run"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "config", "import", "--apply", "FILE"} -> ok
+	os.Args = []string{"saptune", "config", "import", "--apply", "/tmp/config.tar.gz"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if !ChkCliSyntax() {
+		t.Errorf("Test failed, expected good syntax, but got 'wrong'")
+	}
+
+	// {"saptune", "config", "export", "--dry-run", "FILE"} -> wrong
+	os.Args = []string{"saptune", "config", "export", "--dry-run", "/tmp/config.tar.gz"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
+	// {"saptune", "solution", "apply", "--apply", "HANA"} -> wrong
+	os.Args = []string{"saptune", "solution", "apply", "--apply", "HANA"}
+	saptArgs, saptFlags = ParseCliArgs()
+	if ChkCliSyntax() {
+		t.Errorf("Test failed, expected wrong syntax, but got 'good'")
+	}
+
 	// line with force AND dry-run
 	// {"saptune", "staging", "release", "--force", "--dry-run"} -> wrong
 	os.Args = []string{"saptune", "staging", "release", "--force", "--dry-run"}
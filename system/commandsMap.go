@@ -1,5 +1,12 @@
 package system
 
+// realmWithoutCmd contains the realms, which do not have commands. The
+// second argument of these realms is not part of the 'realm command'
+// combination (e.g. the file name of 'saptune converge FILE')
+var realmWithoutCmd = map[string]bool{
+	"converge": true,
+}
+
 // defaultCommand contains all available 'command - action' combinations
 var defaultCommand = map[string]bool{
 	"daemon start":                false,
@@ -32,6 +39,8 @@ var defaultCommand = map[string]bool{
 	"note verify":                 false,
 	"note rename":                 false,
 	"note refresh":                false,
+	"note lint":                   false,
+	"note override":               false,
 	"solution list":               false,
 	"solution verify":             false,
 	"solution enabled":            false,
@@ -46,6 +55,9 @@ var defaultCommand = map[string]bool{
 	"solution show":               false,
 	"solution delete":             false,
 	"solution rename":             false,
+	"solution lint":               false,
+	"solution recommend":          false,
+	"solution upgrade-check":      false,
 	"staging status":              false,
 	"staging enable":              false,
 	"staging disable":             false,
@@ -54,6 +66,8 @@ var defaultCommand = map[string]bool{
 	"staging diff":                false,
 	"staging analysis":            false,
 	"staging release":             false,
+	"staging rollback":            false,
+	"staging history":             false,
 	"configure COLOR_SCHEME":      false,
 	"configure SKIP_SYSCTL_FILES": false,
 	"configure IGNORE_RELOAD":     false,
@@ -61,11 +75,17 @@ var defaultCommand = map[string]bool{
 	"configure TrentoASDP":        false,
 	"configure reset":             false,
 	"configure show":              false,
+	"configure describe":          false,
+	"config export":               false,
+	"config import":               false,
+	"config diff":                 false,
 	"refresh applied":             false,
 	"verify applied":              false,
+	"report html":                 false,
 	"revert all":                  false,
 	"lock remove":                 false,
 	"check":                       false,
+	"converge":                    false,
 	"status":                      false,
 	"version":                     false,
 	"help":                        false,
@@ -95,14 +115,32 @@ func supportedRACMap() map[string]bool {
 	supportedRAC["solution verify"] = true
 	supportedRAC["solution enabled"] = true
 	supportedRAC["solution applied"] = true
+	supportedRAC["solution recommend"] = true
+	supportedRAC["solution upgrade-check"] = true
+	supportedRAC["configure show"] = true
+	supportedRAC["configure describe"] = true
+	supportedRAC["config diff"] = true
 	supportedRAC["status"] = true
 	supportedRAC["verify applied"] = true
 	supportedRAC["version"] = true
 	supportedRAC["check"] = true
+	supportedRAC["staging history"] = true
 
 	return supportedRAC
 }
 
+// reportRACMap contains the supported 'command - action' combinations
+// for the report output formats junit and prometheus
+func reportRACMap() map[string]bool {
+	var reportRAC = newDefaultCommandsMap()
+
+	reportRAC["note verify"] = true
+	reportRAC["solution verify"] = true
+	reportRAC["verify applied"] = true
+
+	return reportRAC
+}
+
 // lockCommandsMap contains the supported 'command - action' combinations
 // for the saptune locking mechanism
 func lockCommandsMap() map[string]bool {
@@ -128,6 +166,7 @@ func lockCommandsMap() map[string]bool {
 	lockCommand["note delete"] = true
 	lockCommand["note rename"] = true
 	lockCommand["note refresh"] = true
+	lockCommand["note override"] = true
 	lockCommand["solution apply"] = true
 	lockCommand["solution change"] = true
 	lockCommand["solution customise"] = true
@@ -136,6 +175,8 @@ func lockCommandsMap() map[string]bool {
 	lockCommand["solution revert"] = true
 	lockCommand["solution delete"] = true
 	lockCommand["solution rename"] = true
+	lockCommand["solution recommend"] = true
+	lockCommand["solution upgrade-check"] = true
 	lockCommand["staging status"] = true
 	lockCommand["staging enable"] = true
 	lockCommand["staging disable"] = true
@@ -144,10 +185,15 @@ func lockCommandsMap() map[string]bool {
 	lockCommand["staging diff"] = true
 	lockCommand["staging analysis"] = true
 	lockCommand["staging release"] = true
+	lockCommand["staging rollback"] = true
+	lockCommand["staging history"] = true
 	lockCommand["configure reset"] = true
 	lockCommand["configure TrentoASDP"] = true
+	lockCommand["config export"] = true
+	lockCommand["config import"] = true
 	lockCommand["refresh applied"] = true
 	lockCommand["revert all"] = true
+	lockCommand["converge"] = true
 
 	return lockCommand
 }
@@ -3,6 +3,7 @@ package system
 import (
 	"bytes"
 	"crypto/md5"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -123,16 +124,22 @@ func FileIsEmpty(fileName string) bool {
 // EditFile copies a source file to another name and opens this copy in an
 // editor defined by environment variable "EDITOR" or in 'vim'
 func EditFile(srcFile, destFile string) error {
-	editor := os.Getenv("EDITOR")
 	// copy source to destintion
 	if err := CopyFile(srcFile, destFile); err != nil {
 		ErrorLog("Problems while copying '%s' to '%s' - %v", srcFile, destFile, err)
 		return err
 	}
+	return runEditor(destFile)
+}
+
+// runEditor opens a file in an editor defined by environment variable
+// "EDITOR" or in 'vim'
+func runEditor(fileName string) error {
+	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = "/usr/bin/vim" // launch vim by default
 	}
-	cmd := exec.Command(editor, destFile)
+	cmd := exec.Command(editor, fileName)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -143,33 +150,61 @@ func EditFile(srcFile, destFile string) error {
 	return err
 }
 
+// ErrEditAborted is returned by EditValidateAndCheckFile, if the user
+// aborts the editing because of validation problems
+var ErrEditAborted = errors.New("editing aborted, changes discarded")
+
+// EditValidator checks the temporary file of an editor session before it
+// is saved. It returns true, if the file can be saved. Otherwise reEdit
+// reports, if the file should be opened in the editor again or if the
+// editing should be aborted
+type EditValidator func(tmpFile string) (valid, reEdit bool)
+
 // EditAndCheckFile creates or modify note or solution definition files
-// using an editor
 func EditAndCheckFile(srcFileName, destFileName, defName, defType string) (bool, error) {
+	return EditValidateAndCheckFile(srcFileName, destFileName, defName, defType, nil)
+}
+
+// EditValidateAndCheckFile creates or modify note or solution definition
+// files. If a validator is given, the edited temporary file is checked
+// before it replaces the destination file. As long as the validator
+// requests it, the temporary file is opened again in the editor, so the
+// changes of the user are not lost
+func EditValidateAndCheckFile(srcFileName, destFileName, defName, defType string, validate EditValidator) (bool, error) {
 	var err error
 	changed := false
 	tmpFile := fmt.Sprintf("/tmp/%s.sttemp", defName)
+	// remove no longer needed temporary file
+	defer os.Remove(tmpFile)
 	if err = EditFile(srcFileName, tmpFile); err != nil {
-		// clean up before exit
-		os.Remove(tmpFile)
 		ErrorLog("Problems while editing %s definition file '%s' - %v", defType, destFileName, err)
 		return changed, err
 	}
 	// check if something was changed in the file
-	if !ChkMD5Pair(srcFileName, tmpFile) {
+	for !ChkMD5Pair(srcFileName, tmpFile) {
 		// template and temporary file differ, so something was
 		// written/changed during the editor session
+		if validate != nil {
+			valid, reEdit := validate(tmpFile)
+			if !valid && reEdit {
+				if err = runEditor(tmpFile); err != nil {
+					ErrorLog("Problems while editing %s definition file '%s' - %v", defType, destFileName, err)
+					return changed, err
+				}
+				continue
+			}
+			if !valid {
+				return changed, ErrEditAborted
+			}
+		}
 		// copy temporary file to extra location
 		if err = CopyFile(tmpFile, destFileName); err != nil {
-			// clean up before exit
-			os.Remove(tmpFile)
 			ErrorLog("Problems writing %s definition file '%s' - %v", defType, destFileName, err)
 			return changed, err
 		}
 		changed = true
+		break
 	}
-	// remove no longer needed temporary file
-	os.Remove(tmpFile)
 	return changed, err
 }
 
@@ -154,6 +154,50 @@ func TestEditAndCheckFile(t *testing.T) {
 	os.Remove(dst)
 }
 
+func TestEditValidateAndCheckFile(t *testing.T) {
+	oldEditor := os.Getenv("EDITOR")
+	defer func() { os.Setenv("EDITOR", oldEditor) }()
+	os.Setenv("EDITOR", path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/testdata/angi-editor"))
+	src := path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/testdata/tstfile")
+	dst := "/tmp/saptune_tstfile"
+	defer os.Remove(dst)
+
+	// re-edit once, then abort
+	calls := 0
+	validate := func(tmpFile string) (bool, bool) {
+		calls++
+		return false, calls == 1
+	}
+	changed, err := EditValidateAndCheckFile(src, dst, "ANGI", "note", validate)
+	if err != ErrEditAborted {
+		t.Errorf("expected '%v', got '%v'", ErrEditAborted, err)
+	}
+	if changed {
+		t.Error("got 'true', but expected 'false'")
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 validations, got %d", calls)
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("file '%s' should not be written", dst)
+	}
+	if _, err := os.Stat("/tmp/ANGI.sttemp"); !os.IsNotExist(err) {
+		t.Error("temporary file should be removed")
+	}
+
+	// valid file
+	validate = func(tmpFile string) (bool, bool) {
+		return true, false
+	}
+	changed, err = EditValidateAndCheckFile(src, dst, "ANGI", "note", validate)
+	if err != nil {
+		t.Error(err)
+	}
+	if !changed {
+		t.Error("got 'false', but expected 'true'")
+	}
+}
+
 func TestMD5(t *testing.T) {
 	//src := "/app/testdata/tstfile"
 	match1 := "1fd006c2c4a9c3bebb749b43889339f6"
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadManifest reads a checksum manifest in the format of the sha256sum
+// command ('<sha256 checksum>  <file name>' per line) and returns the
+// checksums by file name
+func ReadManifest(content []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lnr := 0
+	for scanner.Scan() {
+		lnr++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != sha256.Size*2 {
+			return nil, fmt.Errorf("line %d: wrong syntax, expected '<sha256 checksum>  <file name>'", lnr)
+		}
+		if _, err := hex.DecodeString(fields[0]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid checksum '%s'", lnr, fields[0])
+		}
+		// sha256sum marks binary mode with a leading '*'
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums, scanner.Err()
+}
+
+// VerifySignature verifies the detached signature of data with a public key
+// in PEM format (PKIX). Supported are ed25519 keys, RSA keys (PKCS #1 v1.5
+// with SHA-256) and ECDSA keys (ASN.1 signature with SHA-256).
+// The signature can be raw binary or base64 encoded
+func VerifySignature(data, sig, pubKeyPEM []byte) error {
+	block, _ := pem.Decode(pubKeyPEM)
+	if block == nil {
+		return fmt.Errorf("no PEM encoded public key found")
+	}
+	pubKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("unsupported public key - %v", err)
+	}
+	if dec, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig))); err == nil {
+		sig = dec
+	}
+	digest := sha256.Sum256(data)
+	switch key := pubKey.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, sig) {
+			return fmt.Errorf("invalid ed25519 signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+			return fmt.Errorf("invalid RSA signature - %v", err)
+		}
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], sig) {
+			return fmt.Errorf("invalid ECDSA signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", pubKey)
+	}
+	return nil
+}
+
+// VerifyManifestFile verifies the detached signature (manifestFile + '.sig')
+// of a checksum manifest with the public key file and returns the
+// checksums of the manifest
+func VerifyManifestFile(manifestFile, pubKeyFile string) (map[string]string, error) {
+	pubKey, err := os.ReadFile(pubKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := os.ReadFile(manifestFile + ".sig")
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifySignature(content, sig, pubKey); err != nil {
+		return nil, fmt.Errorf("signature of manifest '%s' - %v", manifestFile, err)
+	}
+	sums, err := ReadManifest(content)
+	if err != nil {
+		return nil, fmt.Errorf("manifest '%s' - %v", manifestFile, err)
+	}
+	return sums, nil
+}
+
+// GetSHA256Hash generates the sha256 checksum of a file
+func GetSHA256Hash(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
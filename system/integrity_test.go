@@ -0,0 +1,73 @@
+package system
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestReadManifest(t *testing.T) {
+	sum := "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+	sums, err := ReadManifest([]byte("# comment\n" + sum + "  notes/4711\n\n" + sum + " *sols/TST.sol\n"))
+	if err != nil || len(sums) != 2 || sums["notes/4711"] != sum || sums["sols/TST.sol"] != sum {
+		t.Errorf("got '%v', '%v'", sums, err)
+	}
+	if _, err := ReadManifest([]byte("abc  notes/4711\n")); err == nil {
+		t.Error("expected an error for an invalid checksum")
+	}
+	if _, err := ReadManifest([]byte(sum + "\n")); err == nil {
+		t.Error("expected an error for a missing file name")
+	}
+}
+
+func TestVerifyManifestFile(t *testing.T) {
+	tstDir := "/tmp/saptune_integrity_test/"
+	defer os.RemoveAll(tstDir)
+	_ = os.MkdirAll(tstDir, 0755)
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	pubKeyFile := path.Join(tstDir, "pubkey.pem")
+	_ = os.WriteFile(pubKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644)
+
+	noteFile := path.Join(tstDir, "4711")
+	_ = os.WriteFile(noteFile, []byte("foo"), 0644)
+	sum, err := GetSHA256Hash(noteFile)
+	if err != nil || sum != "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae" {
+		t.Errorf("got '%s', '%v'", sum, err)
+	}
+	manifest := []byte(sum + "  notes/4711\n")
+	manifestFile := path.Join(tstDir, "SHA256SUMS")
+	_ = os.WriteFile(manifestFile, manifest, 0644)
+
+	// missing signature
+	if _, err := VerifyManifestFile(manifestFile, pubKeyFile); err == nil {
+		t.Error("expected an error for a missing signature")
+	}
+	// valid raw and base64 encoded signature
+	sig := ed25519.Sign(priv, manifest)
+	_ = os.WriteFile(manifestFile+".sig", sig, 0644)
+	if sums, err := VerifyManifestFile(manifestFile, pubKeyFile); err != nil || sums["notes/4711"] != sum {
+		t.Errorf("got '%v', '%v'", sums, err)
+	}
+	_ = os.WriteFile(manifestFile+".sig", []byte(base64.StdEncoding.EncodeToString(sig)+"\n"), 0644)
+	if _, err := VerifyManifestFile(manifestFile, pubKeyFile); err != nil {
+		t.Error(err)
+	}
+	// tampered manifest
+	_ = os.WriteFile(manifestFile, []byte(sum+"  notes/4712\n"), 0644)
+	if _, err := VerifyManifestFile(manifestFile, pubKeyFile); err == nil {
+		t.Error("expected an error for a tampered manifest")
+	}
+	// no public key
+	if err := VerifySignature(manifest, sig, []byte("no key")); err == nil {
+		t.Error("expected an error for an invalid public key")
+	}
+}
@@ -8,8 +8,20 @@ import (
 	"time"
 )
 
-var schemaDir = "file:///usr/share/saptune/schemas/1.1/"
+var schemaDir = "file:///usr/share/saptune/schemas/1.2/"
 var supportedRAC map[string]bool = supportedRACMap()
+var reportRAC map[string]bool = reportRACMap()
+
+// supportedFormats are the machine readable output formats, which can be
+// selected by the '--format' flag. All of them share the same result model
+// (JEntry), only the final encoding differs.
+// The report formats 'junit' and 'prometheus' are only available for the
+// 'verify' commands (see reportRACMap)
+var supportedFormats = map[string]bool{"json": true, "yaml": true, "csv": true, "junit": true, "prometheus": true}
+
+// jmetrics contains the additional information needed for the prometheus
+// output
+var jmetrics JMetricsInfo
 
 // jentry is the json entry to display
 var jentry JEntry
@@ -124,21 +136,25 @@ type JNoteList struct {
 // JPNotesLine one row of 'saptune note verify|simulate'
 // from PrintNoteFields
 type JPNotesLine struct {
-	NoteID    string       `json:"Note ID,omitempty"`
-	NoteVers  string       `json:"Note version,omitempty"`
-	Parameter string       `json:"parameter"`
-	Compliant *bool        `json:"compliant,omitempty"`
-	ExpValue  string       `json:"expected value,omitempty"`
-	OverValue string       `json:"override value,omitempty"`
-	ActValue  *string      `json:"actual value,omitempty"`
-	Comment   string       `json:"comment,omitempty"`
-	Footnotes []JFootNotes `json:"amendments,omitempty"`
+	NoteID        string       `json:"Note ID,omitempty"`
+	NoteVers      string       `json:"Note version,omitempty"`
+	Parameter     string       `json:"parameter"`
+	Compliant     *bool        `json:"compliant,omitempty"`
+	ExpValue      string       `json:"expected value,omitempty"`
+	OverValue     string       `json:"override value,omitempty"`
+	ActValue      *string      `json:"actual value,omitempty"`
+	Comment       string       `json:"comment,omitempty"`
+	Footnotes     []JFootNotes `json:"amendments,omitempty"`
+	FootnoteCodes []string     `json:"amendment codes,omitempty"`
+	OverSource    string       `json:"override source,omitempty"`
 }
 
 // JFootNotes collects the footnotes per parameter
 type JFootNotes struct {
-	FNoteNumber int    `json:"index,omitempty"`
-	FNoteTxt    string `json:"amendment,omitempty"`
+	FNoteNumber   int    `json:"index,omitempty"`
+	FNoteTxt      string `json:"amendment,omitempty"`
+	FNoteCode     string `json:"code,omitempty"`
+	FNoteCategory string `json:"category,omitempty"`
 }
 
 // JPNotesRemind is the reminder section
@@ -191,6 +207,22 @@ type JStatusStaging struct {
 	StagedSols     []string `json:"Solutions staged"`
 }
 
+// JStagingHistoryEntry is a single recorded staging operation (enable,
+// disable, release or rollback) of 'saptune staging history'
+type JStagingHistoryEntry struct {
+	Time    string `json:"time"`
+	User    string `json:"user"`
+	Action  string `json:"action"`
+	Object  string `json:"object,omitempty"`
+	OldVers string `json:"old version,omitempty"`
+	NewVers string `json:"new version,omitempty"`
+}
+
+// JStagingHistory is the whole 'saptune staging history'
+type JStagingHistory struct {
+	History []JStagingHistoryEntry `json:"staging history"`
+}
+
 // JStatusServs are the mentioned systemd services in 'saptune status'
 type JStatusServs struct {
 	SaptuneService JObj    `json:"saptune"`
@@ -209,16 +241,120 @@ type JSolListEntry struct {
 	DepSol      bool     `json:"Solution deprecated"`
 }
 
+// JSAPComponent is a SAP component detected on the host for
+// 'saptune solution recommend'
+type JSAPComponent struct {
+	SID      string `json:"SID,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Type     string `json:"type"`
+	Source   string `json:"detected by"`
+}
+
+// JSolRecommend is the whole 'saptune solution recommend'
+type JSolRecommend struct {
+	Components  []JSAPComponent `json:"components detected"`
+	Solution    string          `json:"recommended Solution"`
+	Explanation []string        `json:"explanation"`
+	Applied     bool            `json:"applied"`
+}
+
+// JSolUpgradeParam is the impact of a solution upgrade on a single
+// parameter for 'saptune solution upgrade-check'
+type JSolUpgradeParam struct {
+	Parameter string `json:"parameter"`
+	ActValue  string `json:"actual value"`
+	NewValue  string `json:"new value"`
+}
+
+// JSolUpgradeNote is a Note added to or removed from the solution
+// definition for 'saptune solution upgrade-check'
+type JSolUpgradeNote struct {
+	NoteID     string             `json:"Note ID"`
+	NoteName   string             `json:"Note description"`
+	Kept       bool               `json:"kept"`
+	Parameters []JSolUpgradeParam `json:"parameters"`
+}
+
+// JSolUpgrade is the whole 'saptune solution upgrade-check'
+type JSolUpgrade struct {
+	SolName string            `json:"Solution ID"`
+	Added   []JSolUpgradeNote `json:"Notes to add"`
+	Removed []JSolUpgradeNote `json:"Notes to remove"`
+	Applied bool              `json:"applied"`
+}
+
+// JConfigDiffEntry is a single difference found by 'saptune config diff'
+type JConfigDiffEntry struct {
+	Category  string `json:"category"`
+	Item      string `json:"item"`
+	Local     string `json:"local"`
+	Reference string `json:"reference"`
+}
+
+// JConfigDiff is the whole 'saptune config diff'
+type JConfigDiff struct {
+	Reference   string             `json:"reference"`
+	RefType     string             `json:"reference type"`
+	Identical   bool               `json:"identical"`
+	Differences []JConfigDiffEntry `json:"differences"`
+}
+
+// JConfigKey is the description and the current value of a variable of the
+// saptune configuration file
+type JConfigKey struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Values      []string `json:"allowed values"`
+	Default     string   `json:"default"`
+	Value       *string  `json:"value"`
+	Source      string   `json:"source"`
+	Mandatory   bool     `json:"mandatory"`
+	Changeable  bool     `json:"changeable"`
+	DropIn      bool     `json:"drop-in"`
+	Description string   `json:"description"`
+	SideEffect  string   `json:"side effect"`
+}
+
+// JConfigKeys is the whole 'saptune configure show' and
+// 'saptune configure describe'
+type JConfigKeys struct {
+	ConfigFile string       `json:"configuration file"`
+	Keys       []JConfigKey `json:"variables"`
+}
+
 // JSolList is the whole 'saptune solution list'
 type JSolList struct {
 	SolsList []JSolListEntry `json:"Solutions available"`
 	Msg      string          `json:"remember message"`
 }
 
+// JMetricsInfo contains the system information needed additional to the
+// verify result for the prometheus output
+type JMetricsInfo struct {
+	Services       []JMetricsServ
+	StagingEnabled bool
+	StagedNotes    []string
+	StagedSols     []string
+	SaptuneVersion string
+	RPMVersion     string
+	// directory of the node_exporter textfile collector
+	TextfileDir string
+}
+
+// JMetricsServ is the state of a systemd service for the prometheus output
+type JMetricsServ struct {
+	Name    string
+	Enabled bool
+	Active  bool
+}
+
+// jCreatedFormat is the time format of the creation time of a json entry
+const jCreatedFormat = "2006-01-02 15:04:05.000"
+
 // jInit creates an initial json entry
 // used in system/InitOut
 func jInit() {
-	created := time.Now().Format("2006-01-02 15:04:05.000")
+	created := time.Now().Format(jCreatedFormat)
 	rac := realmAndCmd()
 	jentry = JEntry{
 		Schema:    schemaName(rac),
@@ -231,12 +367,18 @@ func jInit() {
 	}
 }
 
+// IsStructuredOut returns true, if a machine readable output format was
+// requested by the '--format' flag
+func IsStructuredOut() bool {
+	return supportedFormats[GetFlagVal("format")]
+}
+
 // jWriteMsg appends messages from logging to the json entry
 // instead of writing to stdout/stderr
 // used in system/logging
 func jWriteMsg(prio, msg string) {
 	var jmsg JMsg
-	if GetFlagVal("format") != "json" {
+	if !IsStructuredOut() {
 		return
 	}
 	jmsg.Prio = prio
@@ -244,19 +386,38 @@ func jWriteMsg(prio, msg string) {
 	jentry.CmdMsg = append(jentry.CmdMsg, jmsg)
 }
 
-// jOut writes the json output to stdout
+// jOut writes the json, yaml, csv, junit or prometheus output to stdout
 // used in function system/ErrorExit
 func jOut(exit int) error {
 	var err error
-	if GetFlagVal("format") != "json" {
+	if !IsStructuredOut() {
 		return err
 	}
 	// reset stdout to original setting
 	os.Stdout = stdOutOrg
 	jentry.CmdRet = exit
 	data, err := json.Marshal(jentry)
+	if err != nil {
+		return err
+	}
+	switch GetFlagVal("format") {
+	case "yaml":
+		data, err = jsonToYAML(data)
+	case "csv":
+		data, err = jsonToCSV(data)
+	case "junit":
+		data, err = jsonToJUnit(jentry)
+	case "prometheus":
+		data = jsonToPrometheus(jentry, jmetrics)
+	default:
+		data = append(data, '\n')
+	}
 	if err == nil {
-		fmt.Println(string(data))
+		fmt.Print(string(data))
+	}
+	if err == nil && GetFlagVal("format") == "prometheus" && reportRAC[realmAndCmd()] {
+		// only the verify commands provide the compliance metrics
+		err = writePromTextfile(jmetrics.TextfileDir, data)
 	}
 	return err
 }
@@ -271,11 +432,12 @@ func JInvalid(exitStatus int) {
 	ErrorExit("", exitStatus)
 }
 
-// JnotSupportedYet is the answer of a command without json support yet
+// JnotSupportedYet is the answer of a command without json, yaml or csv
+// support yet
 // used in function action/SelectAction
 func JnotSupportedYet() {
 	rac := realmAndCmd()
-	if GetFlagVal("format") != "json" || racIsSupported(rac) {
+	if !IsStructuredOut() || racIsSupported(rac) {
 		return
 	}
 	jentry.CmdResult = notSupportedResult{Implemented: false}
@@ -285,7 +447,7 @@ func JnotSupportedYet() {
 // Jcollect collects the result data
 func Jcollect(data interface{}) {
 	rac := realmAndCmd()
-	if GetFlagVal("format") != "json" || !racIsSupported(rac) {
+	if !IsStructuredOut() || !racIsSupported(rac) {
 		return
 	}
 	switch res := data.(type) {
@@ -319,9 +481,12 @@ func Jcollect(data interface{}) {
 			appSol.AppliedSol = make([]JAppliedSol, 0)
 		}
 		jentry.CmdResult = appSol
-	case JSolList, JNoteList, JStatus, JPNotes:
-		//"solution list", "note list", "status", "daemon status", "service status", "note verify", "solution verify", "note simulate", "solution simulate":
+	case JSolList, JNoteList, JStatus, JPNotes, JStagingHistory, JSolRecommend, JSolUpgrade, JConfigDiff, JConfigKeys:
+		//"solution list", "note list", "status", "daemon status", "service status", "note verify", "solution verify", "note simulate", "solution simulate", "staging history", "solution recommend", "solution upgrade-check", "config diff", "configure show", "configure describe":
 		jentry.CmdResult = res
+	case JMetricsInfo:
+		// additional information for the prometheus output
+		jmetrics = res
 	case []byte:
 		// "saptune check" - "saptune_check --json" - []uint8
 		jentry.CmdResult = json.RawMessage(string(res))
@@ -338,7 +503,7 @@ func schemaName(name string) string {
 // realmAndCmd returns the realms name and the command name, if available
 func realmAndCmd() string {
 	rac := CliArg(1)
-	if CliArg(2) != "" {
+	if CliArg(2) != "" && !realmWithoutCmd[rac] {
 		rac = rac + " " + CliArg(2)
 	}
 	if rac == "" {
@@ -353,12 +518,16 @@ func realmAndCmd() string {
 	return rac
 }
 
-// racIsSupported checks, if the combination 'realm command' has json support
+// racIsSupported checks, if the combination 'realm command' has json (and
+// therefore yaml and csv) support
 func racIsSupported(rac string) bool {
 	if _, ok := supportedRAC[rac]; !ok {
 		// rac not a valid combination
 		JInvalid(1)
 	}
+	if GetFlagVal("format") == "junit" || GetFlagVal("format") == "prometheus" {
+		return reportRAC[rac]
+	}
 	return supportedRAC[rac]
 }
 
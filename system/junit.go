@@ -0,0 +1,175 @@
+package system
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// jUnitSuites is the root element of a JUnit XML report
+type jUnitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"timestamp,attr"`
+	Suites   []jUnitSuite `xml:"testsuite"`
+}
+
+// jUnitSuite is a JUnit test suite - one suite per Note
+type jUnitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Properties []jUnitProperty `xml:"properties>property,omitempty"`
+	Cases      []jUnitCase     `xml:"testcase"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+// jUnitProperty is a property of a JUnit test suite
+type jUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// jUnitCase is a JUnit test case - one case per parameter of a Note
+type jUnitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *jUnitProblem `xml:"failure,omitempty"`
+	Error     *jUnitProblem `xml:"error,omitempty"`
+	Skipped   *jUnitProblem `xml:"skipped,omitempty"`
+}
+
+// jUnitProblem is the failure, error or skipped information of a test case
+type jUnitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Txt     string `xml:",chardata"`
+}
+
+// jsonToJUnit converts the result of a 'verify' command into a JUnit XML
+// report. Each Note is a test suite, each parameter a test case.
+// Non-compliant parameters are reported as failures, not applicable
+// parameters as skipped.
+// Results of other commands or a failed 'verify' are reported as one test
+// case containing an error with the log messages
+func jsonToJUnit(entry JEntry) ([]byte, error) {
+	report := jUnitSuites{Name: "saptune " + entry.Cmd, Time: entry.Created}
+	if res, ok := entry.CmdResult.(JPNotes); ok {
+		report.Suites = jUnitNoteSuites(res)
+	}
+	if entry.CmdRet != 0 && len(report.Suites) == 0 {
+		report.Suites = []jUnitSuite{jUnitErrorSuite(entry)}
+	}
+	for _, suite := range report.Suites {
+		report.Tests = report.Tests + suite.Tests
+		report.Failures = report.Failures + suite.Failures
+		report.Errors = report.Errors + suite.Errors
+		report.Skipped = report.Skipped + suite.Skipped
+	}
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []byte(xml.Header + string(data) + "\n"), nil
+}
+
+// jUnitNoteSuites creates one test suite per Note from the verifications
+// the order of the Notes is preserved
+func jUnitNoteSuites(res JPNotes) []jUnitSuite {
+	suites := []jUnitSuite{}
+	suiteIdx := make(map[string]int)
+	for _, line := range res.Verifications {
+		idx, ok := suiteIdx[line.NoteID]
+		if !ok {
+			idx = len(suites)
+			suiteIdx[line.NoteID] = idx
+			suites = append(suites, jUnitSuite{
+				Name:       line.NoteID,
+				Properties: []jUnitProperty{{Name: "Note version", Value: line.NoteVers}},
+				Cases:      []jUnitCase{},
+			})
+		}
+		suite := &suites[idx]
+		tcase := jUnitParameterCase(line)
+		suite.Tests++
+		if tcase.Failure != nil {
+			suite.Failures++
+		}
+		if tcase.Skipped != nil {
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tcase)
+	}
+	for _, remind := range res.Attentions {
+		if idx, ok := suiteIdx[remind.NoteID]; ok {
+			suites[idx].SystemOut = remind.NoteReminder
+		}
+	}
+	return suites
+}
+
+// jUnitParameterCase creates the test case of a single parameter
+func jUnitParameterCase(line JPNotesLine) jUnitCase {
+	tcase := jUnitCase{Name: line.Parameter, ClassName: "saptune.note." + line.NoteID}
+	actual := "NA"
+	if line.ActValue != nil {
+		actual = *line.ActValue
+	}
+	fnotes := []string{}
+	for _, fn := range line.Footnotes {
+		if fn.FNoteTxt != "" {
+			fnotes = append(fnotes, strings.TrimSpace(fn.FNoteTxt))
+		}
+	}
+	switch {
+	case line.Compliant == nil:
+		msg := "not applicable"
+		if len(fnotes) > 0 {
+			msg = fnotes[0]
+		}
+		tcase.Skipped = &jUnitProblem{Message: msg}
+	case !*line.Compliant:
+		txt := fmt.Sprintf("expected: %s\nactual: %s\n", line.ExpValue, actual)
+		if line.OverValue != "" {
+			txt = txt + fmt.Sprintf("override: %s\n", line.OverValue)
+		}
+		for _, fn := range fnotes {
+			txt = txt + fn + "\n"
+		}
+		tcase.Failure = &jUnitProblem{
+			Message: fmt.Sprintf("expected '%s', but actual value is '%s'", line.ExpValue, actual),
+			Type:    "non-compliant",
+			Txt:     txt,
+		}
+	}
+	return tcase
+}
+
+// jUnitErrorSuite creates a test suite for a failed command, containing
+// the log messages as error
+func jUnitErrorSuite(entry JEntry) jUnitSuite {
+	msgs := []string{}
+	for _, msg := range entry.CmdMsg {
+		msgs = append(msgs, msg.Prio+": "+strings.TrimSpace(msg.Txt))
+	}
+	message := fmt.Sprintf("saptune exited with exit code %d", entry.CmdRet)
+	if _, ok := entry.CmdResult.(notSupportedResult); ok {
+		message = "command not supported for output format 'junit'"
+	}
+	return jUnitSuite{
+		Name:   "saptune",
+		Tests:  1,
+		Errors: 1,
+		Cases: []jUnitCase{{
+			Name:      entry.Cmd,
+			ClassName: "saptune",
+			Error:     &jUnitProblem{Message: message, Txt: strings.Join(msgs, "\n")},
+		}},
+	}
+}
@@ -0,0 +1,55 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJsonToJUnit(t *testing.T) {
+	yes := true
+	no := false
+	act1 := "75"
+	act2 := "7200"
+	entry := JEntry{
+		Cmd:    "verify applied",
+		CmdRet: 1,
+		CmdResult: JPNotes{
+			Verifications: []JPNotesLine{
+				{NoteID: "1410736", NoteVers: "6", Parameter: "net.ipv4.tcp_keepalive_intvl", Compliant: &yes, ExpValue: "75", ActValue: &act1},
+				{NoteID: "1410736", NoteVers: "6", Parameter: "net.ipv4.tcp_keepalive_time", Compliant: &no, ExpValue: "300", ActValue: &act2, Footnotes: []JFootNotes{{FNoteNumber: 3, FNoteTxt: " [3] value is only checked, but NOT set"}}},
+				{NoteID: "941735", NoteVers: "11", Parameter: "energy_perf_bias", ExpValue: "all:performance", Footnotes: []JFootNotes{{FNoteNumber: 1, FNoteTxt: " [1] setting is not supported by the system"}}},
+			},
+			Attentions: []JPNotesRemind{{NoteID: "941735", NoteReminder: "check manually"}},
+		},
+	}
+	data, err := jsonToJUnit(entry)
+	if err != nil {
+		t.Fatalf("unexpected error: '%v'", err)
+	}
+	report := string(data)
+	for _, expected := range []string{
+		`<testsuites name="saptune verify applied" tests="3" failures="1" errors="0" skipped="1"`,
+		`<testsuite name="1410736" tests="2" failures="1" errors="0" skipped="0">`,
+		`<property name="Note version" value="6"></property>`,
+		`<testcase name="net.ipv4.tcp_keepalive_intvl" classname="saptune.note.1410736"></testcase>`,
+		`<failure message="expected &#39;300&#39;, but actual value is &#39;7200&#39;" type="non-compliant">expected: 300&#xA;actual: 7200&#xA;[3] value is only checked, but NOT set&#xA;</failure>`,
+		`<testsuite name="941735" tests="1" failures="0" errors="0" skipped="1">`,
+		`<skipped message="[1] setting is not supported by the system"></skipped>`,
+		`<system-out>check manually</system-out>`,
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("missing '%s' in report:\n%s", expected, report)
+		}
+	}
+
+	// failed command without verification results
+	entry = JEntry{Cmd: "note list", CmdRet: 1, CmdResult: notSupportedResult{Implemented: false}, CmdMsg: JMessages{{Prio: "ERROR", Txt: "something failed\n"}}}
+	data, err = jsonToJUnit(entry)
+	if err != nil {
+		t.Fatalf("unexpected error: '%v'", err)
+	}
+	expected := `<error message="command not supported for output format &#39;junit&#39;">ERROR: something failed</error>`
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("missing '%s' in report:\n%s", expected, string(data))
+	}
+}
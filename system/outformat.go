@@ -0,0 +1,300 @@
+package system
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// plainYAMLKey matches map keys, which can be written to yaml without quotes
+var plainYAMLKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_ .-]*[A-Za-z0-9_.]$|^[A-Za-z_]$`)
+
+// outNode is one node of an order preserving representation of a json
+// document. It is the common base for the yaml and csv output, so that all
+// machine readable formats share the same result model (JEntry) and the
+// same order of the entries.
+type outNode struct {
+	// kind of the node - "object", "array", "string", "number", "bool"
+	// or "null"
+	kind string
+	// keys of an object in the order they appear in the json document
+	keys []string
+	// child nodes of an object or an array
+	vals []*outNode
+	// value of a scalar node
+	val string
+}
+
+// parseOutNode decodes a json document into an order preserving outNode tree
+func parseOutNode(data []byte) (*outNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeOutNode(dec)
+}
+
+// decodeOutNode reads the next json value from the decoder
+func decodeOutNode(dec *json.Decoder) (*outNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return decodeOutObject(dec)
+		}
+		return decodeOutArray(dec)
+	case string:
+		return &outNode{kind: "string", val: t}, nil
+	case json.Number:
+		return &outNode{kind: "number", val: t.String()}, nil
+	case bool:
+		return &outNode{kind: "bool", val: fmt.Sprintf("%v", t)}, nil
+	default:
+		return &outNode{kind: "null", val: "null"}, nil
+	}
+}
+
+// decodeOutObject reads the members of a json object up to the closing brace
+func decodeOutObject(dec *json.Decoder) (*outNode, error) {
+	node := &outNode{kind: "object"}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		child, err := decodeOutNode(dec)
+		if err != nil {
+			return nil, err
+		}
+		node.keys = append(node.keys, tok.(string))
+		node.vals = append(node.vals, child)
+	}
+	// read closing brace
+	_, err := dec.Token()
+	return node, err
+}
+
+// decodeOutArray reads the elements of a json array up to the closing bracket
+func decodeOutArray(dec *json.Decoder) (*outNode, error) {
+	node := &outNode{kind: "array"}
+	for dec.More() {
+		child, err := decodeOutNode(dec)
+		if err != nil {
+			return nil, err
+		}
+		node.vals = append(node.vals, child)
+	}
+	// read closing bracket
+	_, err := dec.Token()
+	return node, err
+}
+
+// member returns the child node of an object with the given key or nil
+func (node *outNode) member(key string) *outNode {
+	for i, k := range node.keys {
+		if k == key {
+			return node.vals[i]
+		}
+	}
+	return nil
+}
+
+// isEmpty returns true for objects and arrays without content
+func (node *outNode) isEmpty() bool {
+	return (node.kind == "object" || node.kind == "array") && len(node.vals) == 0
+}
+
+// jsonToYAML converts the json output of saptune into yaml
+func jsonToYAML(data []byte) ([]byte, error) {
+	root, err := parseOutNode(data)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.Buffer{}
+	buf.WriteString("---\n")
+	writeYAML(&buf, root, 0)
+	return buf.Bytes(), nil
+}
+
+// writeYAML writes the node in block style with the given indentation
+func writeYAML(writer io.Writer, node *outNode, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch {
+	case node.kind == "object" && !node.isEmpty():
+		for i, key := range node.keys {
+			writeYAMLEntry(writer, pad+yamlKey(key)+":", node.vals[i], indent)
+		}
+	case node.kind == "array" && !node.isEmpty():
+		for _, val := range node.vals {
+			if val.kind == "object" && !val.isEmpty() {
+				// compact notation - first map entry in the line
+				// of the list item
+				item := bytes.Buffer{}
+				writeYAML(&item, val, indent+1)
+				fmt.Fprintf(writer, "%s- %s", pad, strings.TrimPrefix(item.String(), pad+"  "))
+				continue
+			}
+			writeYAMLEntry(writer, pad+"-", val, indent)
+		}
+	default:
+		fmt.Fprintf(writer, "%s%s\n", pad, yamlScalar(node))
+	}
+}
+
+// writeYAMLEntry writes a map entry or a list item. Scalars and empty
+// collections are written on the same line, all others on the following
+// lines with increased indentation
+func writeYAMLEntry(writer io.Writer, prefix string, node *outNode, indent int) {
+	if node.isEmpty() || (node.kind != "object" && node.kind != "array") {
+		fmt.Fprintf(writer, "%s %s\n", prefix, yamlScalar(node))
+		return
+	}
+	fmt.Fprintf(writer, "%s\n", prefix)
+	writeYAML(writer, node, indent+1)
+}
+
+// yamlScalar returns the yaml representation of a scalar node or an empty
+// collection. Strings are always double quoted, so that special characters
+// and values like 'yes', 'no' or '1.0' keep their string type
+func yamlScalar(node *outNode) string {
+	switch node.kind {
+	case "object":
+		return "{}"
+	case "array":
+		return "[]"
+	case "string":
+		return quoteYAML(node.val)
+	}
+	return node.val
+}
+
+// yamlKey returns the map key, quoted if needed
+func yamlKey(key string) string {
+	if plainYAMLKey.MatchString(key) && key != "yes" && key != "no" {
+		return key
+	}
+	return quoteYAML(key)
+}
+
+// quoteYAML returns a double quoted yaml string. The json escaping rules
+// are a subset of the yaml double quoted style
+func quoteYAML(str string) string {
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(str)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// csvTableKeys are the result entries holding the rows of a table.
+// 'verify' uses 'verifications', 'simulate' uses 'simulations', 'note list'
+// and 'solution list' use the available Notes or Solutions, 'staging
+// history' uses the recorded staging operations
+var csvTableKeys = []string{"verifications", "simulations", "Notes available", "Solutions available", "staging history"}
+
+// jsonToCSV converts the result of the json output of saptune into csv.
+// If the result contains a table (see csvTableKeys), it is written with one
+// row per table entry. All other results (e.g. 'status') are written as
+// 'key,value' pairs.
+func jsonToCSV(data []byte) ([]byte, error) {
+	root, err := parseOutNode(data)
+	if err != nil {
+		return nil, err
+	}
+	result := root.member("result")
+	if result == nil {
+		result = &outNode{kind: "object"}
+	}
+	buf := bytes.Buffer{}
+	writer := csv.NewWriter(&buf)
+	if table := csvTable(result); table != nil {
+		err = writer.WriteAll(csvRows(table))
+	} else {
+		rows := [][]string{{"key", "value"}}
+		err = writer.WriteAll(append(rows, csvPairs("", result)...))
+	}
+	return buf.Bytes(), err
+}
+
+// csvTable returns the first non-empty table of the result
+func csvTable(result *outNode) *outNode {
+	if result.kind != "object" {
+		return nil
+	}
+	for _, key := range csvTableKeys {
+		if val := result.member(key); val != nil && val.kind == "array" && !val.isEmpty() {
+			return val
+		}
+	}
+	return nil
+}
+
+// csvRows returns the header line and the rows of a list of objects.
+// As empty fields are omitted in the json output, the columns are the
+// union of all object keys in the order of their first appearance
+func csvRows(table *outNode) [][]string {
+	header := []string{}
+	known := make(map[string]bool)
+	for _, obj := range table.vals {
+		for _, key := range obj.keys {
+			if !known[key] {
+				known[key] = true
+				header = append(header, key)
+			}
+		}
+	}
+	rows := [][]string{header}
+	for _, obj := range table.vals {
+		row := make([]string, len(header))
+		for c, key := range header {
+			if val := obj.member(key); val != nil {
+				row[c] = csvCell(val)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// csvCell returns the content of a single table cell. Lists are joined by
+// '; ', objects by ' '
+func csvCell(node *outNode) string {
+	switch node.kind {
+	case "object":
+		fields := []string{}
+		for _, val := range node.vals {
+			fields = append(fields, csvCell(val))
+		}
+		return strings.Join(fields, " ")
+	case "array":
+		fields := []string{}
+		for _, val := range node.vals {
+			fields = append(fields, csvCell(val))
+		}
+		return strings.Join(fields, "; ")
+	case "null":
+		return ""
+	}
+	return node.val
+}
+
+// csvPairs flattens a node into 'key,value' pairs. Nested keys are joined
+// by '.'
+func csvPairs(prefix string, node *outNode) [][]string {
+	if node.kind != "object" {
+		return [][]string{{prefix, csvCell(node)}}
+	}
+	pairs := [][]string{}
+	for i, key := range node.keys {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		pairs = append(pairs, csvPairs(key, node.vals[i])...)
+	}
+	return pairs
+}
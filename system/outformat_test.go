@@ -0,0 +1,92 @@
+package system
+
+import (
+	"testing"
+)
+
+var outFormatJSON = `{"$schema":"file:///usr/share/saptune/schemas/1.2/saptune_note_verify.schema.json","argv":"saptune --format yaml note verify","pid":4711,"command":"note verify","exit code":1,"result":{"verifications":[{"Note ID":"1410736","Note version":"6","parameter":"net.ipv4.tcp_keepalive_intvl","compliant":true,"expected value":"75","actual value":"75"},{"Note ID":"1410736","Note version":"6","parameter":"net.ipv4.tcp_keepalive_time","compliant":false,"expected value":"300","actual value":"7200","amendments":[{"index":1,"amendment":"[1] setting is not supported"}]}],"simulations":[],"attentions":[],"Notes enabled":["1410736"],"system compliance":false},"messages":[]}`
+
+var outFormatYAML = `---
+"$schema": "file:///usr/share/saptune/schemas/1.2/saptune_note_verify.schema.json"
+argv: "saptune --format yaml note verify"
+pid: 4711
+command: "note verify"
+exit code: 1
+result:
+  verifications:
+    - Note ID: "1410736"
+      Note version: "6"
+      parameter: "net.ipv4.tcp_keepalive_intvl"
+      compliant: true
+      expected value: "75"
+      actual value: "75"
+    - Note ID: "1410736"
+      Note version: "6"
+      parameter: "net.ipv4.tcp_keepalive_time"
+      compliant: false
+      expected value: "300"
+      actual value: "7200"
+      amendments:
+        - index: 1
+          amendment: "[1] setting is not supported"
+  simulations: []
+  attentions: []
+  Notes enabled:
+    - "1410736"
+  system compliance: false
+messages: []
+`
+
+var outFormatCSV = `Note ID,Note version,parameter,compliant,expected value,actual value,amendments
+1410736,6,net.ipv4.tcp_keepalive_intvl,true,75,75,
+1410736,6,net.ipv4.tcp_keepalive_time,false,300,7200,1 [1] setting is not supported
+`
+
+func TestJSONToYAML(t *testing.T) {
+	data, err := jsonToYAML([]byte(outFormatJSON))
+	if err != nil {
+		t.Errorf("unexpected error: '%v'", err)
+	}
+	if string(data) != outFormatYAML {
+		t.Errorf("Test failed, expected:\n'%s'\ngot:\n'%s'", outFormatYAML, string(data))
+	}
+	if _, err := jsonToYAML([]byte(`{"result":`)); err == nil {
+		t.Error("expected an error for an incomplete json document, but got none")
+	}
+}
+
+func TestJSONToCSV(t *testing.T) {
+	data, err := jsonToCSV([]byte(outFormatJSON))
+	if err != nil {
+		t.Errorf("unexpected error: '%v'", err)
+	}
+	if string(data) != outFormatCSV {
+		t.Errorf("Test failed, expected:\n'%s'\ngot:\n'%s'", outFormatCSV, string(data))
+	}
+
+	// no table available - key value pairs
+	status := `{"result":{"services":{"saptune":["enabled","active"],"tuned":""},"tuning state":"compliant","Notes enabled":["1410736","941735"]}}`
+	expected := `key,value
+services.saptune,enabled; active
+services.tuned,
+tuning state,compliant
+Notes enabled,1410736; 941735
+`
+	data, err = jsonToCSV([]byte(status))
+	if err != nil {
+		t.Errorf("unexpected error: '%v'", err)
+	}
+	if string(data) != expected {
+		t.Errorf("Test failed, expected:\n'%s'\ngot:\n'%s'", expected, string(data))
+	}
+}
+
+func TestIsStructuredOut(t *testing.T) {
+	for format, expected := range map[string]bool{"json": true, "yaml": true, "csv": true, "hugo": false, "": false} {
+		saptFlags = map[string]string{"format": format}
+		if IsStructuredOut() != expected {
+			t.Errorf("Test failed for format '%s', expected '%v', got '%v'", format, expected, !expected)
+		}
+	}
+	saptFlags = map[string]string{}
+}
@@ -0,0 +1,143 @@
+package system
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// promTextfileName is the name of the file written to the textfile
+// collector directory of the prometheus node_exporter
+var promTextfileName = "saptune.prom"
+
+// promLabelEscaper escapes the characters not allowed in a label value
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// jsonToPrometheus converts the result of a 'verify' command and the
+// additional system information into the prometheus text exposition format
+func jsonToPrometheus(entry JEntry, info JMetricsInfo) []byte {
+	buf := bytes.Buffer{}
+	promMetric(&buf, "saptune_info", "saptune version information", []string{fmt.Sprintf("package_version=\"%s\",configured_version=\"%s\"", promLabel(info.RPMVersion), promLabel(info.SaptuneVersion))}, []float64{1})
+
+	servLabels := []string{}
+	servEnabled := []float64{}
+	servActive := []float64{}
+	for _, serv := range info.Services {
+		servLabels = append(servLabels, fmt.Sprintf("service=\"%s\"", promLabel(serv.Name)))
+		servEnabled = append(servEnabled, promBool(serv.Enabled))
+		servActive = append(servActive, promBool(serv.Active))
+	}
+	promMetric(&buf, "saptune_service_enabled", "systemd service is enabled (1) or not (0)", servLabels, servEnabled)
+	promMetric(&buf, "saptune_service_active", "systemd service is active (1) or not (0)", servLabels, servActive)
+
+	promMetric(&buf, "saptune_staging_enabled", "staging is enabled (1) or not (0)", []string{""}, []float64{promBool(info.StagingEnabled)})
+	promMetric(&buf, "saptune_staged_notes", "number of Notes in the staging area", []string{""}, []float64{float64(len(info.StagedNotes))})
+	promMetric(&buf, "saptune_staged_solutions", "number of Solutions in the staging area", []string{""}, []float64{float64(len(info.StagedSols))})
+
+	if res, ok := entry.CmdResult.(JPNotes); ok {
+		promVerifyMetrics(&buf, res)
+	}
+	promMetric(&buf, "saptune_verify_exit_code", "exit code of the saptune verify command", []string{""}, []float64{float64(entry.CmdRet)})
+	promMetric(&buf, "saptune_verify_timestamp_seconds", "time of the last saptune verify run", []string{""}, []float64{promTimestamp(entry.Created)})
+	return buf.Bytes()
+}
+
+// promTimestamp returns the time of the verify run, which is the creation
+// time of the json entry, in seconds since the epoch
+func promTimestamp(created string) float64 {
+	stamp, err := time.ParseInLocation(jCreatedFormat, created, time.Local)
+	if err != nil {
+		stamp = time.Now()
+	}
+	return float64(stamp.Unix())
+}
+
+// promVerifyMetrics adds the compliance metrics of the Notes and their
+// parameters. Parameters, which are not applicable on the system, are
+// skipped. A Note is non-compliant, if one of its own parameters is
+// non-compliant
+func promVerifyMetrics(buf *bytes.Buffer, res JPNotes) {
+	noteLabels := []string{}
+	noteCompliant := []float64{}
+	noteIdx := make(map[string]int)
+	paramLabels := []string{}
+	paramCompliant := []float64{}
+	sysCompliant := res.SysCompliance != nil && *res.SysCompliance
+	for _, line := range res.Verifications {
+		idx, ok := noteIdx[line.NoteID]
+		if !ok {
+			idx = len(noteLabels)
+			noteIdx[line.NoteID] = idx
+			noteLabels = append(noteLabels, fmt.Sprintf("note_id=\"%s\",note_version=\"%s\"", promLabel(line.NoteID), promLabel(line.NoteVers)))
+			noteCompliant = append(noteCompliant, 1)
+		}
+		if line.Compliant == nil {
+			continue
+		}
+		if !*line.Compliant {
+			noteCompliant[idx] = 0
+		}
+		paramLabels = append(paramLabels, fmt.Sprintf("note_id=\"%s\",parameter=\"%s\"", promLabel(line.NoteID), promLabel(line.Parameter)))
+		paramCompliant = append(paramCompliant, promBool(*line.Compliant))
+	}
+	promMetric(buf, "saptune_note_compliant", "Note is compliant (1) or not (0)", noteLabels, noteCompliant)
+	promMetric(buf, "saptune_parameter_compliant", "parameter of a Note is compliant (1) or not (0)", paramLabels, paramCompliant)
+	if res.SysCompliance != nil {
+		promMetric(buf, "saptune_system_compliant", "system is compliant (1) or not (0) to all verified Notes", []string{""}, []float64{promBool(sysCompliant)})
+	}
+}
+
+// promMetric writes a gauge metric with its help and type lines followed by
+// one sample per label set
+func promMetric(buf *bytes.Buffer, name, help string, labels []string, values []float64) {
+	if len(labels) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s gauge\n", name)
+	for i, label := range labels {
+		if label != "" {
+			label = "{" + label + "}"
+		}
+		fmt.Fprintf(buf, "%s%s %s\n", name, label, strconv.FormatFloat(values[i], 'f', -1, 64))
+	}
+}
+
+// promLabel escapes a label value
+func promLabel(val string) string {
+	return promLabelEscaper.Replace(val)
+}
+
+// promBool converts a bool into a gauge value
+func promBool(val bool) float64 {
+	if val {
+		return 1
+	}
+	return 0
+}
+
+// writePromTextfile writes the metrics to the textfile collector directory
+// of the prometheus node_exporter, if configured.
+// The file is written to a temporary file first and then renamed, so that
+// the node_exporter never reads a partially written file
+func writePromTextfile(dir string, data []byte) error {
+	if dir == "" {
+		return nil
+	}
+	promFile := path.Join(dir, promTextfileName)
+	tmpFile := fmt.Sprintf("%s.%d.tmp", promFile, os.Getpid())
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		ErrorLog("Problems writing prometheus textfile '%s' - %v", tmpFile, err)
+		return err
+	}
+	if err := os.Rename(tmpFile, promFile); err != nil {
+		os.Remove(tmpFile)
+		ErrorLog("Problems writing prometheus textfile '%s' - %v", promFile, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJsonToPrometheus(t *testing.T) {
+	yes := true
+	no := false
+	act := "7200"
+	entry := JEntry{
+		Cmd:     "verify applied",
+		Created: "2024-05-13 10:20:30.123",
+		CmdRet:  1,
+		CmdResult: JPNotes{
+			Verifications: []JPNotesLine{
+				{NoteID: "1410736", NoteVers: "6", Parameter: "net.ipv4.tcp_keepalive_intvl", Compliant: &yes},
+				{NoteID: "1410736", NoteVers: "6", Parameter: "net.ipv4.tcp_keepalive_time", Compliant: &no, ActValue: &act},
+				{NoteID: "941735", NoteVers: "11", Parameter: "energy_perf_bias"},
+			},
+			SysCompliance: &no,
+		},
+	}
+	info := JMetricsInfo{
+		Services:       []JMetricsServ{{Name: "saptune.service", Enabled: true, Active: true}, {Name: "tuned.service"}},
+		StagingEnabled: true,
+		StagedNotes:    []string{"1410736", "941735"},
+		SaptuneVersion: "3",
+		RPMVersion:     "3.1.0",
+	}
+	data := string(jsonToPrometheus(entry, info))
+	for _, expected := range []string{
+		"# TYPE saptune_info gauge\nsaptune_info{package_version=\"3.1.0\",configured_version=\"3\"} 1\n",
+		"saptune_service_enabled{service=\"saptune.service\"} 1\nsaptune_service_enabled{service=\"tuned.service\"} 0\n",
+		"saptune_service_active{service=\"saptune.service\"} 1\n",
+		"saptune_staging_enabled 1\n",
+		"saptune_staged_notes 2\n",
+		"saptune_staged_solutions 0\n",
+		"saptune_note_compliant{note_id=\"1410736\",note_version=\"6\"} 0\nsaptune_note_compliant{note_id=\"941735\",note_version=\"11\"} 1\n",
+		"saptune_parameter_compliant{note_id=\"1410736\",parameter=\"net.ipv4.tcp_keepalive_intvl\"} 1\nsaptune_parameter_compliant{note_id=\"1410736\",parameter=\"net.ipv4.tcp_keepalive_time\"} 0\n# HELP",
+		"saptune_system_compliant 0\n",
+		"saptune_verify_exit_code 1\n",
+	} {
+		if !strings.Contains(data, expected) {
+			t.Errorf("missing '%s' in metrics:\n%s", expected, data)
+		}
+	}
+	stamp, _ := time.ParseInLocation("2006-01-02 15:04:05", "2024-05-13 10:20:30", time.Local)
+	if !strings.Contains(data, fmt.Sprintf("saptune_verify_timestamp_seconds %d\n", stamp.Unix())) {
+		t.Errorf("wrong timestamp, expected the time of the verify run:\n%s", data)
+	}
+	if strings.Contains(data, "energy_perf_bias") {
+		t.Errorf("not applicable parameter should be skipped:\n%s", data)
+	}
+	// the Note compliance does not depend on the system compliance
+	res := entry.CmdResult.(JPNotes)
+	res.SysCompliance = &yes
+	entry.CmdResult = res
+	data = string(jsonToPrometheus(entry, info))
+	if !strings.Contains(data, "saptune_note_compliant{note_id=\"1410736\",note_version=\"6\"} 0\n") || !strings.Contains(data, "saptune_system_compliant 1\n") {
+		t.Errorf("Note with a non-compliant parameter should be reported as non-compliant:\n%s", data)
+	}
+	if promLabel("a\"b\\c") != `a\"b\\c` {
+		t.Errorf("wrong label escaping: '%s'", promLabel("a\"b\\c"))
+	}
+}
+
+func TestWritePromTextfile(t *testing.T) {
+	if err := writePromTextfile("", []byte("test")); err != nil {
+		t.Errorf("unexpected error for empty directory: '%v'", err)
+	}
+	dir := t.TempDir()
+	if err := writePromTextfile(dir, []byte("saptune_staging_enabled 0\n")); err != nil {
+		t.Errorf("unexpected error: '%v'", err)
+	}
+	content, err := os.ReadFile(path.Join(dir, promTextfileName))
+	if err != nil || string(content) != "saptune_staging_enabled 0\n" {
+		t.Errorf("wrong textfile content '%s' - '%v'", string(content), err)
+	}
+	if err := writePromTextfile("/dir_does_not_exist", []byte("test")); err == nil {
+		t.Error("expected an error for a not existing directory, but got none")
+	}
+}
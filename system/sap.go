@@ -0,0 +1,157 @@
+package system
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// constant definitions
+const (
+	// types of the detected SAP components
+	SAPCompHANA          = "HANA"
+	SAPCompABAP          = "ABAP"
+	SAPCompJava          = "JAVA"
+	SAPCompWebDispatcher = "WEBDISPATCHER"
+	SAPCompMaxDB         = "MAXDB"
+	SAPCompASE           = "ASE"
+	SAPCompBOBJ          = "BOBJ"
+)
+
+// SAPRootDir is the root directory of the SAP systems (/usr/sap/<SID>)
+var SAPRootDir = "/usr/sap/"
+
+// SAPServicesFile lists the sapstartsrv services of all SAP instances
+var SAPServicesFile = "/usr/sap/sapservices"
+
+// SAPDBDir is the installation directory of MaxDB
+var SAPDBDir = "/sapdb/"
+
+// SybaseDir is the installation directory of SAP ASE
+var SybaseDir = "/sybase/"
+
+// isSAPSID matches a SAP system ID
+var isSAPSID = regexp.MustCompile(`^[A-Z][A-Z0-9]{2}$`)
+
+// isSAPInstance matches the name of an instance directory (e.g. HDB00,
+// DVEBMGS01, ASCS02) and splits it into instance type and number
+var isSAPInstance = regexp.MustCompile(`^(HDB|DVEBMGS|D|ASCS|ERS|J|SCS|W)(\d{2})$`)
+
+// isSAPServicesProfile matches the instance profile in a line of the
+// sapservices file (pf=/usr/sap/<SID>/SYS/profile/<SID>_<INSTANCE>_<host>)
+var isSAPServicesProfile = regexp.MustCompile(`pf=\S*/([A-Z][A-Z0-9]{2})_([A-Z]+\d{2})_\S+`)
+
+// sapInstanceTypes maps the instance types to the SAP component types.
+// Diagnostics agents (SMDA) are not relevant for the tuning
+var sapInstanceTypes = map[string]string{
+	"HDB":     SAPCompHANA,
+	"DVEBMGS": SAPCompABAP,
+	"D":       SAPCompABAP,
+	"ASCS":    SAPCompABAP,
+	"ERS":     SAPCompABAP,
+	"J":       SAPCompJava,
+	"SCS":     SAPCompJava,
+	"W":       SAPCompWebDispatcher,
+}
+
+// DetectSAPComponents inspects the host for installed SAP components - the
+// instance directories in /usr/sap/<SID>, the instances registered in
+// /usr/sap/sapservices and the installations of HANA, MaxDB, SAP ASE and
+// SAP BusinessObjects.
+// The components are returned sorted by SID, instance and type
+func DetectSAPComponents() []JSAPComponent {
+	found := make(map[JSAPComponent]bool)
+	comps := []JSAPComponent{}
+	addComp := func(comp JSAPComponent) {
+		key := comp
+		key.Source = ""
+		if !found[key] {
+			found[key] = true
+			comps = append(comps, comp)
+		}
+	}
+
+	// instance directories /usr/sap/<SID>/<INSTANCE>
+	sids, _ := os.ReadDir(SAPRootDir)
+	for _, sid := range sids {
+		if !sid.IsDir() || !isSAPSID.MatchString(sid.Name()) {
+			continue
+		}
+		sidDir := path.Join(SAPRootDir, sid.Name())
+		if _, err := os.Stat(path.Join(sidDir, "sap_bobj")); err == nil {
+			addComp(JSAPComponent{SID: sid.Name(), Type: SAPCompBOBJ, Source: path.Join(sidDir, "sap_bobj")})
+		}
+		instances, _ := os.ReadDir(sidDir)
+		for _, inst := range instances {
+			if comp, ok := sapInstanceComponent(sid.Name(), inst.Name()); ok && inst.IsDir() {
+				comp.Source = path.Join(sidDir, inst.Name())
+				if comp.Type == SAPCompHANA {
+					// prefer the HANA binary as evidence
+					binary := path.Join(comp.Source, "exe", "hdbindexserver")
+					if _, err := os.Stat(binary); err == nil {
+						comp.Source = binary
+					}
+				}
+				addComp(comp)
+			}
+		}
+	}
+
+	// instances registered in /usr/sap/sapservices
+	if sapServices, err := os.Open(SAPServicesFile); err == nil {
+		scanner := bufio.NewScanner(sapServices)
+		for scanner.Scan() {
+			for _, match := range isSAPServicesProfile.FindAllStringSubmatch(scanner.Text(), -1) {
+				if comp, ok := sapInstanceComponent(match[1], match[2]); ok {
+					comp.Source = SAPServicesFile
+					addComp(comp)
+				}
+			}
+		}
+		sapServices.Close()
+	}
+
+	// MaxDB - /sapdb/<SID>/db or the global MaxDB programs
+	maxdbs, _ := filepath.Glob(path.Join(SAPDBDir, "*", "db"))
+	for _, dbDir := range maxdbs {
+		if sid := path.Base(path.Dir(dbDir)); isSAPSID.MatchString(sid) {
+			addComp(JSAPComponent{SID: sid, Type: SAPCompMaxDB, Source: dbDir})
+		}
+	}
+	dbmcli := path.Join(SAPDBDir, "programs", "bin", "dbmcli")
+	if _, err := os.Stat(dbmcli); err == nil && len(maxdbs) == 0 {
+		addComp(JSAPComponent{Type: SAPCompMaxDB, Source: dbmcli})
+	}
+
+	// SAP ASE - /sybase/<SID>/ASE-<release>
+	ases, _ := filepath.Glob(path.Join(SybaseDir, "*", "ASE-*"))
+	for _, aseDir := range ases {
+		if sid := path.Base(path.Dir(aseDir)); isSAPSID.MatchString(sid) {
+			addComp(JSAPComponent{SID: sid, Type: SAPCompASE, Source: aseDir})
+		}
+	}
+
+	sort.SliceStable(comps, func(i, j int) bool {
+		if comps[i].SID != comps[j].SID {
+			return comps[i].SID < comps[j].SID
+		}
+		if comps[i].Instance != comps[j].Instance {
+			return comps[i].Instance < comps[j].Instance
+		}
+		return comps[i].Type < comps[j].Type
+	})
+	return comps
+}
+
+// sapInstanceComponent returns the SAP component of an instance name
+// (e.g. HDB00) of a SAP system
+func sapInstanceComponent(sid, instance string) (JSAPComponent, bool) {
+	match := isSAPInstance.FindStringSubmatch(instance)
+	if match == nil {
+		return JSAPComponent{}, false
+	}
+	return JSAPComponent{SID: sid, Instance: instance, Type: sapInstanceTypes[match[1]]}, true
+}
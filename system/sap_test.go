@@ -0,0 +1,60 @@
+package system
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestDetectSAPComponents(t *testing.T) {
+	oldSAPRootDir := SAPRootDir
+	oldSAPServicesFile := SAPServicesFile
+	oldSAPDBDir := SAPDBDir
+	oldSybaseDir := SybaseDir
+	defer func() {
+		SAPRootDir = oldSAPRootDir
+		SAPServicesFile = oldSAPServicesFile
+		SAPDBDir = oldSAPDBDir
+		SybaseDir = oldSybaseDir
+	}()
+	tstDir := "/tmp/saptune_sapdetect_test/"
+	defer os.RemoveAll(tstDir)
+	SAPRootDir = tstDir + "usr/sap/"
+	SAPServicesFile = SAPRootDir + "sapservices"
+	SAPDBDir = tstDir + "sapdb/"
+	SybaseDir = tstDir + "sybase/"
+
+	// nothing installed
+	if comps := DetectSAPComponents(); len(comps) != 0 {
+		t.Errorf("expected no components, got '%+v'", comps)
+	}
+
+	for _, dir := range []string{"HA0/HDB00/exe", "HA0/SYS", "NW1/ASCS01", "NW1/D02", "DAA/SMDA97", "trans", "BO1/sap_bobj", "sybase"} {
+		_ = os.MkdirAll(path.Join(SAPRootDir, dir), 0755)
+	}
+	_ = os.WriteFile(path.Join(SAPRootDir, "HA0/HDB00/exe/hdbindexserver"), []byte{}, 0755)
+	services := "LD_LIBRARY_PATH=/usr/sap/NW1/ASCS01/exe:$LD_LIBRARY_PATH;export LD_LIBRARY_PATH;/usr/sap/NW1/ASCS01/exe/sapstartsrv pf=/usr/sap/NW1/SYS/profile/NW1_ASCS01_host1 -D -u nw1adm\n" +
+		"systemctl --no-ask-password start SAPJ01_10 # sapstartsrv pf=/usr/sap/J01/SYS/profile/J01_J10_host1\n"
+	_ = os.WriteFile(SAPServicesFile, []byte(services), 0644)
+	_ = os.MkdirAll(path.Join(SAPDBDir, "MX1/db"), 0755)
+	_ = os.MkdirAll(path.Join(SybaseDir, "AS1/ASE-16_0"), 0755)
+
+	comps := DetectSAPComponents()
+	expected := []JSAPComponent{
+		{SID: "AS1", Type: SAPCompASE, Source: path.Join(SybaseDir, "AS1/ASE-16_0")},
+		{SID: "BO1", Type: SAPCompBOBJ, Source: path.Join(SAPRootDir, "BO1/sap_bobj")},
+		{SID: "HA0", Instance: "HDB00", Type: SAPCompHANA, Source: path.Join(SAPRootDir, "HA0/HDB00/exe/hdbindexserver")},
+		{SID: "J01", Instance: "J10", Type: SAPCompJava, Source: SAPServicesFile},
+		{SID: "MX1", Type: SAPCompMaxDB, Source: path.Join(SAPDBDir, "MX1/db")},
+		{SID: "NW1", Instance: "ASCS01", Type: SAPCompABAP, Source: path.Join(SAPRootDir, "NW1/ASCS01")},
+		{SID: "NW1", Instance: "D02", Type: SAPCompABAP, Source: path.Join(SAPRootDir, "NW1/D02")},
+	}
+	if len(comps) != len(expected) {
+		t.Fatalf("got '%+v', expected '%+v'", comps, expected)
+	}
+	for i, comp := range comps {
+		if comp != expected[i] {
+			t.Errorf("got '%+v', expected '%+v'", comp, expected[i])
+		}
+	}
+}
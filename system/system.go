@@ -73,6 +73,10 @@ func SaptuneConfigFile() string {
 	return "/etc/sysconfig/saptune"
 }
 
+// SaptuneConfigDropInDir is the directory of the admin owned drop-in files
+// '*.conf', which are merged over the saptune configuration file
+var SaptuneConfigDropInDir = "/etc/saptune/saptune.conf.d"
+
 // SaptuneConfigTemplate returns the name of the template file for the
 // saptune configuration file
 // /usr/share/fillup-templates/sysconfig.saptune in SLE12 and SLE15
@@ -287,10 +291,11 @@ func OutIsTerm(writer *os.File) bool {
 }
 
 // InitOut initializes the various output methodes
-// currently only json and screen are supported
+// currently screen and the machine readable formats json, yaml, csv, junit
+// and prometheus are supported
 func InitOut(logSwitch map[string]string) {
-	if GetFlagVal("format") == "json" {
-		// if writing json format, switch off
+	if IsStructuredOut() {
+		// if writing a machine readable format, switch off
 		// the stdout and stderr output of the log messages
 		logSwitch["verbose"] = "off"
 		logSwitch["error"] = "off"
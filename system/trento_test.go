@@ -15,7 +15,12 @@ func TestCheckAndSetTrento(t *testing.T) {
 
 	oldTrentoAgentFile := trentoAgentFile
 	defer func() { trentoAgentFile = oldTrentoAgentFile }()
-	trentoAgentFile = path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/testdata/trento_agent.yaml")
+	// the agent file gets changed, so work on a copy
+	trentoAgentFile = "/tmp/saptune_trento_agent.yaml"
+	defer os.Remove(trentoAgentFile)
+	if err := CopyFile(path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/testdata/trento_agent.yaml"), trentoAgentFile); err != nil {
+		t.Fatal(err)
+	}
 	configVal = "600"
 	err = CheckAndSetTrento("TrentoASDP", configVal, true)
 	if err != nil {
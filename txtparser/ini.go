@@ -44,6 +44,7 @@ type INIEntry struct {
 	Key      string
 	Operator Operator
 	Value    string
+	Source   string `json:",omitempty"` // override file, which supplied the entry
 }
 
 // INIFile contains all key-value pairs of an INI file.
@@ -151,7 +152,38 @@ func ParseINIFile(fileName string, autoCreate bool) (*INIFile, error) {
 	return ParseINI(string(content)), nil
 }
 
+// ParseINIFileStrict reads the content of the configuration file and
+// parses it in strict mode (see ParseINIStrict)
+func ParseINIFileStrict(fileName string, sections []string) (*INIFile, error) {
+	content, err := system.ReadConfigFile(fileName, false)
+	if err != nil {
+		return nil, err
+	}
+	return ParseINIStrict(fileName, string(content), sections)
+}
+
+// ParseINIStrict is the strict mode of ParseINI. Instead of ignoring
+// malformed lines and sections, the content is checked with LintINI and
+// all errors are returned as ParseErrors containing file name, line
+// number, section and reason. Only if no error is found, the content is
+// parsed by ParseINI. Warnings (e.g. deprecated syntax) do not prevent the
+// parsing, they are logged.
+// sections are the section names valid for the type of the definition file
+func ParseINIStrict(fileName, input string, sections []string) (*INIFile, error) {
+	res := LintINI(fileName, input, sections)
+	errs, warnings := SplitLintProblems(res.Problems)
+	for _, warning := range warnings {
+		system.WarningLog("%s", warning.String())
+	}
+	if len(errs) != 0 {
+		return nil, ParseErrors(errs)
+	}
+	return ParseINI(input), nil
+}
+
 // ParseINI parse the content of the configuration file
+// Malformed lines and sections are skipped (lenient mode), use
+// ParseINIStrict to get them reported
 func ParseINI(input string) *INIFile {
 	ret := &INIFile{
 		AllValues: make([]INIEntry, 0, 64),
@@ -272,16 +304,17 @@ func ParseINI(input string) *INIFile {
 	// save reminder section, if available
 	if reminder != "" {
 		// Save previous section
-		if currentSection != "" {
+		if currentSection != "" && !skipSection {
 			ret.KeyValue[currentSection] = currentEntriesMap
 			ret.AllValues = append(ret.AllValues, currentEntriesArray...)
 		}
 		// write the reminder section data
 		currentEntriesArray, currentEntriesMap, currentSection = writeReminderSectionData(reminder)
+		skipSection = false
 	}
 
 	// Save last section
-	if currentSection != "" {
+	if currentSection != "" && !skipSection {
 		ret.KeyValue[currentSection] = currentEntriesMap
 		ret.AllValues = append(ret.AllValues, currentEntriesArray...)
 	}
@@ -266,6 +266,77 @@ func TestParseINI(t *testing.T) {
 	os.Remove(devFileName)
 }
 
+func TestParseINIStrict(t *testing.T) {
+	sections := []string{"version", "sysctl", "vm"}
+	valid := `[version]
+VERSION=1
+DATE=19.10.2026
+DESCRIPTION=strict test
+REFERENCES=https://www.suse.com
+
+[vm]
+THP=never
+`
+	ini, err := ParseINIStrict("/tmp/strict", valid, sections)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if ini == nil || ini.KeyValue["vm"]["THP"].Value != "never" {
+		t.Errorf("wrong parse result: '%+v'", ini)
+	}
+
+	invalid := valid + `
+[vm]
+THP never
+
+[unknown]
+key=val
+`
+	ini, err = ParseINIStrict("/tmp/strict", invalid, sections)
+	if ini != nil {
+		t.Errorf("got a parse result for invalid content: '%+v'", ini)
+	}
+	perrs, ok := err.(ParseErrors)
+	if !ok {
+		t.Fatalf("expected ParseErrors, got: '%v'", err)
+	}
+	exp := ParseErrors{
+		{File: "/tmp/strict", Line: 11, Section: "vm", Reason: "line 'THP never' is not a valid 'parameter operator value' entry"},
+		{File: "/tmp/strict", Line: 13, Section: "", Reason: "unknown section '[unknown]', valid sections are: version, sysctl, vm"},
+	}
+	if !reflect.DeepEqual(perrs, exp) {
+		t.Errorf("got: '%+v', expected: '%+v'", perrs, exp)
+	}
+	expMsg := "/tmp/strict:11: [vm] line 'THP never' is not a valid 'parameter operator value' entry\n/tmp/strict:13: unknown section '[unknown]', valid sections are: version, sysctl, vm"
+	if err.Error() != expMsg {
+		t.Errorf("got: '%s', expected: '%s'", err.Error(), expMsg)
+	}
+	// warnings do not prevent the parsing
+	deprecated := `[version]
+# SAP-NOTE=1234 CATEGORY=LINUX VERSION=1 DATE=19.10.2026 NAME="strict test"
+
+[vm]
+THP=never
+`
+	ini, err = ParseINIStrict("/tmp/strict", deprecated, sections)
+	if err != nil || ini == nil || ini.KeyValue["vm"]["THP"].Value != "never" {
+		t.Errorf("expected a parse result for content with warnings, got '%+v' - '%v'", ini, err)
+	}
+	res := LintINI("/tmp/strict", deprecated, sections)
+	errs, warnings := SplitLintProblems(res.Problems)
+	if len(errs) != 0 || len(warnings) != 1 || warnings[0].String() != "/tmp/strict:1: [version] warning: old style version section syntax is deprecated. Please adapt" {
+		t.Errorf("expected one deprecation warning, got '%+v' - '%+v'", errs, warnings)
+	}
+	// lenient mode ignores the problems
+	if ini := ParseINI(invalid); ini.KeyValue["vm"] == nil {
+		t.Errorf("wrong lenient parse result: '%+v'", ini)
+	}
+
+	if _, err := ParseINIFileStrict("/file_does_not_exist", sections); err == nil {
+		t.Error("expected an error for a not existing file")
+	}
+}
+
 func TestGetINIFileDescriptiveName(t *testing.T) {
 	str := GetINIFileDescriptiveName(fileName)
 	if str != descName {
@@ -396,3 +467,24 @@ func TestGetSysctlExcludes(t *testing.T) {
 	t.Log(excludeDirs)
 	excludeDirs = excludeDirsOrg
 }
+
+func TestParseINISkippedLastSection(t *testing.T) {
+	// the last section does not match the os release, so it is skipped
+	// and the entries of the section before are not saved twice
+	iniCont := "[vm]\nTHP = always\n[vm:os=99]\nTHP = never\n"
+	expected := []INIEntry{
+		{Section: "vm", Key: "THP", Operator: "=", Value: "always"},
+	}
+	actualINI := ParseINI(iniCont)
+	if !reflect.DeepEqual(actualINI.AllValues, expected) {
+		t.Errorf("got: %+v, expected: %+v\n", actualINI.AllValues, expected)
+	}
+	if _, ok := actualINI.KeyValue["vm:os=99"]; ok || len(actualINI.KeyValue) != 1 {
+		t.Errorf("skipped section saved - '%+v'\n", actualINI.KeyValue)
+	}
+	// the reminder section is saved, even if the last section is skipped
+	actualINI = ParseINI("[reminder]\n# do not forget\n[vm:os=99]\nTHP = never\n")
+	if len(actualINI.AllValues) != 1 || actualINI.AllValues[0].Section != "reminder" || len(actualINI.KeyValue) != 1 {
+		t.Errorf("unexpected result '%+v'\n", actualINI)
+	}
+}
@@ -0,0 +1,272 @@
+package txtparser
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// regDocEntry splits a parameter line into indentation, parameter name,
+// operator with the surrounding white spaces and the value
+var regDocEntry = regexp.MustCompile(`^(\s*)([\w.+_-]+)(\s*[<=>]+\s*)(.*)$`)
+
+// regDocComment matches the start of an inline comment
+var regDocComment = regexp.MustCompile(`\s#[^#]|"\s#[^#]`)
+
+// INIDocLine is a single line of an INIDocument
+type INIDocLine struct {
+	Text     string // original text of the line
+	Header   string // header of the section (name including tags) the line belongs to
+	Key      string // parameter name, empty for headers, comments and blank lines
+	IsHeader bool   // line is a section header
+}
+
+// INIDocument is a lossless model of a definition file (Note, solution or
+// override file). In contrast to ParseINI it preserves comments, blank
+// lines, section headers with tags and the order of the entries, so single
+// entries can be changed and the file can be written back with a minimal
+// diff. All lines, which are not changed, are written back unmodified
+type INIDocument struct {
+	Lines []INIDocLine
+}
+
+// ParseINIDocument creates the document model of the content of a
+// definition file
+func ParseINIDocument(content string) *INIDocument {
+	doc := &INIDocument{}
+	header := ""
+	for _, text := range strings.Split(content, "\n") {
+		line := INIDocLine{Text: text, Header: header}
+		trimmed := strings.TrimSpace(text)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			header = strings.TrimSuffix(strings.TrimPrefix(trimmed, "["), "]")
+			line.Header = header
+			line.IsHeader = true
+		} else {
+			line.Key = iniLineKey(text)
+		}
+		doc.Lines = append(doc.Lines, line)
+	}
+	return doc
+}
+
+// ReadINIDocument reads a definition file and creates its document model
+func ReadINIDocument(fileName string) (*INIDocument, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return ParseINIDocument(string(content)), nil
+}
+
+// String returns the content of the document
+func (doc *INIDocument) String() string {
+	texts := make([]string, 0, len(doc.Lines))
+	for _, line := range doc.Lines {
+		texts = append(texts, line.Text)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// WriteFile writes the document to the file. The file is only written, if
+// its content differs from the document.
+// It returns, if the file was written
+func (doc *INIDocument) WriteFile(fileName string, perm os.FileMode) (bool, error) {
+	content := doc.String()
+	if cont, err := os.ReadFile(fileName); err == nil && string(cont) == content {
+		return false, nil
+	}
+	if err := os.WriteFile(fileName, []byte(content), perm); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Sections returns the section headers (name including tags) of the
+// document in the order of the file
+func (doc *INIDocument) Sections() []string {
+	headers := []string{}
+	for _, line := range doc.Lines {
+		if line.IsHeader {
+			headers = append(headers, line.Header)
+		}
+	}
+	return headers
+}
+
+// Keys returns the parameter names of a section in the order of the file
+func (doc *INIDocument) Keys(section string) []string {
+	keys := []string{}
+	start, end := doc.sectionLines(section)
+	for idx := start + 1; start >= 0 && idx < end; idx++ {
+		if doc.Lines[idx].Key != "" {
+			keys = append(keys, doc.Lines[idx].Key)
+		}
+	}
+	return keys
+}
+
+// Get returns the value of a parameter of a section and if the parameter
+// was found. Quotes and inline comments are removed from the value
+func (doc *INIDocument) Get(section, key string) (string, bool) {
+	idx := doc.entryLine(section, key)
+	if idx < 0 {
+		return "", false
+	}
+	_, _, value, _ := splitDocEntry(doc.Lines[idx].Text)
+	return strings.Trim(strings.TrimSpace(value), `"'`), true
+}
+
+// Set sets the value of a parameter in the section with the header
+// 'section' (section name including the section tags). An existing entry
+// is replaced in place, the indentation, the white spaces around the
+// operator and an inline comment of the line are preserved. Otherwise the
+// entry is added after the last parameter of the section or a new section
+// is appended to the document.
+// Values containing white spaces are quoted
+func (doc *INIDocument) Set(section, key, operator, value string) {
+	if strings.ContainsAny(value, " \t") {
+		value = fmt.Sprintf("\"%s\"", value)
+	}
+	if idx := doc.entryLine(section, key); idx >= 0 {
+		doc.Lines[idx].Text = setDocEntry(doc.Lines[idx].Text, key, operator, value)
+		return
+	}
+	entry := INIDocLine{Text: key + operator + value, Header: section, Key: key}
+	start, end := doc.sectionLines(section)
+	if start < 0 {
+		// drop trailing empty lines and append the new section
+		last := len(doc.Lines)
+		for last > 0 && doc.Lines[last-1].Text == "" {
+			last--
+		}
+		doc.Lines = doc.Lines[:last]
+		if last > 0 {
+			doc.Lines = append(doc.Lines, INIDocLine{Header: doc.Lines[last-1].Header})
+		}
+		doc.Lines = append(doc.Lines, INIDocLine{Text: "[" + section + "]", Header: section, IsHeader: true}, entry, INIDocLine{Header: section})
+		return
+	}
+	last := start
+	for idx := start + 1; idx < end; idx++ {
+		if strings.TrimSpace(doc.Lines[idx].Text) != "" {
+			last = idx
+		}
+	}
+	doc.Lines = append(doc.Lines[:last+1], append([]INIDocLine{entry}, doc.Lines[last+1:]...)...)
+}
+
+// Delete removes a parameter from the section with the header 'section'.
+// A section without any remaining line is removed together with its
+// trailing blank lines.
+// It returns, if the parameter was found
+func (doc *INIDocument) Delete(section, key string) bool {
+	start, end := doc.sectionLines(section)
+	if start < 0 {
+		return false
+	}
+	found := false
+	empty := true
+	for idx := start + 1; idx < end; idx++ {
+		if doc.Lines[idx].Key == key {
+			doc.Lines = append(doc.Lines[:idx], doc.Lines[idx+1:]...)
+			found = true
+			idx--
+			end--
+			continue
+		}
+		if strings.TrimSpace(doc.Lines[idx].Text) != "" {
+			empty = false
+		}
+	}
+	if found && empty {
+		// remove header and the blank lines of the section
+		doc.Lines = append(doc.Lines[:start], doc.Lines[end:]...)
+	}
+	return found
+}
+
+// sectionLines returns the index of the header line of a section and the
+// index of the first line after the section.
+// The start index is -1, if the section does not exist
+func (doc *INIDocument) sectionLines(section string) (int, int) {
+	start := -1
+	for idx, line := range doc.Lines {
+		if !line.IsHeader {
+			continue
+		}
+		if start >= 0 {
+			return start, idx
+		}
+		if line.Header == section {
+			start = idx
+		}
+	}
+	return start, len(doc.Lines)
+}
+
+// entryLine returns the index of the line of a parameter of a section or
+// -1, if the parameter does not exist
+func (doc *INIDocument) entryLine(section, key string) int {
+	start, end := doc.sectionLines(section)
+	for idx := start + 1; start >= 0 && idx < end; idx++ {
+		if doc.Lines[idx].Key == key {
+			return idx
+		}
+	}
+	return -1
+}
+
+// splitDocEntry splits a parameter line into the part before the value
+// (indentation, parameter name and operator with the surrounding white
+// spaces), the operator, the value and the inline comment including the
+// white spaces in front of it.
+// For lines without operator the prefix is empty and the value contains
+// the line without the comment
+func splitDocEntry(text string) (string, string, string, string) {
+	comment := ""
+	if cut := regDocComment.FindStringIndex(text); cut != nil {
+		pos := cut[0]
+		if text[pos] == '"' {
+			pos++
+		}
+		text, comment = text[:pos], text[pos:]
+		trimmed := strings.TrimRight(text, " \t")
+		text, comment = trimmed, text[len(trimmed):]+comment
+	}
+	match := regDocEntry.FindStringSubmatch(text)
+	if match == nil {
+		return "", "", text, comment
+	}
+	return match[1] + match[2] + match[3], strings.TrimSpace(match[3]), match[4], comment
+}
+
+// setDocEntry replaces the value of a parameter line and keeps the layout
+// of the line. If the operator changes, the new operator is used
+func setDocEntry(text, key, operator, value string) string {
+	prefix, op, _, comment := splitDocEntry(text)
+	if prefix == "" {
+		return key + operator + value + comment
+	}
+	if op != operator {
+		prefix = strings.Replace(prefix, op, operator, 1)
+	}
+	return prefix + value + comment
+}
+
+// iniLineKey returns the parameter name of a line of a definition file.
+// For lines without an operator (e.g. single grub options) the complete
+// line is returned. Comments and empty lines return an empty string
+func iniLineKey(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ""
+	}
+	line = system.StripComment(line, `\s#[^#]|"\s#[^#]`)
+	if kov := RegexKeyOperatorValue.FindStringSubmatch(line); kov != nil && strings.HasPrefix(line, kov[1]) {
+		return kov[1]
+	}
+	return line
+}
@@ -0,0 +1,152 @@
+package txtparser
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+var iniDocExample = `# SAP Note header comment
+[version]
+VERSION=1
+DATE=01.01.2024
+
+[sysctl]
+# swappiness, see SAP Note
+  vm.swappiness = 10   # inline comment
+kernel.shmmni=4096
+
+[grub:csp=aws]
+# explanation
+intel_idle.max_cstate=1
+nosmt
+`
+
+func TestINIDocumentRoundTrip(t *testing.T) {
+	doc := ParseINIDocument(iniDocExample)
+	if got := doc.String(); got != iniDocExample {
+		t.Errorf("got: '%s', expected: '%s'", got, iniDocExample)
+	}
+	for _, fileName := range []string{"/usr/share/saptune/notes/1656250", "/usr/share/saptune/notes/2578899"} {
+		fileName = path.Join(os.Getenv("GOPATH"), "/src/github.com/SUSE/saptune/ospackage", fileName)
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err = ReadINIDocument(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.String() != string(content) {
+			t.Errorf("round trip of '%s' changed the content", fileName)
+		}
+	}
+	if _, err := ReadINIDocument("/file_does_not_exist"); err == nil {
+		t.Error("expected an error for a not existing file")
+	}
+}
+
+func TestINIDocumentAccess(t *testing.T) {
+	doc := ParseINIDocument(iniDocExample)
+	if got := doc.Sections(); !reflect.DeepEqual(got, []string{"version", "sysctl", "grub:csp=aws"}) {
+		t.Errorf("got: '%v'", got)
+	}
+	if got := doc.Keys("sysctl"); !reflect.DeepEqual(got, []string{"vm.swappiness", "kernel.shmmni"}) {
+		t.Errorf("got: '%v'", got)
+	}
+	if got := doc.Keys("grub:csp=aws"); !reflect.DeepEqual(got, []string{"intel_idle.max_cstate", "nosmt"}) {
+		t.Errorf("got: '%v'", got)
+	}
+	if val, ok := doc.Get("sysctl", "vm.swappiness"); !ok || val != "10" {
+		t.Errorf("got: '%s' - %v", val, ok)
+	}
+	if _, ok := doc.Get("grub", "intel_idle.max_cstate"); ok {
+		t.Error("found parameter in not existing section")
+	}
+}
+
+func TestINIDocumentSet(t *testing.T) {
+	doc := ParseINIDocument(iniDocExample)
+	// layout and inline comment are preserved
+	doc.Set("sysctl", "vm.swappiness", "=", "20")
+	if doc.Lines[7].Text != "  vm.swappiness = 20   # inline comment" {
+		t.Errorf("got: '%s'", doc.Lines[7].Text)
+	}
+	// changed operator
+	doc.Set("sysctl", "kernel.shmmni", ">=", "8192")
+	if doc.Lines[8].Text != "kernel.shmmni>=8192" {
+		t.Errorf("got: '%s'", doc.Lines[8].Text)
+	}
+	// all other lines are unchanged
+	orig := ParseINIDocument(iniDocExample)
+	for idx, line := range doc.Lines {
+		if idx != 7 && idx != 8 && line.Text != orig.Lines[idx].Text {
+			t.Errorf("line %d changed: '%s'", idx+1, line.Text)
+		}
+	}
+	// new entry in existing tagged section
+	doc.Set("grub:csp=aws", "numa_balancing", "=", "disable")
+	if got := doc.Keys("grub:csp=aws"); !reflect.DeepEqual(got, []string{"intel_idle.max_cstate", "nosmt", "numa_balancing"}) {
+		t.Errorf("got: '%v'", got)
+	}
+	// new section
+	doc.Set("limits", "LIMITS", "=", "@sapsys soft nofile 1048576")
+	if val, ok := doc.Get("limits", "LIMITS"); !ok || val != "@sapsys soft nofile 1048576" {
+		t.Errorf("got: '%s' - %v", val, ok)
+	}
+}
+
+func TestINIDocumentDelete(t *testing.T) {
+	doc := ParseINIDocument(iniDocExample)
+	if !doc.Delete("sysctl", "kernel.shmmni") {
+		t.Error("parameter not found")
+	}
+	if doc.Delete("sysctl", "kernel.shmmni") {
+		t.Error("deleted parameter found again")
+	}
+	exp := `# SAP Note header comment
+[version]
+VERSION=1
+DATE=01.01.2024
+
+[grub:csp=aws]
+# explanation
+intel_idle.max_cstate=1
+nosmt
+`
+	doc = ParseINIDocument(iniDocExample)
+	doc.Delete("sysctl", "vm.swappiness")
+	doc.Delete("sysctl", "kernel.shmmni")
+	// comment keeps the section
+	if got := doc.Sections(); !reflect.DeepEqual(got, []string{"version", "sysctl", "grub:csp=aws"}) {
+		t.Errorf("got: '%v'", got)
+	}
+	// section without remaining lines is removed
+	doc = ParseINIDocument(iniDocExample)
+	doc.Lines = append(doc.Lines[:6], doc.Lines[7:]...)
+	doc.Delete("sysctl", "vm.swappiness")
+	doc.Delete("sysctl", "kernel.shmmni")
+	if got := doc.String(); got != exp {
+		t.Errorf("got: '%s', expected: '%s'", got, exp)
+	}
+}
+
+func TestINIDocumentWriteFile(t *testing.T) {
+	fileName := path.Join(os.TempDir(), "saptune_inidoc_test")
+	defer os.Remove(fileName)
+	doc := ParseINIDocument(iniDocExample)
+	if written, err := doc.WriteFile(fileName, 0644); err != nil || !written {
+		t.Errorf("file not written - %v", err)
+	}
+	if written, err := doc.WriteFile(fileName, 0644); err != nil || written {
+		t.Errorf("unchanged file written again - %v", err)
+	}
+	doc.Set("sysctl", "vm.swappiness", "=", "30")
+	if written, err := doc.WriteFile(fileName, 0644); err != nil || !written {
+		t.Errorf("changed file not written - %v", err)
+	}
+	if written, err := doc.WriteFile("/not_existing_dir/file", 0644); err == nil || written {
+		t.Error("expected an error for a not existing directory")
+	}
+}
@@ -0,0 +1,21 @@
+package txtparser
+
+// SetINIEntry sets the value of a parameter in the content of a definition
+// file. The parameter is searched in the section with the header 'section'
+// (section name including the section tags). See INIDocument.Set.
+// It returns the new content
+func SetINIEntry(content, section, key, operator, value string) string {
+	doc := ParseINIDocument(content)
+	doc.Set(section, key, operator, value)
+	return doc.String()
+}
+
+// RemoveINIEntry removes a parameter from the section with the header
+// 'section' in the content of a definition file. A section header without
+// any remaining line is removed too.
+// It returns the new content and if the parameter was found
+func RemoveINIEntry(content, section, key string) (string, bool) {
+	doc := ParseINIDocument(content)
+	found := doc.Delete(section, key)
+	return doc.String(), found
+}
@@ -0,0 +1,84 @@
+package txtparser
+
+import (
+	"testing"
+)
+
+var iniEditExample = `# comment
+[sysctl]
+# explanation
+vm.swappiness = 10
+
+[grub:csp=aws]
+intel_idle.max_cstate=1
+`
+
+func TestSetINIEntry(t *testing.T) {
+	// replace existing entry in place
+	exp := `# comment
+[sysctl]
+# explanation
+vm.swappiness = 20
+
+[grub:csp=aws]
+intel_idle.max_cstate=1
+`
+	if got := SetINIEntry(iniEditExample, "sysctl", "vm.swappiness", "=", "20"); got != exp {
+		t.Errorf("got: '%s', expected: '%s'", got, exp)
+	}
+	// add entry at the end of the section
+	exp = `# comment
+[sysctl]
+# explanation
+vm.swappiness = 10
+vm.dirty_ratio=10
+
+[grub:csp=aws]
+intel_idle.max_cstate=1
+`
+	if got := SetINIEntry(iniEditExample, "sysctl", "vm.dirty_ratio", "=", "10"); got != exp {
+		t.Errorf("got: '%s', expected: '%s'", got, exp)
+	}
+	// add new section, quote values with spaces
+	exp = iniEditExample + `
+[limits]
+LIMITS="@sapsys soft nofile 1048576"
+`
+	if got := SetINIEntry(iniEditExample, "limits", "LIMITS", "=", "@sapsys soft nofile 1048576"); got != exp {
+		t.Errorf("got: '%s', expected: '%s'", got, exp)
+	}
+	// empty content
+	if got := SetINIEntry("", "vm", "THP", "=", "never"); got != "[vm]\nTHP=never\n" {
+		t.Errorf("got: '%s'", got)
+	}
+}
+
+func TestRemoveINIEntry(t *testing.T) {
+	exp := `# comment
+[sysctl]
+# explanation
+
+[grub:csp=aws]
+intel_idle.max_cstate=1
+`
+	got, found := RemoveINIEntry(iniEditExample, "sysctl", "vm.swappiness")
+	if !found || got != exp {
+		t.Errorf("got: '%s' - %v, expected: '%s'", got, found, exp)
+	}
+	// section without remaining lines is removed
+	exp = `# comment
+[sysctl]
+# explanation
+vm.swappiness = 10
+`
+	got, found = RemoveINIEntry(iniEditExample, "grub:csp=aws", "intel_idle.max_cstate")
+	if !found || got != exp {
+		t.Errorf("got: '%s' - %v, expected: '%s'", got, found, exp)
+	}
+	if _, found = RemoveINIEntry(iniEditExample, "grub", "intel_idle.max_cstate"); found {
+		t.Error("found entry in not existing section")
+	}
+	if _, found = RemoveINIEntry(iniEditExample, "sysctl", "vm.dirty_ratio"); found {
+		t.Error("found not existing entry")
+	}
+}
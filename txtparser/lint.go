@@ -0,0 +1,400 @@
+package txtparser
+
+import (
+	"fmt"
+	"github.com/SUSE/saptune/system"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// lintOsTag matches the supported syntax of the 'os' section tag
+// 15-SP6, 16.0, 15-*, 15.*, 15-[2,4-5,7-], 16.[0-2]
+var lintOsTag = regexp.MustCompile(`^(12|15|16)([-.](SP)?\d+|[-.]S?P?\*|[-.]\[(\d*-?\d*)(,\d*-?\d*)*\])?$`)
+
+// lintArchTags are the values of the 'arch' section tag, which can match
+var lintArchTags = []string{"x86_64", "ppc64le"}
+
+// lintCSPTags are the values of the 'csp' section tag, which can match
+var lintCSPTags = []string{system.CSPAzure, system.CSPAWS, system.CSPGoogle, system.CSPOVM, system.CSPAlibaba, system.CSPIBMVPC}
+
+// lintOperators are the operators supported in a 'key operator value' line
+var lintOperators = []string{OperatorLessThan, OperatorLessThanEqual, OperatorMoreThan, OperatorMoreThanEqual, OperatorEqual}
+
+// LintProblem is a problem found in a Note or Solution definition file
+type LintProblem struct {
+	File    string
+	Line    int
+	Section string
+	Reason  string
+	// Warning is true for problems, which do not prevent the use of the
+	// file (e.g. deprecated syntax)
+	Warning bool
+}
+
+// String returns the problem in the format 'file:line: [section] reason'
+// or 'file:line: [section] warning: reason'
+func (prob LintProblem) String() string {
+	sect := ""
+	if prob.Section != "" {
+		sect = fmt.Sprintf("[%s] ", prob.Section)
+	}
+	if prob.Warning {
+		sect = sect + "warning: "
+	}
+	return fmt.Sprintf("%s:%d: %s%s", prob.File, prob.Line, sect, prob.Reason)
+}
+
+// Error returns the problem as error message, so a LintProblem can be used
+// as structured parse error
+func (prob LintProblem) Error() string {
+	return prob.String()
+}
+
+// ParseErrors are the problems found by the strict mode of the INI parser
+// (see ParseINIStrict)
+type ParseErrors []LintProblem
+
+// Error returns all problems, one problem per line
+func (errs ParseErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, prob := range errs {
+		msgs = append(msgs, prob.String())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// LintEntry is a parameter entry of a definition file together with the
+// line number and the section header it was found in
+type LintEntry struct {
+	INIEntry
+	Line int
+	// Header is the complete section header including the tags
+	Header string
+	// Active is true, if the section tags match the running system
+	Active bool
+	// BlockDevs are the block devices matching the section tags
+	BlockDevs []string
+}
+
+// LintResult is the result of the strict parsing of a definition file
+type LintResult struct {
+	Problems []LintProblem
+	Entries  []LintEntry
+	// block device information collected during the lint run. Kept
+	// apart from the block device information used by ParseINI
+	blckCnt  int
+	blockDev []string
+}
+
+// addProblem adds a problem to the lint result
+func (res *LintResult) addProblem(file string, line int, section, format string, args ...interface{}) {
+	res.Problems = append(res.Problems, LintProblem{File: file, Line: line, Section: section, Reason: fmt.Sprintf(format, args...)})
+}
+
+// addWarning adds a warning to the lint result
+func (res *LintResult) addWarning(file string, line int, section, format string, args ...interface{}) {
+	res.Problems = append(res.Problems, LintProblem{File: file, Line: line, Section: section, Reason: fmt.Sprintf(format, args...), Warning: true})
+}
+
+// SplitLintProblems separates the errors from the warnings
+func SplitLintProblems(problems []LintProblem) ([]LintProblem, []LintProblem) {
+	errs := []LintProblem{}
+	warnings := []LintProblem{}
+	for _, prob := range problems {
+		if prob.Warning {
+			warnings = append(warnings, prob)
+		} else {
+			errs = append(errs, prob)
+		}
+	}
+	return errs, warnings
+}
+
+// LintINIFile reads a definition file and checks its content with
+// LintINI
+func LintINIFile(fileName string, sections []string) (*LintResult, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return LintINI(fileName, string(content), sections), nil
+}
+
+// LintINI is the strict mode of ParseINI. Instead of skipping irregular
+// lines and sections with a log message, all problems are reported with
+// the line number:
+// unknown sections, malformed section headers and tags, tags which can
+// never match, lines without a valid 'key operator value' syntax, unknown
+// operators, parameters defined more than once in the same section or in
+// different sections valid for the running system and missing mandatory
+// entries of the [version] section.
+// Parameters defined in more than one section of the same name valid for
+// the running system and the deprecated old style version section are
+// reported as warnings.
+// sections are the section names valid for the type of the definition file.
+// The found entries are returned for further checks of the parameter
+// values, which are depending on the section.
+func LintINI(fileName, input string, sections []string) *LintResult {
+	res := &LintResult{Problems: []LintProblem{}, Entries: []LintEntry{}, blckCnt: blckCnt, blockDev: blockDev}
+	chkVersEntries := map[string]bool{"missing": false, "found": false, "isNew": false, "isOld": false, "skip": false, "mandVers": false, "mandDate": false, "mandDesc": false, "mandRefs": false}
+	// key - line and section header of the first definition
+	defined := make(map[string]LintEntry)
+	// parameter name - first definition regardless of the section
+	definedParam := make(map[string]LintEntry)
+	versLine := 0
+	curSection := ""
+	curHeader := ""
+	active := false
+	skipSection := false
+	bdevs := []string{}
+	for lnr, line := range strings.Split(input, "\n") {
+		lnr = lnr + 1
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if curSection == "version" && line[0] != '[' {
+			chkVersEntriesSyntax(line, chkVersEntries)
+			chkVersEntries["skip"] = false
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = system.StripComment(line, `\s#[^#]|"\s#[^#]`)
+		if line[0] == '[' {
+			skipSection = false
+			curSection, curHeader, active, bdevs = lintSectionHeader(res, fileName, lnr, line, sections)
+			if curSection == "" {
+				skipSection = true
+				continue
+			}
+			if curSection == "version" {
+				chkVersEntries["found"] = true
+				versLine = lnr
+			}
+			continue
+		}
+		if skipSection {
+			continue
+		}
+		if curSection == "" {
+			res.addProblem(fileName, lnr, "", "line '%s' is not part of a section", line)
+			continue
+		}
+		entry, ok := lintEntryLine(res, fileName, lnr, curSection, line)
+		if !ok {
+			continue
+		}
+		entry.Header = curHeader
+		entry.Active = active
+		entry.BlockDevs = bdevs
+		res.Entries = append(res.Entries, entry)
+		if entry.Key == "" {
+			continue
+		}
+		if first, found := definedParam[entry.Key]; found && first.Section != curSection && first.Active && active {
+			res.addProblem(fileName, lnr, curSection, "parameter '%s' is already defined in line %d in section [%s]. The parameters of a Note are handled by name, so a parameter can only be used in one section", entry.Key, first.Line, first.Header)
+		} else if !found || (!first.Active && active) {
+			definedParam[entry.Key] = entry
+		}
+		defKey := curSection + ":" + entry.Key
+		if first, found := defined[defKey]; found {
+			if first.Header == curHeader {
+				res.addProblem(fileName, lnr, curSection, "parameter '%s' is already defined in line %d of the same section", entry.Key, first.Line)
+			} else if first.Active && active {
+				res.addWarning(fileName, lnr, curSection, "parameter '%s' is already defined in line %d in section [%s]. Both sections are valid for this system, so the later definition wins", entry.Key, first.Line, first.Header)
+			}
+			continue
+		}
+		defined[defKey] = entry
+	}
+	reasons, warnings := lintVersEntries(chkVersEntries)
+	for _, reason := range reasons {
+		res.addProblem(fileName, versLine, "version", "%s", reason)
+	}
+	for _, warning := range warnings {
+		res.addWarning(fileName, versLine, "version", "%s", warning)
+	}
+	return res
+}
+
+// lintSectionHeader checks a section header line and its tags.
+// It returns the section name, the complete header, if the section tags
+// match the running system and the block devices valid for the section.
+// An empty section name is returned for an invalid section, whose lines
+// should be skipped
+func lintSectionHeader(res *LintResult, fileName string, lnr int, line string, sections []string) (string, string, bool, []string) {
+	if !strings.HasSuffix(line, "]") {
+		res.addProblem(fileName, lnr, "", "malformed section header '%s', missing closing bracket", line)
+		return "", "", false, nil
+	}
+	header := line[1 : len(line)-1]
+	if header == "" {
+		res.addProblem(fileName, lnr, "", "empty section definition '[]'")
+		return "", "", false, nil
+	}
+	sectionFields := strings.Split(header, ":")
+	section := sectionFields[0]
+	known := false
+	for _, sect := range sections {
+		if sect == section {
+			known = true
+		}
+	}
+	if !known {
+		res.addProblem(fileName, lnr, "", "unknown section '[%s]', valid sections are: %s", section, strings.Join(sections, ", "))
+		return "", "", false, nil
+	}
+	res.blckCnt, res.blockDev = blockDevCollect(sectionFields, res.blockDev, res.blckCnt)
+	if len(sectionFields) == 1 {
+		return section, header, true, res.blockDev
+	}
+	tagsOK := true
+	for _, secTag := range sectionFields[1:] {
+		if secTag == "" {
+			continue
+		}
+		if reason := lintSectionTag(secTag); reason != "" {
+			res.addProblem(fileName, lnr, section, "%s", reason)
+			tagsOK = false
+		}
+	}
+	if !tagsOK {
+		// the section will never be used, so skip the checks of
+		// the section lines
+		return "", "", false, nil
+	}
+	active, bdevs := chkSecTags(sectionFields, res.blockDev)
+	return section, header, active, bdevs
+}
+
+// lintSectionTag checks the syntax of a section tag and if the tag is able
+// to match at all. It returns the reason, if the tag is not valid
+func lintSectionTag(secTag string) string {
+	tagField := strings.Split(secTag, "=")
+	if len(tagField) != 2 || tagField[0] == "" {
+		return fmt.Sprintf("wrong syntax of section tag '%s', expected 'tag=value'", secTag)
+	}
+	tag := tagField[0]
+	val := tagField[1]
+	if val == "" {
+		return fmt.Sprintf("empty value for section tag '%s'", tag)
+	}
+	switch tag {
+	case "os":
+		if !lintOsTag.MatchString(val) {
+			return fmt.Sprintf("section tag 'os=%s' can never match, unsupported os version or wrong syntax", val)
+		}
+		if strings.Contains(val, "[-]") || strings.Contains(val, ",-,") || strings.Contains(val, "[-,") || strings.Contains(val, ",-]") {
+			return fmt.Sprintf("section tag 'os=%s' contains an empty release range '-'", val)
+		}
+	case "arch":
+		if !IsInList(val, lintArchTags) {
+			return fmt.Sprintf("section tag 'arch=%s' can never match, supported architectures are: %s", val, strings.Join(lintArchTags, ", "))
+		}
+	case "csp":
+		if !IsInList(val, lintCSPTags) {
+			return fmt.Sprintf("section tag 'csp=%s' can never match, supported cloud service providers are: %s", val, strings.Join(lintCSPTags, ", "))
+		}
+	case "kernel", "vendor", "model", "blkvendor", "blkmodel", "blkpat":
+		if _, err := regexp.Compile(val); err != nil {
+			return fmt.Sprintf("section tag '%s=%s' is not a valid regular expression - %v", tag, val, err)
+		}
+	case "virt", "pmu_name":
+		// values depend on the system
+	case "note":
+		// Note parameter overrides of a solution definition, checked
+		// by the solution lint
+	default:
+		if _, err := os.Stat(fmt.Sprintf("%s/%s", system.DmiID, tag)); err != nil {
+			return fmt.Sprintf("unknown section tag '%s', no such file in '%s'", tag, system.DmiID)
+		}
+	}
+	return ""
+}
+
+// lintEntryLine checks a parameter line and returns the resulting entry.
+// The second return value is false, if the line is not valid
+func lintEntryLine(res *LintResult, fileName string, lnr int, section, line string) (LintEntry, bool) {
+	entry := LintEntry{Line: lnr}
+	entry.Section = section
+	switch section {
+	case "reminder":
+		res.addProblem(fileName, lnr, section, "line '%s' is not a comment. Section [reminder] only supports lines starting with '#'", line)
+		return entry, false
+	case "rpm":
+		fields := strings.Fields(line)
+		if len(fields) < 1 || len(fields) > 3 || (len(fields) == 1 && !strings.HasPrefix(fields[0], "?")) {
+			res.addProblem(fileName, lnr, section, "wrong syntax '%s', expected 'package version' or '?package'", line)
+			return entry, false
+		}
+		entry.Key = "rpm:" + fields[0]
+		entry.Value = fields[len(fields)-1]
+		return entry, true
+	case "ArchX86", "ArchPPC64LE":
+		entry.Value = strings.Join(strings.Fields(line), "\t")
+		return entry, true
+	}
+	if param := regKey.FindStringSubmatch(line); len(param) > 0 && strings.Contains(param[1], "/") {
+		res.addProblem(fileName, lnr, section, "unsupported character '/' in parameter name of line '%s'", line)
+		return entry, false
+	}
+	kov := RegexKeyOperatorValue.FindStringSubmatch(line)
+	if kov != nil && !strings.HasPrefix(line, kov[1]) {
+		res.addProblem(fileName, lnr, section, "invalid parameter name in line '%s'", line)
+		return entry, false
+	}
+	if section == "grub" || section == "sys" || section == "service" {
+		// support single options
+		kov = splitSectLine(section, line, kov)
+	}
+	if kov == nil {
+		res.addProblem(fileName, lnr, section, "line '%s' is not a valid 'parameter operator value' entry", line)
+		return entry, false
+	}
+	if !IsInList(kov[2], lintOperators) {
+		res.addProblem(fileName, lnr, section, "unknown operator '%s' in line '%s'", kov[2], line)
+		return entry, false
+	}
+	entry.Key = kov[1]
+	entry.Operator = Operator(kov[2])
+	entry.Value = kov[3]
+	return entry, true
+}
+
+// lintVersEntries returns the problems and the warnings found in the
+// [version] section regarding the result of the version section entries
+// check (see evalVersEntries)
+func lintVersEntries(chkVents map[string]bool) ([]string, []string) {
+	reasons := []string{}
+	warnings := []string{}
+	noVersion, isNew, missing := evalVersEntries(chkVents)
+	if !chkVents["found"] {
+		return append(reasons, "missing version section"), warnings
+	}
+	if noVersion {
+		return append(reasons, "version section does not contain any of the mandatory entries VERSION, DATE, DESCRIPTION, REFERENCES"), warnings
+	}
+	if len(missing) != 0 {
+		if chkVents["isOld"] {
+			reasons = append(reasons, "version section mismatch - old and (partial) new style version header found")
+		}
+		reasons = append(reasons, fmt.Sprintf("missing mandatory entries in version section: %s", strings.Join(missing, ", ")))
+	}
+	if !isNew && chkVents["isOld"] {
+		warnings = append(warnings, "old style version section syntax is deprecated. Please adapt")
+	}
+	return reasons, warnings
+}
+
+// IsInList returns true, if the value is part of the list
+func IsInList(val string, list []string) bool {
+	for _, entry := range list {
+		if entry == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package txtparser
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var lintSections = []string{"version", "sysctl", "block", "reminder"}
+
+var lintExample = `# comment
+[version]
+VERSION=1
+DATE=01.01.2024
+DESCRIPTION=lint test
+
+[sysctl]
+vm.dirty_ratio = 10
+vm.dirty_ratio = 20
+kernel.shmmni ~ 4096
+no valid line
+
+[sysctl:arch=s390x]
+vm.swappiness = 10
+
+[unknown]
+key = value
+
+[block
+[reminder]
+not a comment
+`
+
+func TestLintINI(t *testing.T) {
+	res := LintINI("/tmp/lint.conf", lintExample, lintSections)
+	expected := []string{
+		"/tmp/lint.conf:2: [version] missing mandatory entries in version section: REFERENCES",
+		"/tmp/lint.conf:9: [sysctl] parameter 'vm.dirty_ratio' is already defined in line 8 of the same section",
+		"/tmp/lint.conf:10: [sysctl] line 'kernel.shmmni ~ 4096' is not a valid 'parameter operator value' entry",
+		"/tmp/lint.conf:11: [sysctl] line 'no valid line' is not a valid 'parameter operator value' entry",
+		"/tmp/lint.conf:13: [sysctl] section tag 'arch=s390x' can never match, supported architectures are: x86_64, ppc64le",
+		"/tmp/lint.conf:16: unknown section '[unknown]', valid sections are: version, sysctl, block, reminder",
+		"/tmp/lint.conf:19: malformed section header '[block', missing closing bracket",
+		"/tmp/lint.conf:21: [reminder] line 'not a comment' is not a comment. Section [reminder] only supports lines starting with '#'",
+	}
+	got := map[string]bool{}
+	for _, prob := range res.Problems {
+		got[prob.String()] = true
+	}
+	for _, exp := range expected {
+		if !got[exp] {
+			t.Errorf("missing problem '%s', got:\n%v", exp, res.Problems)
+		}
+	}
+	if len(res.Problems) != len(expected) {
+		t.Errorf("expected %d problems, got %d: %v", len(expected), len(res.Problems), res.Problems)
+	}
+	if len(res.Entries) != 2 {
+		t.Errorf("expected 2 entries, got %d: %+v", len(res.Entries), res.Entries)
+	}
+
+	// valid file
+	res = LintINI("/tmp/ok.conf", "[version]\nVERSION=1\nDATE=01.01.2024\nDESCRIPTION=ok\nREFERENCES=https://me.sap.com\n[sysctl]\nvm.swappiness = 10\n", lintSections)
+	if len(res.Problems) != 0 {
+		t.Errorf("expected no problems, got: %v", res.Problems)
+	}
+	// missing version section
+	res = LintINI("/tmp/nov.conf", "[sysctl]\nvm.swappiness = 10\n", lintSections)
+	if len(res.Problems) != 1 || !strings.Contains(res.Problems[0].Reason, "missing version section") {
+		t.Errorf("expected missing version section, got: %v", res.Problems)
+	}
+}
+
+func TestLintINIParamInSections(t *testing.T) {
+	sections := []string{"version", "sysctl", "vm", "mem"}
+	version := "[version]\nVERSION=1\nDATE=01.01.2024\nDESCRIPTION=sections\nREFERENCES=https://me.sap.com\n"
+	// section, which does not match the running system
+	otherArch := "ppc64le"
+	if runtime.GOARCH == "ppc64le" {
+		otherArch = "x86_64"
+	}
+	res := LintINI("/tmp/sect.conf", version+"[vm:arch="+otherArch+"]\nvm.swappiness = 1\n[sysctl]\nvm.swappiness = 10\n[mem]\nvm.swappiness = 20\n", sections)
+	expected := "/tmp/sect.conf:11: [mem] parameter 'vm.swappiness' is already defined in line 9 in section [sysctl]. The parameters of a Note are handled by name, so a parameter can only be used in one section"
+	if len(res.Problems) != 1 || res.Problems[0].String() != expected || res.Problems[0].Warning {
+		t.Errorf("expected '%s', got: %v", expected, res.Problems)
+	}
+}
+
+func TestLintSectionTag(t *testing.T) {
+	for tag, valid := range map[string]bool{"arch=x86_64": true, "arch=arm": false, "csp=azure": true, "csp=foo": false, "os=15-SP5": true, "os=abc": false, "vendor=[": false, "vendor=HP.*": true, "novalue": false, "virt=kvm": true} {
+		reason := lintSectionTag(tag)
+		if valid && reason != "" {
+			t.Errorf("tag '%s' should be valid, got '%s'", tag, reason)
+		}
+		if !valid && reason == "" {
+			t.Errorf("tag '%s' should be invalid", tag)
+		}
+	}
+}
+
+func TestLintINIFile(t *testing.T) {
+	if _, err := LintINIFile(fileNotExist, lintSections); err == nil {
+		t.Error("expected an error for a not existing file")
+	}
+}
+
+func TestLintINIBlockDevs(t *testing.T) {
+	blckCntOrg, blockDevOrg := blckCnt, blockDev
+	defer func() { blckCnt, blockDev = blckCntOrg, blockDevOrg }()
+	blckCnt, blockDev = 0, []string{}
+	_ = LintINI("/tmp/blk.conf", "[block]\nIO_SCHEDULER = noop\n[block:blkvendor=NOVENDOR]\nNRREQ = 1024\n", lintSections)
+	if blckCnt != 0 || len(blockDev) != 0 {
+		t.Errorf("block device information of ParseINI changed by the lint run - '%d', '%v'", blckCnt, blockDev)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -34,6 +35,8 @@ func StoreSectionInfo(obj interface{}, file, ID string, overwriteExisting bool)
 		iniFileName = fmt.Sprintf("%s/over_%s.run", saptuneSectionDir, ID)
 	case "del":
 		iniFileName = fmt.Sprintf("%s/del_%s.run", saptuneSectionDir, ID)
+	case "solov":
+		iniFileName = fmt.Sprintf("%s/solov_%s.run", saptuneSectionDir, ID)
 	case "vend":
 		iniFileName = fmt.Sprintf("%s/%s_%s.run", saptuneSectionDir, file, ID)
 	case "angi":
@@ -98,14 +101,16 @@ func GetSectionInfo(initype, ID string, fileSelect bool) (*INIFile, error) {
 }
 
 // GetOverrides is looking for an override file and parse the content
+// The drop-in override files of the Note (ID.d/*.conf) are merged on top of
+// the override file
 // func GetOverrides(filetype, ID string) (bool, bool, *INIFile) {
 func GetOverrides(filetype, ID string) (bool, *INIFile) {
 	override := false
 	//runtime := false
 	ow, err := GetSectionInfo(filetype, ID, false)
 	if err != nil {
-		// Parse the override file
-		ow, err = ParseINIFile(path.Join(OverrideTuningSheets, ID), false)
+		// Parse the override file and the drop-in override files
+		ow, err = ParseOverrides(OverrideTuningSheets, ID)
 		if err == nil {
 			// write section data to section runtime file
 			_ = StoreSectionInfo(ow, filetype, ID, true)
@@ -119,6 +124,194 @@ func GetOverrides(filetype, ID string) (bool, *INIFile) {
 	return override, ow
 }
 
+// GetOverrideDropIns returns the drop-in override files of a Note
+// (<override dir>/ID.d/*.conf) in lexical order
+func GetOverrideDropIns(ovDir, ID string) []string {
+	files, _ := filepath.Glob(path.Join(ovDir, ID+".d", "*.conf"))
+	sort.Strings(files)
+	return files
+}
+
+// ParseOverrides parses the override file of a Note and merges the drop-in
+// override files in lexical order on top of it. A later drop-in file
+// overrides the parameters of the override file and of the former drop-in
+// files. Each entry records the file, which supplied the value
+func ParseOverrides(ovDir, ID string) (*INIFile, error) {
+	ovFile := path.Join(ovDir, ID)
+	dropIns := GetOverrideDropIns(ovDir, ID)
+	ow, err := ParseINIFile(ovFile, false)
+	if err != nil {
+		if len(dropIns) == 0 {
+			return nil, err
+		}
+		ow = &INIFile{
+			AllValues: make([]INIEntry, 0, 64),
+			KeyValue:  make(map[string]map[string]INIEntry),
+		}
+	}
+	setINISource(ow, ovFile)
+	for _, dropIn := range dropIns {
+		dow, err := ParseINIFile(dropIn, false)
+		if err != nil {
+			system.ErrorLog("Problems reading override drop-in file '%s' - %v", dropIn, err)
+			continue
+		}
+		setINISource(dow, dropIn)
+		mergeINIFile(ow, dow)
+	}
+	return ow, nil
+}
+
+// ParseSolutionNoteOverrides reads the Note parameter overrides of a
+// solution definition or solution override file. These are sections of a
+// Note definition with the additional tag 'note=<NoteID>', e.g.
+// [sysctl:note=1984787]. The other tags of the sections are evaluated as
+// usual. The overrides are returned by NoteID, each entry records the
+// solution file as source
+func ParseSolutionNoteOverrides(fileName string) (map[string]*INIFile, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	noteSects := make(map[string][]string)
+	noteOrder := []string{}
+	noteID := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			noteID = ""
+			fields := []string{}
+			for _, field := range strings.Split(line[1:len(line)-1], ":") {
+				if strings.HasPrefix(field, "note=") {
+					noteID = strings.TrimPrefix(field, "note=")
+					continue
+				}
+				fields = append(fields, field)
+			}
+			if noteID == "" {
+				continue
+			}
+			if _, ok := noteSects[noteID]; !ok {
+				noteOrder = append(noteOrder, noteID)
+			}
+			line = "[" + strings.Join(fields, ":") + "]"
+		}
+		if noteID != "" {
+			noteSects[noteID] = append(noteSects[noteID], line)
+		}
+	}
+	overrides := make(map[string]*INIFile)
+	for _, noteID := range noteOrder {
+		ow := ParseINI(strings.Join(noteSects[noteID], "\n"))
+		setINISource(ow, fileName)
+		overrides[noteID] = ow
+	}
+	return overrides, nil
+}
+
+// MergeSolutionOverrides merges the Note parameter overrides of a solution
+// with the overrides of the Note (override file and drop-in files).
+// The overrides of the solution definition (defaults) rank below the
+// overrides of the Note, the overrides of the solution override file
+// (overrides) rank above. Each of the arguments may be nil, nil is returned
+// if there are no overrides at all. The arguments are not modified
+func MergeSolutionOverrides(ow, defaults, overrides *INIFile) *INIFile {
+	if ow == nil && defaults == nil && overrides == nil {
+		return nil
+	}
+	merged := &INIFile{
+		AllValues: make([]INIEntry, 0, 64),
+		KeyValue:  make(map[string]map[string]INIEntry),
+	}
+	if defaults != nil {
+		merged = copyINIFile(defaults)
+	}
+	if ow != nil {
+		mergeINIFile(merged, ow)
+	}
+	if overrides != nil {
+		mergeINIFile(merged, overrides)
+	}
+	return merged
+}
+
+// SolutionOverridesChanged checks, if the Note parameter overrides of the
+// solution differ from the ones, which were used for the stored override
+// information of the Note. If yes, the stored override information of the
+// Note is removed, so that it is read again, and the new Note parameter
+// overrides of the solution are stored instead
+func SolutionOverridesChanged(ID string, defaults, overrides *INIFile) bool {
+	solovFile := fmt.Sprintf("%s/solov_%s.run", saptuneSectionDir, ID)
+	stored, err := os.ReadFile(solovFile)
+	if defaults == nil && overrides == nil {
+		if err != nil {
+			return false
+		}
+		_ = os.Remove(solovFile)
+	} else {
+		content, _ := json.Marshal([]*INIFile{defaults, overrides})
+		if err == nil && string(stored) == string(content) {
+			return false
+		}
+		_ = StoreSectionInfo([]*INIFile{defaults, overrides}, "solov", ID, true)
+	}
+	_ = os.Remove(fmt.Sprintf("%s/over_%s.run", saptuneSectionDir, ID))
+	return true
+}
+
+// copyINIFile returns a copy of an INIFile
+func copyINIFile(ini *INIFile) *INIFile {
+	cp := &INIFile{
+		AllValues: append(make([]INIEntry, 0, len(ini.AllValues)), ini.AllValues...),
+		KeyValue:  make(map[string]map[string]INIEntry),
+	}
+	for section, entries := range ini.KeyValue {
+		cp.KeyValue[section] = make(map[string]INIEntry)
+		for key, entry := range entries {
+			cp.KeyValue[section][key] = entry
+		}
+	}
+	return cp
+}
+
+// setINISource sets the source file of all entries of an INIFile
+func setINISource(ini *INIFile, source string) {
+	for idx := range ini.AllValues {
+		ini.AllValues[idx].Source = source
+	}
+	for section, entries := range ini.KeyValue {
+		for key, entry := range entries {
+			entry.Source = source
+			ini.KeyValue[section][key] = entry
+		}
+	}
+}
+
+// mergeINIFile merges the entries of 'over' into 'ini'. Existing entries
+// are replaced at their position, new entries are appended
+func mergeINIFile(ini, over *INIFile) {
+	for _, entry := range over.AllValues {
+		replaced := false
+		for idx, iniEntry := range ini.AllValues {
+			if iniEntry.Section == entry.Section && iniEntry.Key == entry.Key {
+				ini.AllValues[idx] = entry
+				replaced = true
+			}
+		}
+		if !replaced {
+			ini.AllValues = append(ini.AllValues, entry)
+		}
+	}
+	for section, entries := range over.KeyValue {
+		if ini.KeyValue[section] == nil {
+			ini.KeyValue[section] = make(map[string]INIEntry)
+		}
+		for key, entry := range entries {
+			ini.KeyValue[section][key] = entry
+		}
+	}
+}
+
 // readVersionSection read content of [version] section from config file
 func readVersionSection(fileName string) ([]string, bool, error) {
 	skipSection := false
@@ -257,12 +450,9 @@ func chkVersEntriesResult(fileName string, chkVents map[string]bool) error {
 	if strings.HasSuffix(fileName, ".sol") {
 		object = "Solution"
 	}
-	if !chkVents["mandVers"] && !chkVents["mandDate"] && !chkVents["mandRefs"] && !chkVents["mandDesc"] {
-		chkVents["isNew"] = false
-	} else {
-		chkVents["isNew"] = true
-	}
-	if (!chkVents["isNew"] && !chkVents["isOld"]) || !chkVents["found"] {
+	noVersion, isNew, missing := evalVersEntries(chkVents)
+	chkVents["isNew"] = isNew
+	if noVersion {
 		// missing version section
 		chkVents["missing"] = true
 		if missVersionCnt[fileName] < 1 {
@@ -272,7 +462,7 @@ func chkVersEntriesResult(fileName string, chkVents map[string]bool) error {
 			err = fmt.Errorf("1")
 		}
 	}
-	if chkVents["isNew"] && (!chkVents["mandVers"] || !chkVents["mandDate"] || !chkVents["mandRefs"] || !chkVents["mandDesc"]) {
+	if len(missing) != 0 {
 		// wrong version section
 		chkVents["missing"] = true
 		if missVersionCnt[fileName] < 1 {
@@ -287,6 +477,25 @@ func chkVersEntriesResult(fileName string, chkVents map[string]bool) error {
 	return err
 }
 
+// evalVersEntries evaluates the result of the version section entries check.
+// It returns, if the version section is missing, if it is a new style
+// version section and the missing mandatory fields of a new style version
+// section
+func evalVersEntries(chkVents map[string]bool) (bool, bool, []string) {
+	missing := []string{}
+	for _, ent := range []struct{ key, name string }{{"mandVers", "VERSION"}, {"mandDate", "DATE"}, {"mandDesc", "DESCRIPTION"}, {"mandRefs", "REFERENCES"}} {
+		if !chkVents[ent.key] {
+			missing = append(missing, ent.name)
+		}
+	}
+	isNew := len(missing) < 4
+	if !isNew {
+		missing = []string{}
+	}
+	noVersion := (!isNew && !chkVents["isOld"]) || !chkVents["found"]
+	return noVersion, isNew, missing
+}
+
 // GetINIFileVersionSectionEntry returns the field 'entryName' from the version
 // section of the Note configuration file
 func GetINIFileVersionSectionEntry(fileName, entryName string) string {
@@ -67,3 +67,177 @@ func TestStoreSectionInfo(t *testing.T) {
 func TestResetVersionSectCnts(t *testing.T) {
 	ResetVersionSectCnts("/staging/")
 }
+
+func TestParseOverrides(t *testing.T) {
+	ovDir, err := os.MkdirTemp("", "saptune_ovtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(ovDir)
+	ovFile := path.Join(ovDir, "4711")
+	dropIn1 := path.Join(ovDir, "4711.d", "10-first.conf")
+	dropIn2 := path.Join(ovDir, "4711.d", "20-second.conf")
+
+	// neither override file nor drop-in files
+	if _, err := ParseOverrides(ovDir, "4711"); err == nil {
+		t.Error("expected an error for missing override files")
+	}
+	if dropIns := GetOverrideDropIns(ovDir, "4711"); len(dropIns) != 0 {
+		t.Errorf("expected no drop-in files, got '%v'", dropIns)
+	}
+
+	_ = os.WriteFile(ovFile, []byte("[sysctl]\nvm.swappiness = 10\nkernel.shmmni = 4096\n"), 0644)
+	_ = os.MkdirAll(path.Join(ovDir, "4711.d"), 0755)
+	_ = os.WriteFile(dropIn2, []byte("[sysctl]\nvm.swappiness = 30\n"), 0644)
+	_ = os.WriteFile(dropIn1, []byte("[sysctl]\nvm.swappiness = 20\nvm.max_map_count = 2147483647\n"), 0644)
+	_ = os.WriteFile(path.Join(ovDir, "4711.d", "30-ignored.txt"), []byte("[sysctl]\nvm.swappiness = 40\n"), 0644)
+
+	dropIns := GetOverrideDropIns(ovDir, "4711")
+	if !reflect.DeepEqual(dropIns, []string{dropIn1, dropIn2}) {
+		t.Errorf("got: '%v', expected: '%v'", dropIns, []string{dropIn1, dropIn2})
+	}
+	ow, err := ParseOverrides(ovDir, "4711")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][2]string{
+		"vm.swappiness":    {"30", dropIn2},
+		"kernel.shmmni":    {"4096", ovFile},
+		"vm.max_map_count": {"2147483647", dropIn1},
+	}
+	if len(ow.AllValues) != len(expected) {
+		t.Errorf("got %d entries, expected %d - '%+v'", len(ow.AllValues), len(expected), ow.AllValues)
+	}
+	if ow.AllValues[0].Key != "vm.swappiness" {
+		t.Errorf("merged entry not replaced in place - '%+v'", ow.AllValues)
+	}
+	for _, entry := range ow.AllValues {
+		exp := expected[entry.Key]
+		if entry.Value != exp[0] || entry.Source != exp[1] {
+			t.Errorf("entry '%s': got '%s' from '%s', expected '%s' from '%s'", entry.Key, entry.Value, entry.Source, exp[0], exp[1])
+		}
+		if kv := ow.KeyValue["sysctl"][entry.Key]; kv.Value != exp[0] || kv.Source != exp[1] {
+			t.Errorf("KeyValue entry '%s': got '%s' from '%s', expected '%s' from '%s'", entry.Key, kv.Value, kv.Source, exp[0], exp[1])
+		}
+	}
+
+	// only drop-in files
+	os.Remove(ovFile)
+	ow, err = ParseOverrides(ovDir, "4711")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ow.AllValues) != 2 || ow.KeyValue["sysctl"]["vm.swappiness"].Value != "30" {
+		t.Errorf("unexpected result for drop-in files only - '%+v'", ow.AllValues)
+	}
+}
+
+func TestSolutionNoteOverrides(t *testing.T) {
+	ovDir, err := os.MkdirTemp("", "saptune_solovtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(ovDir)
+	solFile := path.Join(ovDir, "MYSOL.sol")
+	solOvFile := path.Join(ovDir, "override", "MYSOL.sol")
+	ovFile := path.Join(ovDir, "4711")
+	_ = os.MkdirAll(path.Join(ovDir, "override"), 0755)
+	_ = os.WriteFile(solFile, []byte("[version]\nVERSION=1\n[ArchX86]\n4711 4712\n[sysctl:note=4711]\nvm.swappiness = 10\nkernel.shmmni = 4096\n[vm:note=4712]\nTHP = never\n[sysctl:note=4711:os=99]\nvm.swappiness = 99\n"), 0644)
+	_ = os.WriteFile(solOvFile, []byte("[sysctl:note=4711]\nvm.max_map_count = 2147483647\n"), 0644)
+
+	defaults, err := ParseSolutionNoteOverrides(solFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defaults) != 2 || defaults["4711"].KeyValue["sysctl"]["vm.swappiness"].Value != "10" || defaults["4712"].KeyValue["vm"]["THP"].Value != "never" {
+		t.Errorf("unexpected Note parameter overrides '%+v'", defaults)
+	}
+	if defaults["4711"].KeyValue["sysctl"]["vm.swappiness"].Source != solFile {
+		t.Errorf("unexpected source '%s'", defaults["4711"].KeyValue["sysctl"]["vm.swappiness"].Source)
+	}
+	// the Note parameter overrides are ignored in the solution definition
+	solINI, _ := ParseINIFile(solFile, false)
+	if len(solINI.AllValues) != 2 {
+		t.Errorf("unexpected solution definition '%+v'", solINI.AllValues)
+	}
+	overrides, err := ParseSolutionNoteOverrides(solOvFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseSolutionNoteOverrides(path.Join(ovDir, "NOSOL.sol")); err == nil {
+		t.Error("expected an error for a missing solution file")
+	}
+
+	// precedence - solution definition < override file < solution override
+	_ = os.WriteFile(ovFile, []byte("[sysctl]\nkernel.shmmni = 8192\nvm.max_map_count = 65530\n"), 0644)
+	nowv, err := ParseOverrides(ovDir, "4711")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ow := MergeSolutionOverrides(nowv, defaults["4711"], overrides["4711"])
+	expected := map[string][2]string{
+		"vm.swappiness":    {"10", solFile},
+		"kernel.shmmni":    {"8192", ovFile},
+		"vm.max_map_count": {"2147483647", solOvFile},
+	}
+	if len(ow.AllValues) != len(expected) {
+		t.Errorf("got %d entries, expected %d - '%+v'", len(ow.AllValues), len(expected), ow.AllValues)
+	}
+	for _, entry := range ow.AllValues {
+		exp := expected[entry.Key]
+		if entry.Value != exp[0] || entry.Source != exp[1] {
+			t.Errorf("entry '%s': got '%s' from '%s', expected '%s' from '%s'", entry.Key, entry.Value, entry.Source, exp[0], exp[1])
+		}
+	}
+	if defaults["4711"].KeyValue["sysctl"]["kernel.shmmni"].Value != "4096" || nowv.KeyValue["sysctl"]["vm.max_map_count"].Value != "65530" {
+		t.Error("overrides modified by merge")
+	}
+	// Note without override file
+	ow = MergeSolutionOverrides(nil, defaults["4712"], overrides["4712"])
+	if ow == nil || ow.KeyValue["vm"]["THP"].Value != "never" {
+		t.Errorf("unexpected overrides '%+v'", ow)
+	}
+	if MergeSolutionOverrides(nil, nil, nil) != nil {
+		t.Error("expected no overrides")
+	}
+}
+
+func TestSolutionOverridesChanged(t *testing.T) {
+	saptuneSectionDir = "/tmp/saptune_sections"
+	if err := os.MkdirAll(saptuneSectionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(saptuneSectionDir)
+	solDef := ParseINI("[sysctl]\nvm.swappiness = 10\n")
+	overRun := path.Join(saptuneSectionDir, "over_4711.run")
+	otherRun := path.Join(saptuneSectionDir, "over_4712.run")
+	_ = os.WriteFile(otherRun, []byte("{}"), 0644)
+
+	if SolutionOverridesChanged("4711", nil, nil) {
+		t.Error("no solution overrides reported as changed")
+	}
+	_ = os.WriteFile(overRun, []byte("{}"), 0644)
+	if !SolutionOverridesChanged("4711", solDef, nil) {
+		t.Error("new solution overrides not reported as changed")
+	}
+	if _, err := os.Stat(overRun); !os.IsNotExist(err) {
+		t.Error("stored override information of the changed Note not removed")
+	}
+	_ = os.WriteFile(overRun, []byte("{}"), 0644)
+	if SolutionOverridesChanged("4711", ParseINI("[sysctl]\nvm.swappiness = 10\n"), nil) {
+		t.Error("unchanged solution overrides reported as changed")
+	}
+	if _, err := os.Stat(overRun); err != nil {
+		t.Error("stored override information of the unchanged Note removed")
+	}
+	if !SolutionOverridesChanged("4711", nil, nil) {
+		t.Error("removed solution overrides not reported as changed")
+	}
+	if _, err := os.Stat(path.Join(saptuneSectionDir, "solov_4711.run")); !os.IsNotExist(err) {
+		t.Error("stored solution overrides not removed")
+	}
+	if _, err := os.Stat(otherRun); err != nil {
+		t.Error("stored override information of other Notes removed")
+	}
+
+}
@@ -6,6 +6,8 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/SUSE/saptune/system"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -22,9 +24,13 @@ type SysconfigEntry struct {
 
 // Sysconfig contains key-value pairs of a sysconfig file.
 // It is able to convert back to original text in the original key order.
+// The key-value pairs of drop-in files override the values of the sysconfig
+// file, but are not converted back to text.
 type Sysconfig struct {
-	AllValues []*SysconfigEntry // All key-value pairs in the orignal order.
-	KeyValue  map[string]*SysconfigEntry
+	AllValues    []*SysconfigEntry // All key-value pairs in the orignal order.
+	KeyValue     map[string]*SysconfigEntry
+	DropInValues map[string]*SysconfigEntry // key-value pairs of the drop-in files
+	DropInFiles  map[string]string          // drop-in file, which supplied the value of a key
 }
 
 // ParseSysconfigFile read sysconfig file and parse the file content into
@@ -37,11 +43,27 @@ func ParseSysconfigFile(fileName string, autoCreate bool) (*Sysconfig, error) {
 	return ParseSysconfig(string(content))
 }
 
+// ParseSysconfigFileWithDropIns read sysconfig file and merge the drop-in
+// files '*.conf' of the directory dropInDir over the content of the file.
+// Keys listed in excludeKeys are not allowed in the drop-in files.
+func ParseSysconfigFileWithDropIns(fileName, dropInDir string, autoCreate bool, excludeKeys []string) (*Sysconfig, error) {
+	conf, err := ParseSysconfigFile(fileName, autoCreate)
+	if err != nil {
+		return nil, err
+	}
+	if err := conf.MergeDropIns(dropInDir, excludeKeys); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
 // ParseSysconfig read sysconfig text and parse the text into memory structures.
 func ParseSysconfig(input string) (*Sysconfig, error) {
 	conf := &Sysconfig{
-		AllValues: make([]*SysconfigEntry, 0),
-		KeyValue:  make(map[string]*SysconfigEntry),
+		AllValues:    make([]*SysconfigEntry, 0),
+		KeyValue:     make(map[string]*SysconfigEntry),
+		DropInValues: make(map[string]*SysconfigEntry),
+		DropInFiles:  make(map[string]string),
 	}
 	leadingComments := make([]string, 0)
 	for _, line := range strings.Split(input, "\n") {
@@ -72,6 +94,55 @@ func ParseSysconfig(input string) (*Sysconfig, error) {
 	return conf, nil
 }
 
+// MergeDropIns reads the drop-in files '*.conf' of the directory dropInDir
+// in lexical order and merges their key-value pairs over the values of the
+// sysconfig file. A later drop-in file overrides the values of the former
+// ones. Keys listed in excludeKeys are ignored with a warning.
+// A missing directory is not an error.
+func (conf *Sysconfig) MergeDropIns(dropInDir string, excludeKeys []string) error {
+	excluded := make(map[string]bool)
+	for _, key := range excludeKeys {
+		excluded[key] = true
+	}
+	dropIns, err := filepath.Glob(filepath.Join(dropInDir, "*.conf"))
+	if err != nil {
+		return err
+	}
+	for _, dropIn := range dropIns {
+		content, err := os.ReadFile(dropIn)
+		if err != nil {
+			return err
+		}
+		dropInConf, _ := ParseSysconfig(string(content))
+		for _, kv := range dropInConf.AllValues {
+			if excluded[kv.Key] {
+				system.WarningLog("variable '%s' is maintained by saptune and not allowed in drop-in file '%s', ignoring it", kv.Key, dropIn)
+				continue
+			}
+			system.DebugLog("variable '%s' set to '%s' by drop-in file '%s'", kv.Key, kv.Value, dropIn)
+			conf.DropInValues[kv.Key] = kv
+			conf.DropInFiles[kv.Key] = dropIn
+		}
+	}
+	return nil
+}
+
+// DropInFile returns the drop-in file, which supplied the value of the key,
+// or an empty string, if the value is not set by a drop-in file.
+func (conf *Sysconfig) DropInFile(key string) string {
+	return conf.DropInFiles[key]
+}
+
+// entry returns the key-value pair of a key. The value of a drop-in file
+// wins over the value of the sysconfig file.
+func (conf *Sysconfig) entry(key string) (*SysconfigEntry, bool) {
+	if entry, exists := conf.DropInValues[key]; exists {
+		return entry, true
+	}
+	entry, exists := conf.KeyValue[key]
+	return entry, exists
+}
+
 // Set value for a key. If the key does not yet exist, it is created.
 func (conf *Sysconfig) Set(key string, value interface{}) {
 	kv, exists := conf.KeyValue[key]
@@ -108,7 +179,7 @@ func (conf *Sysconfig) SetStrArray(key string, values []string) {
 // GetInt return integer value that belongs to the key, or the default value
 // if the key does not exist or value is not an integer.
 func (conf *Sysconfig) GetInt(key string, defaultValue int) int {
-	entry, exists := conf.KeyValue[key]
+	entry, exists := conf.entry(key)
 	if !exists {
 		return defaultValue
 	}
@@ -122,7 +193,7 @@ func (conf *Sysconfig) GetInt(key string, defaultValue int) int {
 // GetUint64 return uint64 value that belongs to the key, or the default value
 // if the key does not exist or value is not an integer.
 func (conf *Sysconfig) GetUint64(key string, defaultValue uint64) uint64 {
-	entry, exists := conf.KeyValue[key]
+	entry, exists := conf.entry(key)
 	if !exists {
 		return defaultValue
 	}
@@ -136,7 +207,7 @@ func (conf *Sysconfig) GetUint64(key string, defaultValue uint64) uint64 {
 // GetString return string value that belongs to the key, or the default value
 // if the key does not exist.
 func (conf *Sysconfig) GetString(key, defaultValue string) string {
-	entry, exists := conf.KeyValue[key]
+	entry, exists := conf.entry(key)
 	if !exists || strings.TrimSpace(entry.Value) == "" {
 		return defaultValue
 	}
@@ -146,7 +217,7 @@ func (conf *Sysconfig) GetString(key, defaultValue string) string {
 // GetStringArray assume the key carries a space-separated array value,
 // return the value array.
 func (conf *Sysconfig) GetStringArray(key string, defaultValue []string) (ret []string) {
-	entry, exists := conf.KeyValue[key]
+	entry, exists := conf.entry(key)
 	if !exists {
 		return defaultValue
 	}
@@ -163,7 +234,7 @@ func (conf *Sysconfig) GetStringArray(key string, defaultValue []string) (ret []
 // GetIntArray assume the key carries a space-separated array of integers,
 // return the array. Discard malformed integers.
 func (conf *Sysconfig) GetIntArray(key string, defaultValue []int) (ret []int) {
-	entry, exists := conf.KeyValue[key]
+	entry, exists := conf.entry(key)
 	if !exists {
 		return defaultValue
 	}
@@ -204,9 +275,9 @@ func (conf *Sysconfig) ToText() string {
 	return ret.String()
 }
 
-// IsKeyAvail return true, if the key is available.
-// false, if the key does not exist.
+// IsKeyAvail return true, if the key is available in the sysconfig file or
+// in a drop-in file. false, if the key does not exist.
 func (conf *Sysconfig) IsKeyAvail(key string) bool {
-	_, exists := conf.KeyValue[key]
+	_, exists := conf.entry(key)
 	return exists
 }
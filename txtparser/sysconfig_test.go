@@ -178,3 +178,50 @@ func TestSysconfig(t *testing.T) {
 		t.Error("failed to convert back into text")
 	}
 }
+
+func TestSysconfigDropIns(t *testing.T) {
+	tstDir := "/tmp/saptune_sysconfig_dropins"
+	defer os.RemoveAll(tstDir)
+	if err := os.MkdirAll(path.Join(tstDir, "conf.d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"sysconfig":          "# comment\nKEEP=\"file\"\nOVER=\"file\"\nSTATE=\"file\"\n",
+		"conf.d/10-a.conf":   "OVER=\"first\"\nNEW=\"first\"\n",
+		"conf.d/20-b.conf":   "# later drop-in wins\nNEW=\"second\"\nSTATE=\"dropin\"\n",
+		"conf.d/30-c.ignore": "KEEP=\"ignored\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(path.Join(tstDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conf, err := ParseSysconfigFileWithDropIns(path.Join(tstDir, "sysconfig"), path.Join(tstDir, "conf.d"), false, []string{"STATE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, exp := range map[string]string{"KEEP": "file", "OVER": "first", "NEW": "second", "STATE": "file"} {
+		if val := conf.GetString(key, ""); val != exp {
+			t.Errorf("key '%s': got '%s', expected '%s'", key, val, exp)
+		}
+	}
+	if !conf.IsKeyAvail("NEW") {
+		t.Error("key 'NEW' of drop-in not available")
+	}
+	if conf.DropInFile("NEW") != path.Join(tstDir, "conf.d/20-b.conf") || conf.DropInFile("OVER") != path.Join(tstDir, "conf.d/10-a.conf") || conf.DropInFile("KEEP") != "" || conf.DropInFile("STATE") != "" {
+		t.Errorf("wrong drop-in files '%+v'", conf.DropInFiles)
+	}
+	// drop-in values are not written back to the sysconfig file
+	conf.Set("KEEP", "changed")
+	if txt := conf.ToText(); txt != "# comment\nKEEP=\"changed\"\nOVER=\"file\"\nSTATE=\"file\"\n" {
+		t.Errorf("wrong text '%s'", txt)
+	}
+	// missing drop-in directory
+	conf, err = ParseSysconfigFileWithDropIns(path.Join(tstDir, "sysconfig"), path.Join(tstDir, "missing.d"), false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val := conf.GetString("OVER", ""); val != "file" || len(conf.DropInValues) != 0 {
+		t.Errorf("unexpected drop-in values '%+v'", conf.DropInValues)
+	}
+}
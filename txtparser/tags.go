@@ -65,6 +65,10 @@ func chkSecTags(secFields, blkDev []string) (bool, []string) {
 			ret = chkCPUTags(tagField[1], secFields)
 		case "kernel":
 			ret = chkKernelTags(tagField[1], secFields)
+		case "note":
+			// Note parameter overrides of a solution definition
+			// are read by ParseSolutionNoteOverrides
+			ret = false
 		default:
 			ret = chkOtherTags(tagField[0], tagField[1], secFields)
 		}